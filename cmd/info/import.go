@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// runImport is `info import` subcommand. It imports file synchronously and prints report
+//
+//	info import -mapping mapping.json -vet 2 -dry-run pets.csv
+//	info import -resume 15
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	isLocal := fs.Bool("local", false, "is it local? can make logs pretty")
+	isDebug := fs.Bool("debug", false, "enable debug logs")
+	format := fs.String("format", "", "csv or ndjson, by default taken from file extension")
+	mappingPath := fs.String("mapping", "", "path to column mapping json")
+	dryRun := fs.Bool("dry-run", false, "only validate rows and report what would be created")
+	vetID := fs.Uint("vet", 0, "vet of pets without card.vet_id column")
+	resume := fs.Uint("resume", 0, "id of failed import to continue from checkpoint")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: info import [flags] <file>\n       info import -resume <id>")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	log := logging.NewLogger(isLocal, isDebug)
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal("Failed to load config. ", err)
+	}
+
+	storage := storage.New(log, &cfg.Db)
+	defer storage.StorageProcess.Shutdown()
+
+	service := service.New(log, cfg, storage)
+
+	var jobID uint
+	if *resume != 0 {
+		jobID = *resume
+	} else {
+		if fs.NArg() != 1 {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		job := models.ImportJob{
+			Format:       *format,
+			DryRun:       *dryRun,
+			FilePath:     fs.Arg(0),
+			DefaultVetID: *vetID,
+		}
+		if job.Format == "" {
+			job.Format = models.ImportFormatByFilename(job.FilePath)
+		}
+		if *mappingPath != "" {
+			data, err := os.ReadFile(*mappingPath)
+			if err != nil {
+				log.Fatal("failed to read mapping: ", err)
+			}
+			if err := json.Unmarshal(data, &job.Mapping); err != nil {
+				log.Fatal("failed to parse mapping: ", err)
+			}
+		}
+
+		job, err = service.Import.CreateLocalImport(job)
+		if err != nil {
+			log.Fatal("failed to create import: ", err)
+		}
+		jobID = job.ID
+		log.Info("created import ", jobID)
+	}
+
+	job, runErr := service.Import.RunImport(jobID)
+
+	report, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		log.Fatal("failed to print report: ", err)
+	}
+	fmt.Println(string(report))
+
+	if runErr != nil {
+		log.Fatalf("import %d failed, resume it with `info import -resume %d`: %v", jobID, jobID, runErr)
+	}
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"flag"
+	"os"
 
 	"github.com/vet-clinic-back/info-service/internal/config"
 	"github.com/vet-clinic-back/info-service/internal/handlers"
 	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/mllp"
 	"github.com/vet-clinic-back/info-service/internal/server"
 	"github.com/vet-clinic-back/info-service/internal/service"
 	"github.com/vet-clinic-back/info-service/internal/storage"
@@ -22,6 +24,17 @@ import (
 // @in              header
 // @name            Authorization
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "mllp-send":
+			runMLLPSend(os.Args[2:])
+			return
+		}
+	}
+
 	isLocal := flag.Bool("local", false, "is it local? can make logs pretty")
 	idDebug := flag.Bool("debug", false, "is it local? can make logs pretty")
 	port := flag.String("port", "8080", "is it port? can make logs pretty")
@@ -41,7 +54,24 @@ func main() {
 	defer storage.StorageProcess.Shutdown()
 
 	log.Info("initializing service")
-	service := service.New(log, storage.Info)
+	service := service.New(log, cfg, storage)
+
+	if cfg.MLLP.Addr != "" {
+		log.Info("starting mllp listener on ", cfg.MLLP.Addr)
+		mllpServer := mllp.NewServer(log, service.Lab)
+		defer mllpServer.Shutdown()
+		go func() {
+			if err := mllpServer.ListenAndServe(cfg.MLLP.Addr); err != nil {
+				log.Fatal("mllp listener failed. ", err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log.Info("starting job scheduler")
+	go service.Scheduler.Run(ctx)
 
 	log.Info("initializing handler")
 	hander := handlers.NewHandler(log, service)
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/hl7"
+	"github.com/vet-clinic-back/info-service/internal/mllp"
+)
+
+// runMLLPSend is `info mllp-send` subcommand. It sends HL7 files to MLLP listener like analyzer does
+// and prints acknowledgements
+//
+//	info mllp-send -addr localhost:2575 examples/hl7/oru-r01.hl7
+func runMLLPSend(args []string) {
+	fs := flag.NewFlagSet("mllp-send", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:2575", "address of MLLP listener")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout of one message")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: info mllp-send [flags] <file.hl7>...")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := net.DialTimeout("tcp", *addr, *timeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect:", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	failed := false
+	for _, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to read message:", err)
+			os.Exit(1)
+		}
+		// files are usually saved with LF, HL7 segments are separated by CR
+		data = bytes.ReplaceAll(bytes.TrimSpace(data), []byte("\r\n"), []byte("\r"))
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r"))
+
+		ack, err := mllp.Send(conn, data, *timeout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, path+":", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%s:\n%s\n", path, strings.ReplaceAll(strings.TrimRight(string(ack), "\r"), "\r", "\n"))
+		if parsed, err := hl7.Parse(ack); err != nil {
+			failed = true
+		} else if code, _ := hl7.AckCode(parsed); code != hl7.AckAccept {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -2,8 +2,13 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 // use "github.com/ilyakaznacheev/cleanenv" to read yaml
@@ -12,7 +17,15 @@ import (
 //err := cleanenv.ReadConfig("config.yaml", config)
 
 type Config struct {
-	Db DbConfig `yaml:"db"`
+	Db          DbConfig          `yaml:"db"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Import      ImportConfig      `yaml:"import"`
+	Export      ExportConfig      `yaml:"export"`
+	Clinic      ClinicConfig      `yaml:"clinic"`
+	PDF         PDFConfig         `yaml:"pdf"`
+	MLLP        MLLPConfig        `yaml:"mllp"`
+	Notify      NotifyConfig      `yaml:"notify"`
+	FHIR        FHIRConfig        `yaml:"fhir"`
 }
 
 type DbConfig struct {
@@ -23,6 +36,79 @@ type DbConfig struct {
 	Name     string
 }
 
+type IdempotencyConfig struct {
+	// TTL is how long saved response can be replayed
+	TTL time.Duration
+}
+
+type ImportConfig struct {
+	// Dir stores uploaded import files, they are needed to resume failed imports
+	Dir string
+}
+
+type ExportConfig struct {
+	// Dir stores files of running exports, finished ones are moved to database
+	Dir string
+	// PseudonymKey is HMAC key of pseudonymous IDs in research exports. IDs are stable while key is
+	// the same, research exports are disabled if it is empty
+	PseudonymKey string
+	// TTL is how long file of completed export can be downloaded
+	TTL time.Duration
+}
+
+// ClinicConfig is clinic branding of printed documents & local time of clinic
+type ClinicConfig struct {
+	Name     string
+	Address  string
+	Phone    string
+	Email    string
+	LogoPath string // png or jpg, optional
+	// TimeZone is IANA name of zone entry dates & follow-ups are in, e.g. Europe/Moscow
+	TimeZone string
+}
+
+// FHIRConfig is public address of FHIR API
+type FHIRConfig struct {
+	// BaseURL is absolute FHIR base behind proxy, e.g. https://api.clinic.example/info/v1/fhir.
+	// If empty, it is built from host & scheme of request
+	BaseURL string
+}
+
+// PDFConfig contains TrueType fonts with cyrillic glyphs
+type PDFConfig struct {
+	FontPath     string
+	BoldFontPath string
+}
+
+// MLLPConfig is HL7 listener of lab analyzers. Listener is disabled if Addr is empty
+type MLLPConfig struct {
+	Addr string
+}
+
+// NotifyConfig is delivery of owner notifications. Channel is disabled if its address is empty,
+// scanner does not run without channels
+type NotifyConfig struct {
+	SMTP       SMTPConfig
+	SMSWebhook SMSWebhookConfig
+	// ScanInterval is how often due reminders & pending deliveries are processed
+	ScanInterval time.Duration
+	// FollowUpLead is how long before follow_up_at of med entry owner is reminded
+	FollowUpLead time.Duration
+}
+
+type SMTPConfig struct {
+	Addr     string // host:port
+	Username string
+	Password string
+	From     string
+}
+
+// SMSWebhookConfig is SMS gateway receiving JSON {"to", "text"} by POST
+type SMSWebhookConfig struct {
+	URL   string
+	Token string // sent as bearer token, optional
+}
+
 var config *Config
 var once sync.Once
 
@@ -63,5 +149,88 @@ func NewConfig() (*Config, error) {
 		return &Config{}, errors.New("POSTGRES_DB is empty")
 	}
 
+	config.Idempotency.TTL = 24 * time.Hour
+	if ttl := os.Getenv("IDEMPOTENCY_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil || parsed <= 0 {
+			return &Config{}, fmt.Errorf("IDEMPOTENCY_TTL is invalid duration: %s", ttl)
+		}
+		config.Idempotency.TTL = parsed
+	}
+
+	if config.Import.Dir = os.Getenv("IMPORT_DIR"); config.Import.Dir == "" {
+		config.Import.Dir = filepath.Join(os.TempDir(), "info-service-imports")
+	}
+
+	if config.Export.Dir = os.Getenv("EXPORT_DIR"); config.Export.Dir == "" {
+		config.Export.Dir = filepath.Join(os.TempDir(), "info-service-exports")
+	}
+	config.Export.PseudonymKey = os.Getenv("EXPORT_PSEUDONYM_KEY")
+	config.Export.TTL = 24 * time.Hour
+	if ttl := os.Getenv("EXPORT_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil || parsed <= 0 {
+			return &Config{}, fmt.Errorf("EXPORT_TTL is invalid duration: %s", ttl)
+		}
+		config.Export.TTL = parsed
+	}
+
+	if config.Clinic.Name = os.Getenv("CLINIC_NAME"); config.Clinic.Name == "" {
+		config.Clinic.Name = "Vet clinic"
+	}
+	config.Clinic.Address = os.Getenv("CLINIC_ADDRESS")
+	config.Clinic.Phone = os.Getenv("CLINIC_PHONE")
+	config.Clinic.Email = os.Getenv("CLINIC_EMAIL")
+	config.Clinic.LogoPath = os.Getenv("CLINIC_LOGO_PATH")
+	if config.Clinic.TimeZone = os.Getenv("CLINIC_TIME_ZONE"); config.Clinic.TimeZone == "" {
+		config.Clinic.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(config.Clinic.TimeZone); err != nil {
+		return &Config{}, fmt.Errorf("CLINIC_TIME_ZONE is invalid time zone: %s", config.Clinic.TimeZone)
+	}
+
+	if config.PDF.FontPath = os.Getenv("PDF_FONT_PATH"); config.PDF.FontPath == "" {
+		config.PDF.FontPath = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	}
+	if config.PDF.BoldFontPath = os.Getenv("PDF_FONT_BOLD_PATH"); config.PDF.BoldFontPath == "" {
+		config.PDF.BoldFontPath = "/usr/share/fonts/truetype/dejavu/DejaVuSans-Bold.ttf"
+	}
+
+	config.FHIR.BaseURL = strings.TrimSuffix(os.Getenv("FHIR_BASE_URL"), "/")
+	if config.FHIR.BaseURL != "" {
+		parsed, err := url.Parse(config.FHIR.BaseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return &Config{}, fmt.Errorf("FHIR_BASE_URL is invalid absolute URL: %s", config.FHIR.BaseURL)
+		}
+	}
+
+	config.MLLP.Addr = os.Getenv("MLLP_ADDR")
+
+	config.Notify.SMTP.Addr = os.Getenv("SMTP_ADDR")
+	config.Notify.SMTP.Username = os.Getenv("SMTP_USERNAME")
+	config.Notify.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	if config.Notify.SMTP.From = os.Getenv("SMTP_FROM"); config.Notify.SMTP.From == "" {
+		config.Notify.SMTP.From = config.Clinic.Email
+	}
+	config.Notify.SMSWebhook.URL = os.Getenv("SMS_WEBHOOK_URL")
+	config.Notify.SMSWebhook.Token = os.Getenv("SMS_WEBHOOK_TOKEN")
+
+	config.Notify.ScanInterval = time.Minute
+	if interval := os.Getenv("NOTIFY_SCAN_INTERVAL"); interval != "" {
+		parsed, err := time.ParseDuration(interval)
+		if err != nil || parsed <= 0 {
+			return &Config{}, fmt.Errorf("NOTIFY_SCAN_INTERVAL is invalid duration: %s", interval)
+		}
+		config.Notify.ScanInterval = parsed
+	}
+	config.Notify.FollowUpLead = 24 * time.Hour
+	if lead := os.Getenv("NOTIFY_FOLLOW_UP_LEAD"); lead != "" {
+		parsed, err := time.ParseDuration(lead)
+		if err != nil || parsed < 0 {
+			return &Config{}, fmt.Errorf("NOTIFY_FOLLOW_UP_LEAD is invalid duration: %s", lead)
+		}
+		config.Notify.FollowUpLead = parsed
+	}
+
 	return config, nil
 }
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Create allergy
+// @Description Records known allergy or chronic condition of pet. kind is allergy or condition,
+// @Description severity is low, moderate or high. Allergies are matched with new med entries
+// @Security ApiKeyAuth
+// @Tags allergies
+// @Accept json
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Param input body models.Allergy true "Allergy, recorded_by is vet ID"
+// @Success 201 {object} models.Allergy "Created allergy"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 422 {object} models.ProblemDTO "Vet does not exist"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/allergies [post]
+func (h *Handler) createAllergy(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createAllergy")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	var input models.Allergy
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.PetID = uint(petID)
+
+	if err := validation.ValidateCreatingAllergy(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	allergy, err := h.service.Info.CreateAllergy(input)
+	if err != nil {
+		log.Error("failed to create allergy: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created allergy")
+	c.JSON(http.StatusCreated, allergy)
+}
+
+// @Summary Get pet allergies
+// @Description Allergies & chronic conditions of pet, latest first
+// @Security ApiKeyAuth
+// @Tags allergies
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} []models.Allergy "Allergies"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/allergies [get]
+func (h *Handler) getAllergies(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getAllergies")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	allergies, err := h.service.Info.GetAllergies(uint(petID))
+	if err != nil {
+		log.Error("failed to get allergies: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, allergies)
+}
+
+// @Summary Delete allergy
+// @Description Removes allergy or condition recorded by mistake. Logged overrides are deleted too
+// @Security ApiKeyAuth
+// @Tags allergies
+// @Param id path int true "Pet ID"
+// @Param allergy_id path int true "Allergy ID"
+// @Success 200 "Deleted"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Allergy not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/allergies/{allergy_id} [delete]
+func (h *Handler) deleteAllergy(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.deleteAllergy")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+	allergyID, err := strconv.ParseUint(c.Param("allergy_id"), 10, 32)
+	if err != nil {
+		log.Error("invalid allergy ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid allergy ID", err))
+		return
+	}
+
+	if err := h.service.Info.DeleteAllergy(uint(petID), uint(allergyID)); err != nil {
+		log.Error("failed to delete allergy: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully deleted allergy")
+	c.Status(http.StatusOK)
+}
+
+// @Summary Get allergen synonyms
+// @Description Synonym groups used to match allergies with med entries. All terms of group match each other
+// @Security ApiKeyAuth
+// @Tags allergies
+// @Produce json
+// @Success 200 {object} []models.AllergenSynonyms "Synonym groups"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/allergen-synonyms [get]
+func (h *Handler) getAllergenSynonyms(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getAllergenSynonyms")
+
+	groups, err := h.service.Info.GetAllergenSynonyms()
+	if err != nil {
+		log.Error("failed to get allergen synonyms: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// @Summary Save allergen synonyms
+// @Description Creates or replaces synonym group of substance. Terms are lower cased
+// @Security ApiKeyAuth
+// @Tags allergies
+// @Accept json
+// @Produce json
+// @Param substance path string true "Substance, e.g. penicillin"
+// @Param input body models.AllergenSynonyms true "Synonyms, substance is taken from path"
+// @Success 200 {object} models.AllergenSynonyms "Saved synonym group"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/allergen-synonyms/{substance} [put]
+func (h *Handler) saveAllergenSynonyms(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.saveAllergenSynonyms")
+
+	var input models.AllergenSynonyms
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.Substance = c.Param("substance")
+
+	if err := validation.ValidateAllergenSynonyms(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	group, err := h.service.Info.SaveAllergenSynonyms(input)
+	if err != nil {
+		log.Error("failed to save allergen synonyms: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully saved allergen synonyms")
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Delete allergen synonyms
+// @Description Removes synonym group of substance. Substance itself is still matched
+// @Security ApiKeyAuth
+// @Tags allergies
+// @Param substance path string true "Substance"
+// @Success 200 "Deleted"
+// @Failure 404 {object} models.ProblemDTO "Synonym group not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/allergen-synonyms/{substance} [delete]
+func (h *Handler) deleteAllergenSynonyms(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.deleteAllergenSynonyms")
+
+	if err := h.service.Info.DeleteAllergenSynonyms(c.Param("substance")); err != nil {
+		log.Error("failed to delete allergen synonyms: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully deleted allergen synonyms")
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Book appointment
+// @Description Books visit of pet to vet. starts_at & ends_at are RFC 3339, clinic_number defaults to clinic of vet.
+// @Description Time outside working hours of vet or overlapping booked or checked in appointment of vet is rejected
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param input body models.Appointment true "Appointment"
+// @Success 201 {object} models.Appointment "Booked appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Pet or vet not found"
+// @Failure 409 {object} models.ProblemDTO "Vet does not work or has other appointment at that time"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments [post]
+func (h *Handler) bookAppointment(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.bookAppointment")
+
+	var input models.Appointment
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateBookingAppointment(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	appointment, err := h.service.Appointment.BookAppointment(input)
+	if err != nil {
+		log.Error("failed to book appointment: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully booked appointment")
+	c.JSON(http.StatusCreated, appointment)
+}
+
+// @Summary Get appointments
+// @Description Appointments ordered by start. from & to are RFC 3339, appointments overlapping them are returned
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Produce json
+// @Param vet_id query int false "Vet ID"
+// @Param pet_id query int false "Pet ID"
+// @Param status query string false "booked, checked_in, completed, no_show or cancelled"
+// @Param from query string false "Period start"
+// @Param to query string false "Period end"
+// @Param offset query int false "offset"
+// @Param limit query int false "limit"
+// @Success 200 {object} []models.Appointment "Appointments"
+// @Failure 400 {object} models.ProblemDTO "Invalid filters"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments [get]
+func (h *Handler) getAppointments(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getAppointments")
+
+	filters, err := http_utils.ParseAppointmentFilters(c)
+	if err != nil {
+		log.Error("failed to parse filters: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+
+	if err := validation.ValidateAppointmentFilter(filters); err != nil {
+		log.Error("failed to validate filters: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	appointments, err := h.service.Appointment.GetAppointments(filters)
+	if err != nil {
+		log.Error("failed to get appointments: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got appointments")
+	c.JSON(http.StatusOK, appointments)
+}
+
+// @Summary Get appointment
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} models.Appointment "Appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid appointment ID"
+// @Failure 404 {object} models.ProblemDTO "Appointment not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments/{id} [get]
+func (h *Handler) getAppointment(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getAppointment")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid appointment ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid appointment ID", err))
+		return
+	}
+
+	appointment, err := h.service.Appointment.GetAppointment(uint(id))
+	if err != nil {
+		log.Error("failed to get appointment: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got appointment")
+	c.JSON(http.StatusOK, appointment)
+}
+
+// @Summary Reschedule appointment
+// @Description Moves booked appointment to other time, vet_id moves it to other vet
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param input body models.ReschedulingAppointmentDTO true "New time & optional vet"
+// @Success 200 {object} models.Appointment "Rescheduled appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Appointment or vet not found"
+// @Failure 409 {object} models.ProblemDTO "Appointment is not booked or vet does not work or has other appointment at that time"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments/{id}/reschedule [post]
+func (h *Handler) rescheduleAppointment(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.rescheduleAppointment")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid appointment ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid appointment ID", err))
+		return
+	}
+
+	var input models.ReschedulingAppointmentDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateReschedulingAppointment(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	appointment, err := h.service.Appointment.RescheduleAppointment(uint(id), input)
+	if err != nil {
+		log.Error("failed to reschedule appointment: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully rescheduled appointment")
+	c.JSON(http.StatusOK, appointment)
+}
+
+// @Summary Cancel appointment
+// @Description Cancels booked or checked in appointment
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param input body models.CancellingAppointmentDTO true "Cancel reason"
+// @Success 200 {object} models.Appointment "Cancelled appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Appointment not found"
+// @Failure 409 {object} models.ProblemDTO "Appointment can not be cancelled"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments/{id}/cancel [post]
+func (h *Handler) cancelAppointment(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.cancelAppointment")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid appointment ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid appointment ID", err))
+		return
+	}
+
+	var input models.CancellingAppointmentDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateCancellingAppointment(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	appointment, err := h.service.Appointment.CancelAppointment(uint(id), input)
+	if err != nil {
+		log.Error("failed to cancel appointment: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully cancelled appointment")
+	c.JSON(http.StatusOK, appointment)
+}
+
+// @Summary Check in appointment
+// @Description Marks booked appointment as checked in when pet arrives
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} models.Appointment "Checked in appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid appointment ID"
+// @Failure 404 {object} models.ProblemDTO "Appointment not found"
+// @Failure 409 {object} models.ProblemDTO "Appointment is not booked"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments/{id}/check-in [post]
+func (h *Handler) checkInAppointment(c *gin.Context) {
+	h.setAppointmentStatus(c, "Handler.checkInAppointment", h.service.Appointment.CheckInAppointment)
+}
+
+// @Summary Mark no show
+// @Description Marks booked appointment as missed
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} models.Appointment "Missed appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid appointment ID"
+// @Failure 404 {object} models.ProblemDTO "Appointment not found"
+// @Failure 409 {object} models.ProblemDTO "Appointment is not booked"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments/{id}/no-show [post]
+func (h *Handler) markNoShow(c *gin.Context) {
+	h.setAppointmentStatus(c, "Handler.markNoShow", h.service.Appointment.MarkNoShow)
+}
+
+// @Summary Complete appointment
+// @Description Completes checked in appointment and creates medical entry stub in record of pet,
+// @Description medical_entry_id of response is its ID
+// @Security ApiKeyAuth
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} models.Appointment "Completed appointment"
+// @Failure 400 {object} models.ProblemDTO "Invalid appointment ID"
+// @Failure 404 {object} models.ProblemDTO "Appointment or medical record not found"
+// @Failure 409 {object} models.ProblemDTO "Appointment is not checked in"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/appointments/{id}/complete [post]
+func (h *Handler) completeAppointment(c *gin.Context) {
+	h.setAppointmentStatus(c, "Handler.completeAppointment", h.service.Appointment.CompleteAppointment)
+}
+
+func (h *Handler) setAppointmentStatus(c *gin.Context, op string, set func(id uint) (models.Appointment, error)) {
+	log := h.log.WithField("op", op)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid appointment ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid appointment ID", err))
+		return
+	}
+
+	appointment, err := set(uint(id))
+	if err != nil {
+		log.Error("failed to change appointment status: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("appointment is ", appointment.Status)
+	c.JSON(http.StatusOK, appointment)
+}
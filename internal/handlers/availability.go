@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Get clinics
+// @Description Clinic branches without opening hours
+// @Security ApiKeyAuth
+// @Tags availability
+// @Produce json
+// @Success 200 {object} []models.Clinic "Clinics"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/clinics [get]
+func (h *Handler) getClinics(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getClinics")
+
+	clinics, err := h.service.Availability.GetClinics()
+	if err != nil {
+		log.Error("failed to get clinics: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got clinics")
+	c.JSON(http.StatusOK, clinics)
+}
+
+// @Summary Get clinic
+// @Description Clinic with opening hours
+// @Security ApiKeyAuth
+// @Tags availability
+// @Produce json
+// @Param number path string true "Clinic number"
+// @Success 200 {object} models.Clinic "Clinic"
+// @Failure 404 {object} models.ProblemDTO "Clinic not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/clinics/{number} [get]
+func (h *Handler) getClinic(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getClinic")
+
+	clinic, err := h.service.Availability.GetClinic(c.Param("number"))
+	if err != nil {
+		log.Error("failed to get clinic: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got clinic")
+	c.JSON(http.StatusOK, clinic)
+}
+
+// @Summary Save clinic
+// @Description Creates or replaces clinic. time_zone is IANA name, hours are opening hours in local time:
+// @Description weekday 0-6 (0 is Sunday), start & end HH:MM. Clinic without hours does not limit vet schedules
+// @Security ApiKeyAuth
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param number path string true "Clinic number"
+// @Param input body models.Clinic true "Clinic"
+// @Success 200 {object} models.Clinic "Saved clinic"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/clinics/{number} [put]
+func (h *Handler) saveClinic(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.saveClinic")
+
+	var input models.Clinic
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.Number = c.Param("number")
+
+	if err := validation.ValidateClinic(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	clinic, err := h.service.Availability.SaveClinic(input)
+	if err != nil {
+		log.Error("failed to save clinic: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully saved clinic")
+	c.JSON(http.StatusOK, clinic)
+}
+
+// @Summary Get vet schedule
+// @Description Weekly working hours of vet in local time of vet clinic
+// @Security ApiKeyAuth
+// @Tags availability
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Success 200 {object} []models.WorkingHours "Working hours"
+// @Failure 400 {object} models.ProblemDTO "Invalid vet ID"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/schedule [get]
+func (h *Handler) getVetSchedule(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getVetSchedule")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	hours, err := h.service.Availability.GetVetSchedule(uint(vetID))
+	if err != nil {
+		log.Error("failed to get vet schedule: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got vet schedule")
+	c.JSON(http.StatusOK, hours)
+}
+
+// @Summary Save vet schedule
+// @Description Replaces weekly working hours of vet: weekday 0-6 (0 is Sunday), start & end HH:MM in local time
+// @Description of vet clinic
+// @Security ApiKeyAuth
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Param input body []models.WorkingHours true "Working hours"
+// @Success 200 {object} []models.WorkingHours "Saved working hours"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/schedule [put]
+func (h *Handler) saveVetSchedule(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.saveVetSchedule")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	var input []models.WorkingHours
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateVetSchedule(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	hours, err := h.service.Availability.SaveVetSchedule(uint(vetID), input)
+	if err != nil {
+		log.Error("failed to save vet schedule: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully saved vet schedule")
+	c.JSON(http.StatusOK, hours)
+}
+
+// @Summary Get schedule exceptions
+// @Description Vacations, sick leaves & other days off of vet that did not end yet
+// @Security ApiKeyAuth
+// @Tags availability
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Success 200 {object} []models.ScheduleException "Exceptions"
+// @Failure 400 {object} models.ProblemDTO "Invalid vet ID"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/exceptions [get]
+func (h *Handler) getScheduleExceptions(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getScheduleExceptions")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	exceptions, err := h.service.Availability.GetScheduleExceptions(uint(vetID))
+	if err != nil {
+		log.Error("failed to get schedule exceptions: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got schedule exceptions")
+	c.JSON(http.StatusOK, exceptions)
+}
+
+// @Summary Create schedule exception
+// @Description Days vet does not work. kind is vacation, sick_leave or other, dates are YYYY-MM-DD, both inclusive
+// @Security ApiKeyAuth
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Param input body models.ScheduleException true "Exception"
+// @Success 201 {object} models.ScheduleException "Created exception"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 422 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/exceptions [post]
+func (h *Handler) createScheduleException(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createScheduleException")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	var input models.ScheduleException
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.VetID = uint(vetID)
+
+	if err := validation.ValidateScheduleException(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	exception, err := h.service.Availability.CreateScheduleException(input)
+	if err != nil {
+		log.Error("failed to create schedule exception: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created schedule exception")
+	c.JSON(http.StatusCreated, exception)
+}
+
+// @Summary Delete schedule exception
+// @Security ApiKeyAuth
+// @Tags availability
+// @Param id path int true "Vet ID"
+// @Param exception_id path int true "Exception ID"
+// @Success 200 "Deleted"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Exception not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/exceptions/{exception_id} [delete]
+func (h *Handler) deleteScheduleException(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.deleteScheduleException")
+
+	vetID, id, err := vetChildIDs(c, "exception_id")
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.service.Availability.DeleteScheduleException(vetID, id); err != nil {
+		log.Error("failed to delete schedule exception: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully deleted schedule exception")
+	c.Status(http.StatusOK)
+}
+
+// @Summary Get time blocks
+// @Description Reserved time of vet that did not end yet
+// @Security ApiKeyAuth
+// @Tags availability
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Success 200 {object} []models.TimeBlock "Time blocks"
+// @Failure 400 {object} models.ProblemDTO "Invalid vet ID"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/blocks [get]
+func (h *Handler) getTimeBlocks(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getTimeBlocks")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	blocks, err := h.service.Availability.GetTimeBlocks(uint(vetID))
+	if err != nil {
+		log.Error("failed to get time blocks: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got time blocks")
+	c.JSON(http.StatusOK, blocks)
+}
+
+// @Summary Create time block
+// @Description Reserves time of vet, optionally for pet. starts_at & ends_at are RFC 3339.
+// @Description Time overlapping appointments or other blocks of vet is rejected
+// @Security ApiKeyAuth
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Param input body models.TimeBlock true "Time block"
+// @Success 201 {object} models.TimeBlock "Created time block"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Vet or pet not found"
+// @Failure 409 {object} models.ProblemDTO "Time is already booked"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/blocks [post]
+func (h *Handler) createTimeBlock(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createTimeBlock")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	var input models.TimeBlock
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.VetID = uint(vetID)
+
+	if err := validation.ValidateTimeBlock(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	block, err := h.service.Availability.CreateTimeBlock(input)
+	if err != nil {
+		log.Error("failed to create time block: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created time block")
+	c.JSON(http.StatusCreated, block)
+}
+
+// @Summary Delete time block
+// @Security ApiKeyAuth
+// @Tags availability
+// @Param id path int true "Vet ID"
+// @Param block_id path int true "Time block ID"
+// @Success 200 "Deleted"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Time block not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/blocks/{block_id} [delete]
+func (h *Handler) deleteTimeBlock(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.deleteTimeBlock")
+
+	vetID, id, err := vetChildIDs(c, "block_id")
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.service.Availability.DeleteTimeBlock(vetID, id); err != nil {
+		log.Error("failed to delete time block: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully deleted time block")
+	c.Status(http.StatusOK)
+}
+
+// @Summary Get vet availability
+// @Description Free slots of vet: working hours within clinic opening hours except days off, time blocks & booked
+// @Description appointments. from & to are local dates of vet clinic (YYYY-MM-DD, at most 31 days), duration is
+// @Description slot length in minutes. Slots are in time zone of clinic
+// @Security ApiKeyAuth
+// @Tags availability
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Param from query string true "First date"
+// @Param to query string true "Last date"
+// @Param duration query int true "Slot length in minutes, 5-480"
+// @Success 200 {object} models.AvailabilityDTO "Free slots"
+// @Failure 400 {object} models.ProblemDTO "Invalid vet ID or filters"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/availability [get]
+func (h *Handler) getVetAvailability(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getVetAvailability")
+
+	vetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid vet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid vet ID", err))
+		return
+	}
+
+	filter := models.AvailabilityReqFilter{From: c.Query("from"), To: c.Query("to")}
+	duration, err := strconv.ParseUint(c.Query("duration"), 10, 32)
+	if err != nil {
+		log.Error("invalid duration: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("duration should be number of minutes", err))
+		return
+	}
+	filter.Duration = uint(duration)
+
+	if err := validation.ValidateAvailabilityFilter(filter); err != nil {
+		log.Error("failed to validate filters: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	availability, err := h.service.Availability.GetAvailability(uint(vetID), filter)
+	if err != nil {
+		log.Error("failed to get availability: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got availability")
+	c.JSON(http.StatusOK, availability)
+}
+
+func vetChildIDs(c *gin.Context, childParam string) (vetID, id uint, err error) {
+	parsedVetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, 0, errs.Validation("invalid vet ID", err)
+	}
+	parsedID, err := strconv.ParseUint(c.Param(childParam), 10, 32)
+	if err != nil {
+		return 0, 0, errs.Validation("invalid "+childParam, err)
+	}
+	return uint(parsedVetID), uint(parsedID), nil
+}
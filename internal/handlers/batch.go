@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// customMethodParam is gin param that holds ":method" suffix of routes like /pets:batch
+const customMethodParam = "method"
+
+// customMethod lets request through only if route suffix is ":name"
+func (h *Handler) customMethod(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param(customMethodParam) != ":"+name {
+			h.newErrorResponse(c, errs.NotFound("route not found", nil))
+			return
+		}
+		c.Next()
+	}
+}
+
+// @Summary Create pets batch
+// @Description Create several pets with med cards. In atomic mode (default) nothing is created if any item fails,
+// @Description in best_effort mode each item is created separately. Results are in the same order as items
+// @Security ApiKeyAuth
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param mode query string false "atomic or best_effort"
+// @Param input body []createPetDTO true "Pets"
+// @Param Idempotency-Key header string false "Key to safely retry request. Saved response is replayed"
+// @Success 201 {object} models.BatchResponseDTO "All pets created"
+// @Success 207 {object} models.BatchResponseDTO "Some items failed"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or mode"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets:batch [post]
+func (h *Handler) createPetsBatch(c *gin.Context) {
+	op := "Handler.createPetsBatch"
+	log := h.log.WithField("op", op)
+
+	var input []createPetDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	items := make([]models.PetWithCard, 0, len(input))
+	for _, dto := range input {
+		items = append(items, models.PetWithCard{Pet: dto.Pet, OwnerID: dto.OwnerID, VetID: dto.VetID})
+	}
+
+	mode := c.DefaultQuery("mode", models.BatchModeAtomic)
+	results, err := h.service.Info.CreatePetsBatch(items, mode)
+	if err != nil {
+		log.Error("failed to create pets batch: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("pets batch processed")
+	h.batchResponse(c, mode, results, batchCreated)
+}
+
+// @Summary Update pets batch
+// @Description Replace several pets like PUT /info/v1/pets/{id}, id of each pet is in body. In atomic mode (default)
+// @Description nothing is updated if any item fails, in best_effort mode each item is updated separately.
+// @Description Results are in the same order as items
+// @Security ApiKeyAuth
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param mode query string false "atomic or best_effort"
+// @Param input body []models.Pet true "Pets"
+// @Success 200 {object} models.BatchResponseDTO "All pets updated"
+// @Success 207 {object} models.BatchResponseDTO "Some items failed"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or mode"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets:batch [put]
+func (h *Handler) updatePetsBatch(c *gin.Context) {
+	op := "Handler.updatePetsBatch"
+	log := h.log.WithField("op", op)
+
+	var input []models.Pet
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	mode := c.DefaultQuery("mode", models.BatchModeAtomic)
+	results, err := h.service.Info.UpdatePetsBatch(input, mode)
+	if err != nil {
+		log.Error("failed to update pets batch: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("pets batch processed")
+	h.batchResponse(c, mode, results, batchUpdated)
+}
+
+// @Summary Create med entries batch
+// @Description Create several med entries. In atomic mode (default) nothing is created if any item fails,
+// @Description in best_effort mode each item is created separately. Results are in the same order as items.
+// @Description Allergies are checked per item as in single create, warnings are returned per created item
+// @Security ApiKeyAuth
+// @Tags MedEntry
+// @Accept json
+// @Produce json
+// @Param mode query string false "atomic or best_effort"
+// @Param input body []models.CreatingMedEntry true "Entries"
+// @Param Idempotency-Key header string false "Key to safely retry request. Saved response is replayed"
+// @Success 201 {object} models.BatchResponseDTO "All entries created"
+// @Success 207 {object} models.BatchResponseDTO "Some items failed"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or mode"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries:batch [post]
+func (h *Handler) createEntriesBatch(c *gin.Context) {
+	op := "Handler.createEntriesBatch"
+	log := h.log.WithField("op", op)
+
+	var input []models.CreatingMedEntry
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	mode := c.DefaultQuery("mode", models.BatchModeAtomic)
+	results, err := h.service.MedInfo.CreateMedEntriesBatch(input, mode)
+	if err != nil {
+		log.Error("failed to create entries batch: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("entries batch processed")
+	h.batchResponse(c, mode, results, batchCreated)
+}
+
+// @Summary Update med entries batch
+// @Description Replace several med entries, id of each entry is in body. Entries stay in their med records,
+// @Description empty entry_date keeps saved one. In atomic mode (default) nothing is updated if any item fails,
+// @Description in best_effort mode each item is updated separately. Results are in the same order as items.
+// @Description Allergies are checked per item as in create, warnings are returned per updated item
+// @Security ApiKeyAuth
+// @Tags MedEntry
+// @Accept json
+// @Produce json
+// @Param mode query string false "atomic or best_effort"
+// @Param input body []models.CreatingMedEntry true "Entries"
+// @Success 200 {object} models.BatchResponseDTO "All entries updated"
+// @Success 207 {object} models.BatchResponseDTO "Some items failed"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or mode"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries:batch [put]
+func (h *Handler) updateEntriesBatch(c *gin.Context) {
+	op := "Handler.updateEntriesBatch"
+	log := h.log.WithField("op", op)
+
+	var input []models.CreatingMedEntry
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	mode := c.DefaultQuery("mode", models.BatchModeAtomic)
+	results, err := h.service.MedInfo.UpdateMedEntriesBatch(input, mode)
+	if err != nil {
+		log.Error("failed to update entries batch: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("entries batch processed")
+	h.batchResponse(c, mode, results, batchUpdated)
+}
+
+// batchCreated & batchUpdated are statuses of saved batch items
+const (
+	batchCreated = "created"
+	batchUpdated = "updated"
+)
+
+// batchResponse responds 201 if all items are created, 200 if all items are updated & 207 otherwise
+func (h *Handler) batchResponse(c *gin.Context, mode string, results []models.BatchResult, saved string) {
+	resp := models.BatchResponseDTO{
+		Mode:    mode,
+		Results: make([]models.BatchResultDTO, 0, len(results)),
+	}
+
+	for i, res := range results {
+		item := models.BatchResultDTO{Index: i, ID: res.ID, Warnings: res.Warnings, Status: saved}
+		if res.Err != nil {
+			problem := newProblem(c, res.Err)
+			problem.Instance = fmt.Sprintf("%s/items/%d", problem.Instance, i)
+			item.Error = &problem
+			item.Status = "failed"
+			if errors.Is(res.Err, errs.ErrRolledBack) {
+				item.Status = "rolled_back"
+			}
+			resp.Failed++
+		} else if saved == batchCreated {
+			resp.Created++
+		} else {
+			resp.Updated++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+
+	status := http.StatusCreated
+	if saved == batchUpdated {
+		status = http.StatusOK
+	}
+	if resp.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
+}
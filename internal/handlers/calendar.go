@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const calendarPath = "/info/v1/calendar/"
+
+// @Summary Get calendar feed
+// @Description iCalendar of follow-ups of vet or owner for calendar apps. Token is secret of feed, so endpoint
+// @Description needs no auth. Follow-ups older than 90 days are not included
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token with .ics suffix"
+// @Success 200 {file} file "iCalendar"
+// @Failure 404 {object} models.ProblemDTO "Feed not found or revoked"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/calendar/{token} [get]
+func (h *Handler) getCalendar(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getCalendar")
+
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	calendar, err := h.service.Calendar.GetCalendar(token)
+	if err != nil {
+		log.Error("failed to get calendar: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully rendered calendar")
+	c.Header("Content-Disposition", `inline; filename="follow-ups.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar)
+}
+
+// @Summary Create vet calendar feed
+// @Description Issues feed of follow-ups of pets whose med record belongs to vet. token & url are shown only once
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Success 201 {object} models.CalendarFeed "Created feed"
+// @Failure 400 {object} models.ProblemDTO "Invalid vet ID"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/calendar-feeds [post]
+func (h *Handler) createVetCalendarFeed(c *gin.Context) {
+	h.createCalendarFeed(c, models.CalendarFeedKindVet)
+}
+
+// @Summary Get vet calendar feeds
+// @Description Feeds of vet, revoked ones included. Tokens are not shown
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Produce json
+// @Param id path int true "Vet ID"
+// @Success 200 {object} []models.CalendarFeed "Feeds"
+// @Failure 400 {object} models.ProblemDTO "Invalid vet ID"
+// @Failure 404 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/calendar-feeds [get]
+func (h *Handler) getVetCalendarFeeds(c *gin.Context) {
+	h.getCalendarFeeds(c, models.CalendarFeedKindVet)
+}
+
+// @Summary Revoke vet calendar feed
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Param id path int true "Vet ID"
+// @Param feed_id path int true "Feed ID"
+// @Success 200 "Revoked"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Feed not found or already revoked"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vets/{id}/calendar-feeds/{feed_id} [delete]
+func (h *Handler) revokeVetCalendarFeed(c *gin.Context) {
+	h.revokeCalendarFeed(c, models.CalendarFeedKindVet)
+}
+
+// @Summary Create owner calendar feed
+// @Description Issues feed of follow-ups of pets of owner. token & url are shown only once
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Produce json
+// @Param id path int true "Owner ID"
+// @Success 201 {object} models.CalendarFeed "Created feed"
+// @Failure 400 {object} models.ProblemDTO "Invalid owner ID"
+// @Failure 404 {object} models.ProblemDTO "Owner not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/owners/{id}/calendar-feeds [post]
+func (h *Handler) createOwnerCalendarFeed(c *gin.Context) {
+	h.createCalendarFeed(c, models.CalendarFeedKindOwner)
+}
+
+// @Summary Get owner calendar feeds
+// @Description Feeds of owner, revoked ones included. Tokens are not shown
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Produce json
+// @Param id path int true "Owner ID"
+// @Success 200 {object} []models.CalendarFeed "Feeds"
+// @Failure 400 {object} models.ProblemDTO "Invalid owner ID"
+// @Failure 404 {object} models.ProblemDTO "Owner not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/owners/{id}/calendar-feeds [get]
+func (h *Handler) getOwnerCalendarFeeds(c *gin.Context) {
+	h.getCalendarFeeds(c, models.CalendarFeedKindOwner)
+}
+
+// @Summary Revoke owner calendar feed
+// @Security ApiKeyAuth
+// @Tags calendar
+// @Param id path int true "Owner ID"
+// @Param feed_id path int true "Feed ID"
+// @Success 200 "Revoked"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Feed not found or already revoked"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/owners/{id}/calendar-feeds/{feed_id} [delete]
+func (h *Handler) revokeOwnerCalendarFeed(c *gin.Context) {
+	h.revokeCalendarFeed(c, models.CalendarFeedKindOwner)
+}
+
+func (h *Handler) createCalendarFeed(c *gin.Context, kind string) {
+	log := h.log.WithField("op", "Handler.createCalendarFeed").WithField("kind", kind)
+
+	subjectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid "+kind+" ID", err))
+		return
+	}
+
+	feed, err := h.service.Calendar.CreateCalendarFeed(kind, uint(subjectID))
+	if err != nil {
+		log.Error("failed to create calendar feed: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+	feed.URL = calendarPath + feed.Token + ".ics"
+
+	log.Info("successfully created calendar feed")
+	c.JSON(http.StatusCreated, feed)
+}
+
+func (h *Handler) getCalendarFeeds(c *gin.Context, kind string) {
+	log := h.log.WithField("op", "Handler.getCalendarFeeds").WithField("kind", kind)
+
+	subjectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid "+kind+" ID", err))
+		return
+	}
+
+	feeds, err := h.service.Calendar.GetCalendarFeeds(kind, uint(subjectID))
+	if err != nil {
+		log.Error("failed to get calendar feeds: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got calendar feeds")
+	c.JSON(http.StatusOK, feeds)
+}
+
+func (h *Handler) revokeCalendarFeed(c *gin.Context, kind string) {
+	log := h.log.WithField("op", "Handler.revokeCalendarFeed").WithField("kind", kind)
+
+	subjectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid "+kind+" ID", err))
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("feed_id"), 10, 32)
+	if err != nil {
+		log.Error("invalid feed ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid feed ID", err))
+		return
+	}
+
+	if err := h.service.Calendar.RevokeCalendarFeed(kind, uint(subjectID), uint(id)); err != nil {
+		log.Error("failed to revoke calendar feed: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully revoked calendar feed")
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Start export
+// @Description Starts async export. research type is zip with anonymized pets, entries & manifest.json,
+// @Description pets & entries types are file with same rows as GET /info/v1/pets & GET /info/v1/record/entries.
+// @Description criteria.pets are filters of GET /info/v1/pets, criteria.entries are pet_id, vet_id &
+// @Description date_from, date_to (YYYY-MM-DD) of medical entries. research type includes only pets with research
+// @Description status consented, enrolled, active or completed. format is csv or parquet
+// @Security ApiKeyAuth
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Param input body models.CreatingExportDTO true "Export type, format & criteria"
+// @Success 202 {object} models.ExportJob "Export started"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 403 {object} models.ProblemDTO "Research exports are disabled"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/exports [post]
+func (h *Handler) createExport(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createExport")
+
+	var input models.CreatingExportDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateCreatingExport(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	job, err := h.service.Export.CreateExport(models.ExportJob{
+		Type:     input.Type,
+		Format:   input.Format,
+		Criteria: input.Criteria,
+	})
+	if err != nil {
+		log.Error("failed to create export: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("export started")
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary Get export
+// @Description Export job status & progress
+// @Security ApiKeyAuth
+// @Tags exports
+// @Produce json
+// @Param id path int true "Export job ID"
+// @Success 200 {object} models.ExportJob "Export job"
+// @Failure 400 {object} models.ProblemDTO "Invalid job ID"
+// @Failure 404 {object} models.ProblemDTO "Job not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/exports/{id} [get]
+func (h *Handler) getExport(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getExport")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid job ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid job ID", err))
+		return
+	}
+
+	job, err := h.service.Export.GetExport(uint(id))
+	if err != nil {
+		log.Error("failed to get export: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Download export
+// @Description File of completed export, available until expires_at of job
+// @Security ApiKeyAuth
+// @Tags exports
+// @Produce application/zip,text/csv
+// @Param id path int true "Export job ID"
+// @Success 200 {file} file "Export file"
+// @Failure 400 {object} models.ProblemDTO "Invalid job ID"
+// @Failure 404 {object} models.ProblemDTO "Job or file not found"
+// @Failure 409 {object} models.ProblemDTO "Export is not completed"
+// @Failure 410 {object} models.ProblemDTO "Export file expired"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/exports/{id}/download [get]
+func (h *Handler) downloadExport(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.downloadExport")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid job ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid job ID", err))
+		return
+	}
+
+	job, err := h.service.Export.GetExportFile(uint(id))
+	if err != nil {
+		log.Error("failed to get export file: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(job.FileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	c.Header("Content-Length", strconv.FormatInt(job.FileSize, 10))
+	c.Status(http.StatusOK)
+
+	log.Info("sending export file")
+	// file may take longer than server WriteTimeout to send, deadline is moved with every chunk
+	if err := h.service.Export.WriteExportFile(job.ID, deadlineWriter{c: c}); err != nil {
+		// once file is being sent client gets response shorter than Content-Length, otherwise problem
+		log.Error("failed to send export file: ", err.Error())
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Length")
+			c.Writer.Header().Del("Content-Disposition")
+		}
+		h.newErrorResponse(c, err)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	fhirContentType = "application/fhir+json"
+	fhirBasePath    = "/info/v1/fhir"
+)
+
+// @Summary Get FHIR Patient
+// @Description Pet as FHIR R4 Patient with animal extension
+// @Security ApiKeyAuth
+// @Tags fhir
+// @Produce application/fhir+json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} models.FHIRPatient "Patient resource"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/fhir/Patient/{id} [get]
+func (h *Handler) getFHIRPatient(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getFHIRPatient")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	patient, err := h.service.FHIR.Patient(uint(id))
+	if err != nil {
+		log.Error("failed to get patient: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	renderFHIR(c, patient)
+}
+
+// @Summary Get FHIR Patient $everything
+// @Description Bundle with Patient, owner as RelatedPerson, vets of card & entries as Practitioner and med entries as
+// @Description Encounter, Condition, Immunization & CarePlan
+// @Security ApiKeyAuth
+// @Tags fhir
+// @Produce application/fhir+json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} models.FHIRBundle "searchset Bundle"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/fhir/Patient/{id}/$everything [get]
+func (h *Handler) getFHIRPatientEverything(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getFHIRPatientEverything")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	bundle, err := h.service.FHIR.PatientEverything(uint(id), fhirBaseURL(c))
+	if err != nil {
+		log.Error("failed to get patient bundle: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	renderFHIR(c, bundle)
+}
+
+func renderFHIR(c *gin.Context, resource interface{}) {
+	// gin keeps content type if it is already set
+	c.Header("Content-Type", fhirContentType)
+	c.JSON(http.StatusOK, resource)
+}
+
+// fhirBaseURL is absolute FHIR base of request. X-Forwarded-* headers are not trusted, behind proxy
+// FHIR_BASE_URL is used instead
+func fhirBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + fhirBasePath
+}
@@ -23,12 +23,13 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.Default()
 	router.Use(cors.New(cors.Config{
 		AllowAllOrigins:  true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", requestIDHeader, idempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", requestIDHeader, idempotentReplayedHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600,
 	}))
+	router.Use(h.requestIDMiddleware, h.errorMiddleware)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -36,23 +37,164 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	{
 		v1 := info.Group("/v1")
 		{
+			v1.POST("/pets:"+customMethodParam, h.customMethod("batch"), h.idempotencyMiddleware, h.createPetsBatch)
+			v1.PUT("/pets:"+customMethodParam, h.customMethod("batch"), h.updatePetsBatch)
 			pets := v1.Group("/pets")
 			{
-				pets.POST("/", h.createPet)
+				pets.POST("/", h.idempotencyMiddleware, h.createPet)
 				pets.GET("/", h.getPets)
 				pets.GET("/:id", h.getPet)
+				pets.GET("/:id/record.pdf", h.getPetRecordPDF)
+				pets.GET("/:id/vaccinations", h.getVaccinations)
+				pets.POST("/:id/vaccinations", h.createVaccination)
+				pets.GET("/:id/medications", h.getActiveMedications)
+				pets.GET("/:id/allergies", h.getAllergies)
+				pets.POST("/:id/allergies", h.createAllergy)
+				pets.DELETE("/:id/allergies/:allergy_id", h.deleteAllergy)
+				pets.GET("/:id/measurements", h.getMeasurements)
+				pets.POST("/:id/measurements", h.createMeasurement)
+				pets.GET("/:id/measurements/trend", h.getWeightTrend)
+				pets.GET("/:id/research-status", h.getResearchStatus)
+				pets.POST("/:id/research-status", h.transitionResearchStatus)
 				pets.PUT("/:id", h.updatePet)
+				pets.PATCH("/:id", h.patchPet)
 				pets.DELETE("/:id", h.deletePet)
 			}
 			medCard := v1.Group("/record")
 			{
+				medCard.POST("/entries:"+customMethodParam,
+					h.customMethod("batch"), h.idempotencyMiddleware, h.createEntriesBatch)
+				medCard.PUT("/entries:"+customMethodParam, h.customMethod("batch"), h.updateEntriesBatch)
 				entries := medCard.Group("/entries")
 				{
 					entries.GET("/", h.getEntries)
-					entries.POST("/", h.createEntry)
+					entries.POST("/", h.idempotencyMiddleware, h.createEntry)
 					entries.DELETE("/", func(context *gin.Context) {})
+					entries.POST("/:id/prescriptions", h.createPrescription)
+					entries.GET("/:id/prescriptions", h.getPrescriptions)
+					entries.GET("/:id/prescriptions/:prescription_id", h.getPrescription)
+					entries.PUT("/:id/prescriptions/:prescription_id", h.updatePrescription)
+					entries.DELETE("/:id/prescriptions/:prescription_id", h.deletePrescription)
 				}
 			}
+			imports := v1.Group("/imports")
+			{
+				imports.POST("/", h.createImport)
+				imports.GET("/:id", h.getImport)
+				imports.POST("/:id/resume", h.resumeImport)
+			}
+			appointments := v1.Group("/appointments")
+			{
+				appointments.POST("/", h.bookAppointment)
+				appointments.GET("/", h.getAppointments)
+				appointments.GET("/:id", h.getAppointment)
+				appointments.POST("/:id/reschedule", h.rescheduleAppointment)
+				appointments.POST("/:id/cancel", h.cancelAppointment)
+				appointments.POST("/:id/check-in", h.checkInAppointment)
+				appointments.POST("/:id/no-show", h.markNoShow)
+				appointments.POST("/:id/complete", h.completeAppointment)
+			}
+			clinics := v1.Group("/clinics")
+			{
+				clinics.GET("/", h.getClinics)
+				clinics.GET("/:number", h.getClinic)
+				clinics.PUT("/:number", h.saveClinic)
+			}
+			vets := v1.Group("/vets")
+			{
+				vets.GET("/:id/schedule", h.getVetSchedule)
+				vets.PUT("/:id/schedule", h.saveVetSchedule)
+				vets.GET("/:id/exceptions", h.getScheduleExceptions)
+				vets.POST("/:id/exceptions", h.createScheduleException)
+				vets.DELETE("/:id/exceptions/:exception_id", h.deleteScheduleException)
+				vets.GET("/:id/blocks", h.getTimeBlocks)
+				vets.POST("/:id/blocks", h.createTimeBlock)
+				vets.DELETE("/:id/blocks/:block_id", h.deleteTimeBlock)
+				vets.GET("/:id/availability", h.getVetAvailability)
+				vets.POST("/:id/calendar-feeds", h.createVetCalendarFeed)
+				vets.GET("/:id/calendar-feeds", h.getVetCalendarFeeds)
+				vets.DELETE("/:id/calendar-feeds/:feed_id", h.revokeVetCalendarFeed)
+			}
+			owners := v1.Group("/owners")
+			{
+				owners.POST("/:id/calendar-feeds", h.createOwnerCalendarFeed)
+				owners.GET("/:id/calendar-feeds", h.getOwnerCalendarFeeds)
+				owners.DELETE("/:id/calendar-feeds/:feed_id", h.revokeOwnerCalendarFeed)
+				owners.GET("/:id/notification-preferences", h.getNotificationPreferences)
+				owners.PUT("/:id/notification-preferences", h.saveNotificationPreferences)
+			}
+			reminders := v1.Group("/reminders")
+			{
+				reminders.POST("/", h.createReminder)
+				reminders.GET("/", h.getReminders)
+				reminders.GET("/:id", h.getReminder)
+				reminders.POST("/:id/cancel", h.cancelReminder)
+			}
+			v1.GET("/notifications", h.getNotifications)
+			admin := v1.Group("/admin")
+			{
+				admin.GET("/jobs", h.getJobs)
+				admin.GET("/jobs/:name/runs", h.getJobRuns)
+				admin.POST("/jobs/:name/run", h.runJob)
+			}
+			v1.GET("/calendar/:token", h.getCalendar)
+			exports := v1.Group("/exports")
+			{
+				exports.POST("/", h.createExport)
+				exports.GET("/:id", h.getExport)
+				exports.GET("/:id/download", h.downloadExport)
+			}
+			vaccines := v1.Group("/vaccines")
+			{
+				vaccines.POST("/", h.createVaccine)
+				vaccines.GET("/", h.getVaccines)
+			}
+			v1.GET("/vaccinations/due", h.getDueVaccinations)
+			allergenSynonyms := v1.Group("/allergen-synonyms")
+			{
+				allergenSynonyms.GET("/", h.getAllergenSynonyms)
+				allergenSynonyms.PUT("/:substance", h.saveAllergenSynonyms)
+				allergenSynonyms.DELETE("/:substance", h.deleteAllergenSynonyms)
+			}
+			doseRanges := v1.Group("/dose-ranges")
+			{
+				doseRanges.POST("/", h.createDoseRange)
+				doseRanges.GET("/", h.getDoseRanges)
+				doseRanges.PUT("/:id", h.updateDoseRange)
+				doseRanges.DELETE("/:id", h.deleteDoseRange)
+			}
+			species := v1.Group("/species")
+			{
+				species.GET("/", h.getSpecies)
+				species.POST("/", h.createSpecies)
+				species.GET("/unmapped", h.getUnmappedSpecies)
+				species.PUT("/:code", h.updateSpecies)
+				species.GET("/:code/breeds", h.getBreeds)
+				species.POST("/:code/breeds", h.createBreed)
+				species.PUT("/:code/breeds/:breed_code", h.updateBreed)
+			}
+			studies := v1.Group("/studies")
+			{
+				studies.POST("/", h.createStudy)
+				studies.GET("/", h.getStudies)
+				studies.GET("/:id", h.getStudy)
+				studies.PUT("/:id", h.updateStudy)
+				studies.POST("/:id/enrollments", h.enrollPet)
+				studies.POST("/:id/enrollments/:pet_id/withdraw", h.withdrawPet)
+				studies.GET("/:id/cohort", h.getCohort)
+			}
+			lab := v1.Group("/lab")
+			{
+				lab.GET("/results", h.getLabResults)
+				lab.GET("/reviews", h.getLabReviews)
+				lab.POST("/reviews/:id/resolve", h.resolveLabReview)
+				lab.POST("/reviews/:id/dismiss", h.dismissLabReview)
+			}
+			fhir := v1.Group("/fhir")
+			{
+				fhir.GET("/Patient/:id", h.getFHIRPatient)
+				fhir.GET("/Patient/:id/$everything", h.getFHIRPatientEverything)
+			}
 			//owner := v1.Group("/owner")
 			//{
 			//	owner.POST("/", h.createOwner)
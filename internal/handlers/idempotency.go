@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// responseRecorder copies response body to replay it later
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyMiddleware replays saved response for requests with same Idempotency-Key.
+// Failed requests release the key so client can retry them
+func (h *Handler) idempotencyMiddleware(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.idempotencyMiddleware")
+
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		h.newErrorResponse(c, errs.Validation("Idempotency-Key is too long", nil))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		h.newErrorResponse(c, errs.Validation("failed to read body", err))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	scope := idempotencyScope(c)
+	rec, replay, err := h.service.Idempotency.Start(scope, key, requestHash(c, body))
+	if err != nil {
+		log.Error("failed to start idempotent request: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+	if replay {
+		log.Info("replaying saved response")
+		c.Header(idempotentReplayedHeader, "true")
+		c.Data(rec.ResponseStatus, rec.ResponseContentType, rec.ResponseBody)
+		c.Abort()
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	c.Next()
+
+	status := recorder.Status()
+	if len(c.Errors) > 0 || !recorder.Written() || status >= http.StatusInternalServerError {
+		if err := h.service.Idempotency.Abort(scope, key); err != nil {
+			log.Error("failed to release idempotency key: ", err.Error())
+		}
+		return
+	}
+
+	err = h.service.Idempotency.Finish(models.IdempotencyRecord{
+		Scope:               scope,
+		Key:                 key,
+		ResponseStatus:      status,
+		ResponseContentType: recorder.Header().Get("Content-Type"),
+		ResponseBody:        recorder.body.Bytes(),
+	})
+	if err != nil {
+		log.Error("failed to save idempotent response: ", err.Error())
+	}
+}
+
+// idempotencyScope identifies client by Authorization header & route by method & path pattern, so same key sent
+// by different clients or to different endpoints does not collide. Header is hashed, it is not stored
+func idempotencyScope(c *gin.Context) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.GetHeader("Authorization") + "\n"))
+	hash.Write([]byte(c.Request.Method + " " + c.FullPath()))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// requestHash identifies request by method, path & body
+func requestHash(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// @Summary Start import
+// @Description Starts async import of historical pets, owners and med entries from CSV or NDJSON.
+// @Description mapping is json {"columns": {"source column": "pet.name"}, "defaults": {"pet.research_status": "candidate"}}.
+// @Description Without mapping source columns should be named as targets. Owners are deduplicated by email or phone
+// @Security ApiKeyAuth
+// @Tags imports
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "CSV or NDJSON file"
+// @Param format formData string false "csv or ndjson, by default taken from file extension"
+// @Param mapping formData string false "Column mapping json"
+// @Param dry_run formData bool false "Only validate rows and report what would be created"
+// @Param default_vet_id formData int false "Vet of pets without card.vet_id column"
+// @Success 202 {object} models.ImportJob "Import started"
+// @Failure 400 {object} models.ProblemDTO "Invalid file, format or mapping"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/imports [post]
+func (h *Handler) createImport(c *gin.Context) {
+	op := "Handler.createImport"
+	log := h.log.WithField("op", op)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Error("failed to get file: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("file is required", err))
+		return
+	}
+
+	job := models.ImportJob{
+		Format: c.PostForm("format"),
+		DryRun: c.PostForm("dry_run") == "true",
+	}
+	if job.Format == "" {
+		job.Format = models.ImportFormatByFilename(fileHeader.Filename)
+	}
+
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &job.Mapping); err != nil {
+			log.Error("failed to parse mapping: ", err.Error())
+			h.newErrorResponse(c, errs.Validation("mapping should be json object", err))
+			return
+		}
+	}
+
+	if vetID := c.PostForm("default_vet_id"); vetID != "" {
+		id, err := strconv.ParseUint(vetID, 10, 32)
+		if err != nil {
+			log.Error("invalid default vet ID: ", err.Error())
+			h.newErrorResponse(c, errs.Validation("invalid default_vet_id", err))
+			return
+		}
+		job.DefaultVetID = uint(id)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error("failed to open file: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to read file", err))
+		return
+	}
+	defer file.Close()
+
+	job, err = h.service.Import.CreateImport(job, file)
+	if err != nil {
+		log.Error("failed to create import: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.WithField("job_id", job.ID).Info("import started")
+	c.Header("Location", fmt.Sprintf("/info/v1/imports/%d", job.ID))
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary Get import
+// @Description Get import status, checkpoint and report
+// @Security ApiKeyAuth
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import ID"
+// @Success 200 {object} models.ImportJob "Import job"
+// @Failure 404 {object} models.ProblemDTO "Import not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/imports/{id} [get]
+func (h *Handler) getImport(c *gin.Context) {
+	op := "Handler.getImport"
+	log := h.log.WithField("op", op)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid import ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid import ID", err))
+		return
+	}
+
+	job, err := h.service.Import.GetImport(uint(id))
+	if err != nil {
+		log.Error("failed to get import: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Resume import
+// @Description Continue failed import from last processed row
+// @Security ApiKeyAuth
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import ID"
+// @Success 202 {object} models.ImportJob "Import resumed"
+// @Failure 404 {object} models.ProblemDTO "Import not found"
+// @Failure 409 {object} models.ProblemDTO "Import is not failed"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/imports/{id}/resume [post]
+func (h *Handler) resumeImport(c *gin.Context) {
+	op := "Handler.resumeImport"
+	log := h.log.WithField("op", op)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid import ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid import ID", err))
+		return
+	}
+
+	job, err := h.service.Import.ResumeImport(uint(id))
+	if err != nil {
+		log.Error("failed to resume import: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.WithField("job_id", job.ID).Info("import resumed")
+	c.JSON(http.StatusAccepted, job)
+}
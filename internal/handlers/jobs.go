@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+)
+
+// @Summary Get jobs
+// @Description Background jobs with schedule, next run & last run
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} []models.ScheduledJob "Jobs"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/admin/jobs [get]
+func (h *Handler) getJobs(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getJobs")
+
+	jobs, err := h.service.Scheduler.GetJobs()
+	if err != nil {
+		log.Error("failed to get jobs: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got jobs")
+	c.JSON(http.StatusOK, jobs)
+}
+
+// @Summary Get job runs
+// @Description Run history of job, latest first. History is kept for 30 days
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} []models.JobRun "Runs"
+// @Failure 400 {object} models.ProblemDTO "Invalid filters"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/admin/jobs/{name}/runs [get]
+func (h *Handler) getJobRuns(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getJobRuns")
+
+	filters, err := http_utils.ParseJobRunFilters(c)
+	if err != nil {
+		log.Error("failed to parse filters: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+	name := c.Param("name")
+	filters.JobName = &name
+
+	runs, err := h.service.Scheduler.GetJobRuns(filters)
+	if err != nil {
+		log.Error("failed to get job runs: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got job runs")
+	c.JSON(http.StatusOK, runs)
+}
+
+// @Summary Run job
+// @Description Starts job now in background without changing its schedule. Poll runs for result
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 202 {object} models.JobRun "Started run"
+// @Failure 404 {object} models.ProblemDTO "Job not found"
+// @Failure 409 {object} models.ProblemDTO "Job is running"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/admin/jobs/{name}/run [post]
+func (h *Handler) runJob(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.runJob")
+
+	run, err := h.service.Scheduler.TriggerJob(c.Param("name"))
+	if err != nil {
+		log.Error("failed to run job: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully started job")
+	c.JSON(http.StatusAccepted, run)
+}
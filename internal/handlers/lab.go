@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Get lab results
+// @Description Results of lab analyzers received over HL7 MLLP, ordered by observation time
+// @Security ApiKeyAuth
+// @Tags lab
+// @Produce json
+// @Param pet_id query int false "Pet ID"
+// @Param offset query int false "offset"
+// @Param limit query int false "limit"
+// @Success 200 {object} []models.LabResult "Lab results"
+// @Failure 400 {object} models.ProblemDTO "failed to parse filters"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/lab/results [get]
+func (h *Handler) getLabResults(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getLabResults")
+
+	filters, err := http_utils.ParseLabResultFilters(c)
+	if err != nil {
+		log.Error("failed to parse filters: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+
+	results, err := h.service.Lab.GetLabResults(filters)
+	if err != nil {
+		log.Error("failed to get lab results: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary Get lab review queue
+// @Description HL7 messages whose patient was not matched to pet, oldest first
+// @Security ApiKeyAuth
+// @Tags lab
+// @Produce json
+// @Success 200 {object} []models.LabMessage "Messages waiting for review"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/lab/reviews [get]
+func (h *Handler) getLabReviews(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getLabReviews")
+
+	messages, err := h.service.Lab.GetLabReviews()
+	if err != nil {
+		log.Error("failed to get lab reviews: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// @Summary Resolve lab review
+// @Description Stores results of message from review queue for chosen pet
+// @Security ApiKeyAuth
+// @Tags lab
+// @Accept json
+// @Produce json
+// @Param id path int true "Lab message ID"
+// @Param input body models.ResolveLabReviewDTO true "Pet of message"
+// @Success 200 {object} models.LabMessage "Resolved message"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or message ID"
+// @Failure 404 {object} models.ProblemDTO "Message or pet medical record not found"
+// @Failure 409 {object} models.ProblemDTO "Message is not in review queue"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/lab/reviews/{id}/resolve [post]
+func (h *Handler) resolveLabReview(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.resolveLabReview")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid lab message ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid lab message ID", err))
+		return
+	}
+
+	var input models.ResolveLabReviewDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateResolvingLabReview(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	msg, err := h.service.Lab.ResolveLabReview(uint(id), input.PetID)
+	if err != nil {
+		log.Error("failed to resolve lab review: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully resolved lab review")
+	c.JSON(http.StatusOK, msg)
+}
+
+// @Summary Dismiss lab review
+// @Description Removes message from review queue without storing results
+// @Security ApiKeyAuth
+// @Tags lab
+// @Produce json
+// @Param id path int true "Lab message ID"
+// @Success 200 {object} models.LabMessage "Dismissed message"
+// @Failure 400 {object} models.ProblemDTO "Invalid message ID"
+// @Failure 404 {object} models.ProblemDTO "Message not found"
+// @Failure 409 {object} models.ProblemDTO "Message is not in review queue"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/lab/reviews/{id}/dismiss [post]
+func (h *Handler) dismissLabReview(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.dismissLabReview")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid lab message ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid lab message ID", err))
+		return
+	}
+
+	msg, err := h.service.Lab.DismissLabReview(uint(id))
+	if err != nil {
+		log.Error("failed to dismiss lab review: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully dismissed lab review")
+	c.JSON(http.StatusOK, msg)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Create measurement
+// @Description Records weigh-in of pet. Pet weight becomes weight of latest measurement
+// @Security ApiKeyAuth
+// @Tags measurements
+// @Accept json
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Param input body models.Measurement true "Weigh-in, measured_at is YYYY-MM-DD, body_condition_score is 1-9"
+// @Success 201 {object} models.Measurement "Created measurement"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/measurements [post]
+func (h *Handler) createMeasurement(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createMeasurement")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	var input models.Measurement
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.PetID = uint(petID)
+
+	if err := validation.ValidateCreatingMeasurement(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	measurement, err := h.service.Measurement.CreateMeasurement(input)
+	if err != nil {
+		log.Error("failed to create measurement: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created measurement")
+	c.JSON(http.StatusCreated, measurement)
+}
+
+// @Summary Get pet measurements
+// @Description Weigh-ins of pet, oldest first
+// @Security ApiKeyAuth
+// @Tags measurements
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} []models.Measurement "Measurements"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/measurements [get]
+func (h *Handler) getMeasurements(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getMeasurements")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	measurements, err := h.service.Measurement.GetMeasurements(uint(petID))
+	if err != nil {
+		log.Error("failed to get measurements: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, measurements)
+}
+
+// @Summary Get weight trend
+// @Description Weight series with percent change from previous weigh-in and from first one.
+// @Description rapid_loss is set if weight dropped more than 10% within 30 days, losses lists such drops
+// @Security ApiKeyAuth
+// @Tags measurements
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} models.WeightTrend "Weight trend"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/measurements/trend [get]
+func (h *Handler) getWeightTrend(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getWeightTrend")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	trend, err := h.service.Measurement.GetWeightTrend(uint(petID))
+	if err != nil {
+		log.Error("failed to get weight trend: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
@@ -1,25 +1,29 @@
 package handlers
 
 import (
-	"database/sql"
-	"errors"
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
 	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+	"github.com/vet-clinic-back/info-service/internal/validation"
 	"net/http"
 )
 
 // @Summary Create med entry
-// @Description Creates a new med entry
+// @Description Creates a new med entry. Vaccinations & recommendation are matched with pet allergies,
+// @Description matches are returned in warnings. High severity match is rejected with allergy_conflict code
+// @Description unless allergy_override_reason is set, override is logged
 // @Security ApiKeyAuth
 // @Tags MedEntry
 // @Accept json
 // @Produce json
-// @Param input body models.MedicalEntry true "entry data"
-// @Success 201 {object} number "Successfully created утекн"
-// @Failure 400 {object} models.ErrorDTO "Invalid input body"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Param input body models.CreatingMedEntry true "entry data"
+// @Param Idempotency-Key header string false "Key to safely retry request. Saved response is replayed"
+// @Failure 409 {object} models.ProblemDTO "Request with same idempotency key is in progress"
+// @Success 201 {object} models.CreatedMedEntryDTO "Successfully created entry"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or high severity allergy conflict"
+// @Failure 422 {object} models.ProblemDTO "Medical record, vet or device does not exist or idempotency key reused"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router /info/v1/record/entries [post]
 func (h *Handler) createEntry(c *gin.Context) {
 	log := h.log.WithField("op", "Handler.createEntry")
@@ -27,49 +31,50 @@ func (h *Handler) createEntry(c *gin.Context) {
 	//petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	//if err != nil {
 	//	log.Error("invalid pet ID: ", err.Error())
-	//	h.newErrorResponse(c, http.StatusBadRequest, "invalid pet ID")
+	//	h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
 	//	return
 	//}
 
-	var input models.MedicalEntry
+	var input models.CreatingMedEntry
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Error("failed to parse json", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid json body")
+		h.newErrorResponse(c, errs.Validation("invalid json body", err))
+		return
+	}
+
+	if err := validation.ValidateCreatingMedEntry(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
 		return
 	}
 
-	id, err := h.service.MedInfo.CreateMedEntry(input)
+	created, err := h.service.MedInfo.CreateMedEntry(input)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23503" {
-				log.Errorf("foreign key constraint failed: %v", err)
-				h.newErrorResponse(c, http.StatusBadRequest, "foreign key constraint failed. U use correct ids?")
-				return
-			}
-		}
-		log.Error("failed to create med entry", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		log.Error("failed to create med entry: ", err.Error())
+		h.newErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, id) // ну ты сам начал тут не json возвращать, я продолжу)
+	c.JSON(http.StatusCreated, created)
 }
 
 // @Summary getEntries
 // @Description Creates a new med entry
+// @Description With Accept application/x-ndjson or text/csv entries are streamed while they are read, one per line
 // @Security ApiKeyAuth
 // @Tags MedEntry
 // @Accept json
-// @Produce json
+// @Produce json,application/x-ndjson,text/csv
 // @Param entry_id query int false "Entry ID"
 // @Param pet_id query int false "Pet ID"
+// @Param vet_id query int false "Vet who made entry"
 // @Param offset query int false "offset"
 // @Param limit query int false "limit"
 // @Success 200 {object} []models.MedicalEntry "Successfully created утекн"
-// @Failure 400 {object} models.ErrorDTO "failed to parse filters"
-// @Failure 404 {object} models.ErrorDTO "Not found"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Failure 400 {object} models.ProblemDTO "failed to parse filters"
+// @Failure 404 {object} models.ProblemDTO "Not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router /info/v1/record/entries [get]
 func (h *Handler) getEntries(c *gin.Context) {
 	log := h.log.WithField("op", "Handler.getEntries")
@@ -77,27 +82,35 @@ func (h *Handler) getEntries(c *gin.Context) {
 	//petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	//if err != nil {
 	//	log.Error("invalid pet ID: ", err.Error())
-	//	h.newErrorResponse(c, http.StatusBadRequest, "invalid pet ID")
+	//	h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
 	//	return
 	//}
 
 	filters, err := http_utils.ParseEntryFilters(c)
 	if err != nil {
 		log.Error("failed to parse filters: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "failed to parse filters")
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
 		return
 	}
 	log.Debug("parsed filters", filters)
 
-	entries, err := h.service.MedInfo.GetMedEntries(filters)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error("pets not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "not found")
+	if format := http_utils.StreamFormat(c); format != "" {
+		err := streamRows(c, format, models.EntryCSVHeader, models.EntryCSVRecord,
+			func(fn func(models.MedicalEntry) error) error {
+				return h.service.MedInfo.IterateMedEntries(filters, fn)
+			})
+		if err != nil {
+			log.Error("failed to stream entries: ", err.Error())
+			h.newErrorResponse(c, err)
 			return
 		}
+		return
+	}
+
+	entries, err := h.service.MedInfo.GetMedEntries(filters)
+	if err != nil {
 		log.Error("failed to get entries", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		h.newErrorResponse(c, err)
 		return
 	}
 
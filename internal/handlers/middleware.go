@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+// requestIDMiddleware takes request id from header or generates new one
+func (h *Handler) requestIDMiddleware(c *gin.Context) {
+	id := c.GetHeader(requestIDHeader)
+	if id == "" || len(id) > 64 {
+		id = newRequestID()
+	}
+
+	c.Set(requestIDKey, id)
+	c.Header(requestIDHeader, id)
+	c.Next()
+}
+
+// errorMiddleware renders last error of request as problem+json
+func (h *Handler) errorMiddleware(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	problem := newProblem(c, err)
+
+	log := h.log.WithField("op", "Handler.errorMiddleware").WithField(requestIDKey, requestID(c))
+	if problem.Status >= 500 {
+		log.Error("request failed: ", err.Error())
+	} else {
+		log.Debug("request rejected: ", err.Error())
+	}
+
+	renderProblem(c, problem)
+}
+
+func requestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
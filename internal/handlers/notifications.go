@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Get notification preferences
+// @Description Channels & language of owner. Owner without saved preferences gets russian emails
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Owner ID"
+// @Success 200 {object} models.NotificationPreferences "Preferences"
+// @Failure 400 {object} models.ProblemDTO "Invalid owner ID"
+// @Failure 404 {object} models.ProblemDTO "Owner not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/owners/{id}/notification-preferences [get]
+func (h *Handler) getNotificationPreferences(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getNotificationPreferences")
+
+	ownerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid owner ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid owner ID", err))
+		return
+	}
+
+	prefs, err := h.service.Notification.GetNotificationPreferences(uint(ownerID))
+	if err != nil {
+		log.Error("failed to get notification preferences: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got notification preferences")
+	c.JSON(http.StatusOK, prefs)
+}
+
+// @Summary Save notification preferences
+// @Description Replaces preferences of owner. language is ru or en, email & sms enable channels,
+// @Description opted_out stops all notifications
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path int true "Owner ID"
+// @Param input body models.NotificationPreferences true "Preferences"
+// @Success 200 {object} models.NotificationPreferences "Saved preferences"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Owner not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/owners/{id}/notification-preferences [put]
+func (h *Handler) saveNotificationPreferences(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.saveNotificationPreferences")
+
+	ownerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid owner ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid owner ID", err))
+		return
+	}
+
+	var input models.NotificationPreferences
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.OwnerID = uint(ownerID)
+
+	if err := validation.ValidateNotificationPreferences(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	prefs, err := h.service.Notification.SaveNotificationPreferences(input)
+	if err != nil {
+		log.Error("failed to save notification preferences: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully saved notification preferences")
+	c.JSON(http.StatusOK, prefs)
+}
+
+// @Summary Create reminder
+// @Description Custom reminder to owner of pet sent at due_at (RFC 3339) over channels of owner
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param input body models.CreatingReminderDTO true "Reminder"
+// @Success 201 {object} models.Reminder "Created reminder"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Med record of pet not found"
+// @Failure 409 {object} models.ProblemDTO "Pet has no owner"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/reminders [post]
+func (h *Handler) createReminder(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createReminder")
+
+	var input models.CreatingReminderDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateCreatingReminder(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	reminder, err := h.service.Notification.CreateReminder(input)
+	if err != nil {
+		log.Error("failed to create reminder: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created reminder")
+	c.JSON(http.StatusCreated, reminder)
+}
+
+// @Summary Get reminders
+// @Description Reminders, latest due first. Reminders of med entries are created by notification scanner
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Produce json
+// @Param owner_id query int false "Owner ID"
+// @Param pet_id query int false "Pet ID"
+// @Param status query string false "pending, sent, skipped or cancelled"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} []models.Reminder "Reminders"
+// @Failure 400 {object} models.ProblemDTO "Invalid filters"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/reminders [get]
+func (h *Handler) getReminders(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getReminders")
+
+	filters, err := http_utils.ParseReminderFilters(c)
+	if err != nil {
+		log.Error("failed to parse filters: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+
+	if err := validation.ValidateReminderFilter(filters); err != nil {
+		log.Error("failed to validate filters: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	reminders, err := h.service.Notification.GetReminders(filters)
+	if err != nil {
+		log.Error("failed to get reminders: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got reminders")
+	c.JSON(http.StatusOK, reminders)
+}
+
+// @Summary Get reminder
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} models.Reminder "Reminder"
+// @Failure 400 {object} models.ProblemDTO "Invalid reminder ID"
+// @Failure 404 {object} models.ProblemDTO "Reminder not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/reminders/{id} [get]
+func (h *Handler) getReminder(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getReminder")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid reminder ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid reminder ID", err))
+		return
+	}
+
+	reminder, err := h.service.Notification.GetReminder(uint(id))
+	if err != nil {
+		log.Error("failed to get reminder: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got reminder")
+	c.JSON(http.StatusOK, reminder)
+}
+
+// @Summary Cancel reminder
+// @Description Cancels reminder that is not sent yet
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} models.Reminder "Cancelled reminder"
+// @Failure 400 {object} models.ProblemDTO "Invalid reminder ID"
+// @Failure 404 {object} models.ProblemDTO "Reminder not found"
+// @Failure 409 {object} models.ProblemDTO "Reminder is already processed"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/reminders/{id}/cancel [post]
+func (h *Handler) cancelReminder(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.cancelReminder")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid reminder ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid reminder ID", err))
+		return
+	}
+
+	reminder, err := h.service.Notification.CancelReminder(uint(id))
+	if err != nil {
+		log.Error("failed to cancel reminder: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully cancelled reminder")
+	c.JSON(http.StatusOK, reminder)
+}
+
+// @Summary Get notifications
+// @Description Deliveries of reminders with status, attempts & last error, latest first
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Produce json
+// @Param owner_id query int false "Owner ID"
+// @Param reminder_id query int false "Reminder ID"
+// @Param status query string false "pending, sent or failed"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} []models.Notification "Notifications"
+// @Failure 400 {object} models.ProblemDTO "Invalid filters"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/notifications [get]
+func (h *Handler) getNotifications(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getNotifications")
+
+	filters, err := http_utils.ParseNotificationFilters(c)
+	if err != nil {
+		log.Error("failed to parse filters: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+
+	if err := validation.ValidateNotificationFilter(filters); err != nil {
+		log.Error("failed to validate filters: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	notifications, err := h.service.Notification.GetNotifications(filters)
+	if err != nil {
+		log.Error("failed to get notifications: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got notifications")
+	c.JSON(http.StatusOK, notifications)
+}
@@ -1,14 +1,13 @@
 package handlers
 
 import (
-	"database/sql"
-	"errors"
-	"github.com/vet-clinic-back/info-service/internal/utils/http-utils"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
 )
 
 // // @Summary Create owner
@@ -18,9 +17,9 @@ import (
 // // @Produce json
 // // @Param input body models.Owner true "owner details"
 // // @Success 201 {object} models.Owner "Successfully created owner"
-// // @Failure 400 {object} models.ErrorDTO "Invalid input body"
-// // @Failure 409 {object} models.ErrorDTO "Owner with same email already exists"
-// // @Failure 500 {object} models.ErrorDTO "Internal server error"
+// // @Failure 400 {object} models.ProblemDTO "Invalid input body"
+// // @Failure 409 {object} models.ProblemDTO "Owner with same email already exists"
+// // @Failure 500 {object} models.ProblemDTO "Internal server error"
 // // @Router /info/v1/owner/ [post]
 func (h *Handler) createOwner(c *gin.Context) {
 	op := "Handler.createOwner"
@@ -29,33 +28,16 @@ func (h *Handler) createOwner(c *gin.Context) {
 	var input models.Owner
 
 	log.Debug("binding json")
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Error("failed to bind json: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
 		return
 	}
 
 	log.Debug("validating input")
-	if err := http_utils.ValidateCreatingOwnerDTO(input); err != nil {
+	if err := validation.ValidateCreatingOwner(input); err != nil {
 		log.Error("failed to validate input: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid input body. all required fields must be present")
-		return
-	}
-
-	log.Debug("checking if owner already exists")
-	_, err := h.service.Info.GetOwner(models.Owner{
-		Email: input.Email,
-		Phone: input.Phone,
-	})
-	if err == nil {
-		log.Error("failed to create new owner. Owner with same email or phone already exists")
-		h.newErrorResponse(c, http.StatusConflict, "owner with same email or phone already exists")
-		return
-	}
-
-	if !errors.Is(err, sql.ErrNoRows) {
-		log.Error("failed to check existing owner: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to check existing owner in db")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -63,7 +45,7 @@ func (h *Handler) createOwner(c *gin.Context) {
 	owner, err := h.service.Info.CreateOwner(input)
 	if err != nil {
 		log.Error("failed to create owner: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to create owner")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -77,8 +59,8 @@ func (h *Handler) createOwner(c *gin.Context) {
 // // @Produce json
 // // @Param id path int true "owner ID"
 // // @Success 200 {object} models.Owner "Successfully retrieved owner"
-// // @Failure 404 {object} models.ErrorDTO "owner not found"
-// // @Failure 500 {object} models.ErrorDTO "Internal server error"
+// // @Failure 404 {object} models.ProblemDTO "owner not found"
+// // @Failure 500 {object} models.ProblemDTO "Internal server error"
 // //  @Router /info/v1/owner/{id} [get]
 func (h *Handler) getOwner(c *gin.Context) {
 	op := "Handler.getOwner"
@@ -87,7 +69,7 @@ func (h *Handler) getOwner(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		log.Error("invalid owner ID: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid owner ID")
+		h.newErrorResponse(c, errs.Validation("invalid owner ID", err))
 		return
 	}
 
@@ -95,13 +77,8 @@ func (h *Handler) getOwner(c *gin.Context) {
 
 	owner, err := h.service.Info.GetOwner(own)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Error("owner not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "owner not found")
-			return
-		}
 		log.Error("failed to get owner: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to get owner")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -114,7 +91,7 @@ func (h *Handler) getOwner(c *gin.Context) {
 // // @Tags owners
 // // @Produce json
 // // @Success 200 {object} models.Owner "Successfully retrieved owners"
-// // @Failure 500 {object} models.ErrorDTO "Internal server error"
+// // @Failure 500 {object} models.ProblemDTO "Internal server error"
 // // @Router  /info/v1/owner [get]
 func (h *Handler) getAllOwners(c *gin.Context) {
 	op := "Handler.getAllOwners"
@@ -124,7 +101,7 @@ func (h *Handler) getAllOwners(c *gin.Context) {
 	owners, err := h.service.Info.GetAllOwners()
 	if err != nil {
 		log.Error("failed to get all owners: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to get all owners")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -140,10 +117,10 @@ func (h *Handler) getAllOwners(c *gin.Context) {
 // // @Param id path int true "owner ID"
 // // @Param input body models.Owner true "owner details"
 // // @Success 200 {object} models.Owner "Successfully updated owner"
-// // @Failure 400 {object} models.ErrorDTO "Invalid input body or owner ID"
-// // @Failure 404 {object} models.ErrorDTO "Owner not found"
-// // @Failure 409 {object} models.ErrorDTO "Owner with same email already exists"
-// // @Failure 500 {object} models.ErrorDTO "Internal server error"
+// // @Failure 400 {object} models.ProblemDTO "Invalid input body or owner ID"
+// // @Failure 404 {object} models.ProblemDTO "Owner not found"
+// // @Failure 409 {object} models.ProblemDTO "Owner with same email already exists"
+// // @Failure 500 {object} models.ProblemDTO "Internal server error"
 // // @Router /info/v1/owner/{id} [put]
 func (h *Handler) updateOwner(c *gin.Context) {
 	op := "Handler.updateOwner"
@@ -152,43 +129,30 @@ func (h *Handler) updateOwner(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		log.Error("invalid owner ID: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid owner ID")
+		h.newErrorResponse(c, errs.Validation("invalid owner ID", err))
 		return
 	}
 
 	var input models.Owner
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Error("failed to bind json: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
 		return
 	}
 
 	input.ID = uint(id)
 
-	// Check for existing owner with the same email (excluding the owner being updated)
-	existingOwner, err := h.service.Info.GetOwner(models.Owner{Email: input.Email})
-	if err == nil {
-		if existingOwner.ID != input.ID { // Check if it's not the same owner
-			log.Error("owner with this email already exists")
-			h.newErrorResponse(c, http.StatusConflict, "owner with this email already exists")
-			return
-		}
-	} else if !errors.Is(err, sql.ErrNoRows) {
-		log.Error("failed to check existing owner: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to check existing owner in db")
+	if err := validation.ValidateUpdatingOwner(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
 		return
 	}
 
 	log.Debug("updating owner")
 	updatedOwner, err := h.service.Info.UpdateOwner(input)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error("owner not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "owner not found")
-			return
-		}
 		log.Error("failed to update owner: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to update owner")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -204,8 +168,8 @@ func (h *Handler) updateOwner(c *gin.Context) {
 // // @Param id path int true "owner ID"
 // // @Param input body models.Owner true "owner details"
 // // @Success 200 {object} models.Owner "Successfully deleted owner"
-// // @Failure 404 {object} models.ErrorDTO "owner not found"
-// // @Failure 500 {object} models.ErrorDTO "Internal server error"
+// // @Failure 404 {object} models.ProblemDTO "owner not found"
+// // @Failure 500 {object} models.ProblemDTO "Internal server error"
 // // @Router /info/v1/owner/{id} [delete]
 func (h *Handler) deleteOwner(c *gin.Context) {
 	op := "Handler.deleteOwner"
@@ -214,20 +178,15 @@ func (h *Handler) deleteOwner(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		log.Error("invalid owner ID: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid owner ID")
+		h.newErrorResponse(c, errs.Validation("invalid owner ID", err))
 		return
 	}
 
 	log.Debug("deleting owner")
 	err = h.service.Info.DeleteOwner(uint(id))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Error("owner not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "owner not found")
-			return
-		}
 		log.Error("failed to delete owner: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to delete owner")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -1,14 +1,16 @@
 package handlers
 
 import (
-	"database/sql"
-	"errors"
+	"encoding/json"
+	"fmt"
 	"github.com/vet-clinic-back/info-service/internal/utils/http-utils"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
 )
 
 type createPetDTO struct {
@@ -18,15 +20,21 @@ type createPetDTO struct {
 }
 
 // @Summary Create Pet
-// @Description Create a new pet in the system. Age & weight should be > 0 & Gender should be 'Male' or 'Female'
+// @Description Create a new pet in the system. birth_date is YYYY-MM-DD, birth_date_estimated marks approximate date.
+// @Description If birth date is unknown age in years is used to estimate it. animal_type & optional breed should be in
+// @Description species catalogue, aliases & localized names are replaced with codes. Weight should be > 0 & Gender should be 'Male' or 'Female'.
+// @Description research_status may be omitted, new pet is always candidate
 // @Security ApiKeyAuth
 // @Tags pets
 // @Accept json
 // @Produce json
 // @Param input body createPetDTO true "Pet details"
+// @Param Idempotency-Key header string false "Key to safely retry request. Saved response is replayed"
 // @Success 201 {object} number "Successfully created pet"
-// @Failure 400 {object} models.ErrorDTO "Invalid input body"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Failure 409 {object} models.ProblemDTO "Request with same idempotency key is in progress"
+// @Failure 422 {object} models.ProblemDTO "Idempotency key reused with another body"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router /info/v1/pets [post]
 func (h *Handler) createPet(c *gin.Context) {
 	op := "Handler.createPet"
@@ -35,17 +43,21 @@ func (h *Handler) createPet(c *gin.Context) {
 	var input createPetDTO
 
 	log.Debug("binding json")
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Error("failed to bind json: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
 		return
 	}
 
 	log.Debug("validating input")
-	if err := http_utils.ValidateCreatingPetDTO(input.Pet); err != nil || input.OwnerID == 0 || input.VetID == 0 {
-		log.Error("failed to validate input")
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid input body. Age & weight should be > 0 & Gender "+
-			"should be 'Male' or 'Female' & vetID with ownerID are required")
+	if err := validation.ValidateCreatingPet(input.Pet); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+	if err := validation.ValidatePetCard(input.OwnerID, input.VetID); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -53,7 +65,7 @@ func (h *Handler) createPet(c *gin.Context) {
 	pet, err := h.service.Info.CreatePetWithCard(input.Pet, input.OwnerID, input.VetID)
 	if err != nil {
 		log.Errorf("failed to create pet: %s", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to create pet")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -68,8 +80,8 @@ func (h *Handler) createPet(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Pet ID"
 // @Success 200 {object} models.Pet "Successfully retrieved pet"
-// @Failure 404 {object} models.ErrorDTO "Pet not found"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router /info/v1/pets/{id} [get]
 func (h *Handler) getPet(c *gin.Context) {
 	op := "Handler.getPet"
@@ -78,7 +90,7 @@ func (h *Handler) getPet(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		log.Error("invalid pet ID: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid pet ID")
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
 		return
 	}
 
@@ -86,13 +98,8 @@ func (h *Handler) getPet(c *gin.Context) {
 
 	pet, err := h.service.Info.GetPet(pt)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error("pet not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "pet not found")
-			return
-		}
 		log.Error("failed to get pet: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to get pet")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -101,18 +108,22 @@ func (h *Handler) getPet(c *gin.Context) {
 }
 
 // @Summary Get all pets
-// @Description Get all pets details
+// @Description Get all pets details. With Accept application/x-ndjson or text/csv pets are streamed while
+// @Description they are read, one per line
 // @Security ApiKeyAuth
 // @Tags pets
 // @Param pet_id query int false "Pet ID"
 // @Param vet_id query int false "Veterinarian ID"
 // @Param owner_id query int false "Owner ID"
+// @Param age_from query int false "Min age in full years"
+// @Param age_to query int false "Max age in full years"
+// @Param research_status query string false "Research stage"
 // @Param offset query int false "offset"
 // @Param limit query int false "limit"
-// @Produce json
+// @Produce json,application/x-ndjson,text/csv
 // @Success 200 {object} []models.OutputPetDTO "Successfully retrieved pets"
-// @Failure 404 {object} models.ErrorDTO "Not found in db"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Failure 404 {object} models.ProblemDTO "Not found in db"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router  /info/v1/pets [get]
 func (h *Handler) getPets(c *gin.Context) {
 	op := "Handler.getPets"
@@ -121,22 +132,35 @@ func (h *Handler) getPets(c *gin.Context) {
 	filters, err := http_utils.ParsePetFilters(c)
 	if err != nil {
 		log.Error("failed to parse filters: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "failed to parse filters")
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+
+	if err := validation.ValidatePetFilter(filters); err != nil {
+		log.Error("failed to validate filters: ", err.Error())
+		h.newErrorResponse(c, err)
 		return
 	}
 
 	log.WithField("filters", filters).Info("filters updated")
 
+	if format := http_utils.StreamFormat(c); format != "" {
+		err := streamRows(c, format, models.PetCSVHeader, models.PetCSVRecord,
+			func(fn func(models.OutputPetDTO) error) error { return h.service.Info.IteratePets(filters, fn) })
+		if err != nil {
+			log.Error("failed to stream pets: ", err.Error())
+			h.newErrorResponse(c, err)
+			return
+		}
+		log.Info("successfully streamed pets")
+		return
+	}
+
 	log.Debug("retrieving all petsWithExtraInfo")
 	petsWithExtraInfo, err := h.service.Info.GetPets(filters)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error("pets not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "not found")
-			return
-		}
 		log.Error("failed to get petsWithExtraInfo with filter: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to get petsWithExtraInfo with filter")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -145,7 +169,8 @@ func (h *Handler) getPets(c *gin.Context) {
 }
 
 // @Summary Update Pet
-// @Description Update pet details by ID
+// @Description Replace pet details by ID. All fields are written, omitted fields are cleared.
+// @Description research_status can not be changed here, use POST /info/v1/pets/{id}/research-status
 // @Security ApiKeyAuth
 // @Tags pets
 // @Accept json
@@ -153,9 +178,10 @@ func (h *Handler) getPets(c *gin.Context) {
 // @Param id path int true "Pet ID"
 // @Param input body models.Pet true "Pet details"
 // @Success 200 {object} models.Pet "Successfully updated pet"
-// @Failure 400 {object} models.ErrorDTO "Invalid input body or pet ID"
-// @Failure 404 {object} models.ErrorDTO "Pet not found"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body or pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 409 {object} models.ProblemDTO "Research status differs from current one"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router /info/v1/pets/{id} [put]
 func (h *Handler) updatePet(c *gin.Context) {
 	op := "Handler.updatePet"
@@ -164,29 +190,31 @@ func (h *Handler) updatePet(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		log.Error("invalid pet ID: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid pet ID")
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
 		return
 	}
 
 	var input models.Pet
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Error("failed to bind json: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
 		return
 	}
 
 	input.ID = uint(id)
 
+	log.Debug("validating input")
+	if err := validation.ValidateUpdatingPet(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
 	log.Debug("updating pet")
 	updatedPet, err := h.service.Info.UpdatePet(input)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error("pet not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "pet not found")
-			return
-		}
 		log.Error("failed to update pet: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to update pet")
+		h.newErrorResponse(c, err)
 		return
 	}
 
@@ -194,6 +222,103 @@ func (h *Handler) updatePet(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedPet)
 }
 
+// @Summary Patch Pet
+// @Description Partially update pet by ID with JSON Merge Patch (RFC 7386). null clears field, absent field is unchanged.
+// @Description research_status can not be changed here, use POST /info/v1/pets/{id}/research-status
+// @Security ApiKeyAuth
+// @Tags pets
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Param input body models.Pet true "Merge patch"
+// @Success 200 {object} models.Pet "Successfully patched pet"
+// @Failure 400 {object} models.ProblemDTO "Invalid patch or pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 409 {object} models.ProblemDTO "Research status differs from current one"
+// @Failure 415 {object} models.ProblemDTO "Unsupported content type"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id} [patch]
+func (h *Handler) patchPet(c *gin.Context) {
+	op := "Handler.patchPet"
+	log := h.log.WithField("op", op)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	if !http_utils.IsMergePatch(c.ContentType()) {
+		log.Error("unsupported content type: ", c.ContentType())
+		h.newErrorResponse(c, errs.UnsupportedMedia("content type should be "+http_utils.MergePatchContentType, nil))
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		log.Error("failed to read body: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	log.Debug("getting current pet")
+	current, err := h.service.Info.GetPet(models.Pet{ID: uint(id)})
+	if err != nil {
+		log.Error("failed to get pet: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	input, err := mergePetPatch(current, patch)
+	if err != nil {
+		log.Error("failed to apply merge patch: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	input.ID = uint(id)
+
+	log.Debug("validating merged pet")
+	if err := validation.ValidateUpdatingPet(input); err != nil {
+		log.Error("failed to validate merged pet: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Debug("updating pet")
+	updatedPet, err := h.service.Info.UpdatePet(input)
+	if err != nil {
+		log.Error("failed to patch pet: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully patched pet")
+	c.JSON(http.StatusOK, updatedPet)
+}
+
+// mergePetPatch applies merge patch to pet. Age & age_months are computed from birth date, they are
+// dropped from current pet so cleared birth date is not estimated back from them
+func mergePetPatch(current models.Pet, patch []byte) (models.Pet, error) {
+	current.Age, current.AgeMonths = 0, 0
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return models.Pet{}, fmt.Errorf("failed to marshal pet: %w", err)
+	}
+
+	merged, err := http_utils.ApplyMergePatch(doc, patch)
+	if err != nil {
+		return models.Pet{}, errs.Validation("invalid merge patch", err)
+	}
+
+	var pet models.Pet
+	if err := json.Unmarshal(merged, &pet); err != nil {
+		return models.Pet{}, errs.Validation("invalid input body", err)
+	}
+	return pet, nil
+}
+
 // @Summary Delete Pet
 // @Description Delete pet details by ID
 // @Security ApiKeyAuth
@@ -202,8 +327,8 @@ func (h *Handler) updatePet(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Pet ID"
 // @Success 200 {object} models.Pet "Successfully deleted pet"
-// @Failure 404 {object} models.ErrorDTO "Pet not found"
-// @Failure 500 {object} models.ErrorDTO "Internal server error"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
 // @Router /info/v1/pets/{id} [delete]
 func (h *Handler) deletePet(c *gin.Context) {
 	op := "Handler.deletePet"
@@ -212,20 +337,15 @@ func (h *Handler) deletePet(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		log.Error("invalid pet ID: ", err.Error())
-		h.newErrorResponse(c, http.StatusBadRequest, "invalid pet ID")
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
 		return
 	}
 
 	log.Debug("deleting pet")
 	err = h.service.Info.DelPetWithCard(uint(id))
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Error("pet not found: ", err.Error())
-			h.newErrorResponse(c, http.StatusNotFound, "pet not found")
-			return
-		}
 		log.Error("failed to delete pet: ", err.Error())
-		h.newErrorResponse(c, http.StatusInternalServerError, "failed to delete pet")
+		h.newErrorResponse(c, err)
 		return
 	}
 
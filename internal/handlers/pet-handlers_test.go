@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+func TestMergePetPatch(t *testing.T) {
+	current := models.Pet{
+		ID: 1, Name: "Rex", Weight: 12.5, BirthDate: "2023-05-10", BirthDateEstimated: true, Age: 3, AgeMonths: 40,
+	}
+
+	tests := []struct {
+		name          string
+		patch         string
+		wantName      string
+		wantBirthDate string
+		wantAge       uint
+		wantErr       error
+	}{
+		{"clears birth date", `{"birth_date": null}`, "Rex", "", 0, nil},
+		{"birth date replaced by age", `{"birth_date": null, "age": 4}`, "Rex", "", 4, nil},
+		{"keeps birth date", `{"name": "Max"}`, "Max", "2023-05-10", 0, nil},
+		{"invalid patch", `[1]`, "", "", 0, errs.ErrValidation},
+		{"invalid field", `{"age": "old"}`, "", "", 0, errs.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergePetPatch(current, []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergePetPatch: %v", err)
+			}
+			if got.Name != tt.wantName || got.BirthDate != tt.wantBirthDate || got.Age != tt.wantAge {
+				t.Errorf("pet = %q born %q age %d, want %q born %q age %d",
+					got.Name, got.BirthDate, got.Age, tt.wantName, tt.wantBirthDate, tt.wantAge)
+			}
+			if got.AgeMonths != 0 {
+				t.Errorf("age_months = %d, want computed field dropped", got.AgeMonths)
+			}
+		})
+	}
+}
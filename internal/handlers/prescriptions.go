@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Create prescription
+// @Description Prescribes drug at medical entry. Dose in mg or mg/kg is checked against pet weight and
+// @Description reference range of drug, dose out of range or dose that can not be checked requires dose_override
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Med entry ID"
+// @Param input body models.Prescription true "Prescription, dates are YYYY-MM-DD"
+// @Success 201 {object} models.Prescription "Created prescription with dose check"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body, dose out of range or unchecked"
+// @Failure 404 {object} models.ProblemDTO "Med entry not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries/{id}/prescriptions [post]
+func (h *Handler) createPrescription(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createPrescription")
+
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid entry ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid entry ID", err))
+		return
+	}
+
+	var input models.Prescription
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.MedicalEntryID = uint(entryID)
+
+	if err := validation.ValidatePrescription(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	prescription, err := h.service.Prescription.CreatePrescription(input)
+	if err != nil {
+		log.Error("failed to create prescription: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created prescription")
+	c.JSON(http.StatusCreated, prescription)
+}
+
+// @Summary Get prescriptions
+// @Description Prescriptions of medical entry with dose checks by current pet weight
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Produce json
+// @Param id path int true "Med entry ID"
+// @Success 200 {object} []models.Prescription "Prescriptions"
+// @Failure 400 {object} models.ProblemDTO "Invalid entry ID"
+// @Failure 404 {object} models.ProblemDTO "Med entry not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries/{id}/prescriptions [get]
+func (h *Handler) getPrescriptions(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getPrescriptions")
+
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid entry ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid entry ID", err))
+		return
+	}
+
+	prescriptions, err := h.service.Prescription.GetPrescriptions(uint(entryID))
+	if err != nil {
+		log.Error("failed to get prescriptions: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prescriptions)
+}
+
+// @Summary Get prescription
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Produce json
+// @Param id path int true "Med entry ID"
+// @Param prescription_id path int true "Prescription ID"
+// @Success 200 {object} models.Prescription "Prescription"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Prescription not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries/{id}/prescriptions/{prescription_id} [get]
+func (h *Handler) getPrescription(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getPrescription")
+
+	entryID, id, err := prescriptionIDs(c)
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	prescription, err := h.service.Prescription.GetPrescription(entryID, id)
+	if err != nil {
+		log.Error("failed to get prescription: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prescription)
+}
+
+// @Summary Update prescription
+// @Description Replaces prescription. Dose is checked against current pet weight again
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Med entry ID"
+// @Param prescription_id path int true "Prescription ID"
+// @Param input body models.Prescription true "Prescription"
+// @Success 200 {object} models.Prescription "Updated prescription"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body, dose out of range or unchecked"
+// @Failure 404 {object} models.ProblemDTO "Prescription not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries/{id}/prescriptions/{prescription_id} [put]
+func (h *Handler) updatePrescription(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.updatePrescription")
+
+	entryID, id, err := prescriptionIDs(c)
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	var input models.Prescription
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.ID = id
+	input.MedicalEntryID = entryID
+
+	if err := validation.ValidatePrescription(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	prescription, err := h.service.Prescription.UpdatePrescription(input)
+	if err != nil {
+		log.Error("failed to update prescription: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully updated prescription")
+	c.JSON(http.StatusOK, prescription)
+}
+
+// @Summary Delete prescription
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Param id path int true "Med entry ID"
+// @Param prescription_id path int true "Prescription ID"
+// @Success 200 "Deleted"
+// @Failure 400 {object} models.ProblemDTO "Invalid ID"
+// @Failure 404 {object} models.ProblemDTO "Prescription not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/record/entries/{id}/prescriptions/{prescription_id} [delete]
+func (h *Handler) deletePrescription(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.deletePrescription")
+
+	entryID, id, err := prescriptionIDs(c)
+	if err != nil {
+		log.Error("invalid ID: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.service.Prescription.DeletePrescription(entryID, id); err != nil {
+		log.Error("failed to delete prescription: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully deleted prescription")
+	c.Status(http.StatusOK)
+}
+
+// @Summary Get active medications
+// @Description Prescriptions of pet active today with dose checks by current weight
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} []models.Prescription "Active prescriptions"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/medications [get]
+func (h *Handler) getActiveMedications(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getActiveMedications")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	medications, err := h.service.Prescription.GetActiveMedications(uint(petID))
+	if err != nil {
+		log.Error("failed to get active medications: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, medications)
+}
+
+// @Summary Create dose range
+// @Description Adds reference dose range of drug in mg/kg. Empty species is range for any species
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Accept json
+// @Produce json
+// @Param input body models.DoseRange true "Dose range"
+// @Success 201 {object} models.DoseRange "Created dose range"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 409 {object} models.ProblemDTO "Range of drug & species exists"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/dose-ranges [post]
+func (h *Handler) createDoseRange(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createDoseRange")
+
+	var input models.DoseRange
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateDoseRange(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	doseRange, err := h.service.Prescription.CreateDoseRange(input)
+	if err != nil {
+		log.Error("failed to create dose range: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created dose range")
+	c.JSON(http.StatusCreated, doseRange)
+}
+
+// @Summary Get dose ranges
+// @Description Reference dose ranges used to check prescriptions
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Produce json
+// @Param drug query string false "Drug, case-insensitive"
+// @Success 200 {object} []models.DoseRange "Dose ranges"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/dose-ranges [get]
+func (h *Handler) getDoseRanges(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getDoseRanges")
+
+	doseRanges, err := h.service.Prescription.GetDoseRanges(c.Query("drug"))
+	if err != nil {
+		log.Error("failed to get dose ranges: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, doseRanges)
+}
+
+// @Summary Update dose range
+// @Description Replaces reference dose range. Existing prescriptions are checked by new range on read
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Dose range ID"
+// @Param input body models.DoseRange true "Dose range"
+// @Success 200 {object} models.DoseRange "Updated dose range"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Dose range not found"
+// @Failure 409 {object} models.ProblemDTO "Range of drug & species exists"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/dose-ranges/{id} [put]
+func (h *Handler) updateDoseRange(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.updateDoseRange")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid dose range ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid dose range ID", err))
+		return
+	}
+
+	var input models.DoseRange
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.ID = uint(id)
+
+	if err := validation.ValidateDoseRange(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	doseRange, err := h.service.Prescription.UpdateDoseRange(input)
+	if err != nil {
+		log.Error("failed to update dose range: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully updated dose range")
+	c.JSON(http.StatusOK, doseRange)
+}
+
+// @Summary Delete dose range
+// @Description Removes reference dose range. Doses of the drug are not checked until a range is added
+// @Security ApiKeyAuth
+// @Tags prescriptions
+// @Param id path int true "Dose range ID"
+// @Success 200 "Deleted"
+// @Failure 400 {object} models.ProblemDTO "Invalid dose range ID"
+// @Failure 404 {object} models.ProblemDTO "Dose range not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/dose-ranges/{id} [delete]
+func (h *Handler) deleteDoseRange(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.deleteDoseRange")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid dose range ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid dose range ID", err))
+		return
+	}
+
+	if err := h.service.Prescription.DeleteDoseRange(uint(id)); err != nil {
+		log.Error("failed to delete dose range: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully deleted dose range")
+	c.Status(http.StatusOK)
+}
+
+func prescriptionIDs(c *gin.Context) (entryID, id uint, err error) {
+	parsedEntryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, 0, errs.Validation("invalid entry ID", err)
+	}
+	parsedID, err := strconv.ParseUint(c.Param("prescription_id"), 10, 32)
+	if err != nil {
+		return 0, 0, errs.Validation("invalid prescription ID", err)
+	}
+	return uint(parsedEntryID), uint(parsedID), nil
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// @Summary Get pet medical history PDF
+// @Description Printable medical history of pet with owner, vet & all med entries in chronological order
+// @Security ApiKeyAuth
+// @Tags pets
+// @Produce application/pdf
+// @Param id path int true "Pet ID"
+// @Success 200 {file} file "PDF document"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet or med record not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/record.pdf [get]
+func (h *Handler) getPetRecordPDF(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getPetRecordPDF")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	// render to buffer so error can still be returned as problem
+	var buf bytes.Buffer
+	if err := h.service.Report.PetRecordPDF(uint(id), &buf); err != nil {
+		log.Error("failed to render pet record: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully rendered pet record")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="pet-%d-record.pdf"`, id))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Change research status
+// @Description Moves pet to next research stage. Allowed: candidate -> consented -> enrolled -> active -> completed,
+// @Description any stage except completed -> withdrawn (reason is required), withdrawn -> candidate.
+// @Description enrolled is reached only by study enrollment, active & completed need active enrollment
+// @Security ApiKeyAuth
+// @Tags research
+// @Accept json
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Param input body models.ResearchTransitionDTO true "Next stage, actor_id is vet ID"
+// @Success 200 {object} models.ResearchStatusDTO "Current status with history"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 409 {object} models.ProblemDTO "Stage can not be reached from current one or needs enrollment"
+// @Failure 422 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/research-status [post]
+func (h *Handler) transitionResearchStatus(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.transitionResearchStatus")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	var input models.ResearchTransitionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateResearchTransition(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	status, err := h.service.Research.TransitionResearchStatus(uint(petID), input)
+	if err != nil {
+		log.Error("failed to change research status: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully changed research status")
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary Get research status
+// @Description Current research stage of pet with transitions, oldest first
+// @Security ApiKeyAuth
+// @Tags research
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} models.ResearchStatusDTO "Current status with history"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/research-status [get]
+func (h *Handler) getResearchStatus(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getResearchStatus")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	status, err := h.service.Research.GetResearchStatus(uint(petID))
+	if err != nil {
+		log.Error("failed to get research status: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got research status")
+	c.JSON(http.StatusOK, status)
+}
@@ -1,10 +1,88 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
 )
 
-func (h *Handler) newErrorResponse(c *gin.Context, statusCode int, message string) {
-	c.AbortWithStatusJSON(statusCode, models.ErrorDTO{Message: message})
+const problemContentType = "application/problem+json"
+
+// problemKind describes how domain error kind is rendered. code is stable, clients rely on it
+type problemKind struct {
+	kind   error
+	status int
+	code   string
+	title  string
+}
+
+var problemKinds = []problemKind{
+	{errs.ErrValidation, http.StatusBadRequest, "validation_failed", "Invalid request"},
+	{errs.ErrNotFound, http.StatusNotFound, "not_found", "Resource not found"},
+	{errs.ErrConflict, http.StatusConflict, "conflict", "Resource conflict"},
+	{errs.ErrForeignKey, http.StatusUnprocessableEntity, "foreign_key_violation", "Referenced resource does not exist"},
+	{errs.ErrForbidden, http.StatusForbidden, "forbidden", "Forbidden"},
+	{errs.ErrUnsupportedMedia, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type"},
+	{errs.ErrKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency key reused"},
+	{errs.ErrRolledBack, http.StatusFailedDependency, "rolled_back", "Rolled back"},
+	{errs.ErrGone, http.StatusGone, "gone", "Resource is gone"},
+}
+
+var internalProblem = problemKind{
+	status: http.StatusInternalServerError,
+	code:   "internal_error",
+	title:  "Internal server error",
+}
+
+// newErrorResponse aborts request with error. Response is rendered by errorMiddleware
+func (h *Handler) newErrorResponse(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// newProblem builds problem+json body for error. Details of unknown errors are not exposed
+func newProblem(c *gin.Context, err error) models.ProblemDTO {
+	kind := internalProblem
+	for _, k := range problemKinds {
+		if errors.Is(err, k.kind) {
+			kind = k
+			break
+		}
+	}
+
+	problem := models.ProblemDTO{
+		Type:     "/problems/" + kind.code,
+		Title:    kind.title,
+		Status:   kind.status,
+		Code:     kind.code,
+		Instance: "urn:request:" + requestID(c),
+	}
+
+	if kind.status != http.StatusInternalServerError {
+		problem.Detail = errs.Detail(err)
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		problem.Detail = "invalid input body"
+		for _, fieldErr := range fieldErrs {
+			problem.Fields = append(problem.Fields, models.FieldErrorDTO{
+				Field:   fieldErr.Field,
+				Code:    fieldErr.Code,
+				Message: fieldErr.Message,
+			})
+		}
+	}
+
+	return problem
+}
+
+func renderProblem(c *gin.Context, problem models.ProblemDTO) {
+	// gin keeps content type if it is already set
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
 }
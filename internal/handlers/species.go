@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Get species
+// @Description Species catalogue with localized names & aliases. Pet animal_type is species code
+// @Security ApiKeyAuth
+// @Tags species
+// @Produce json
+// @Success 200 {object} []models.Species "Species"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species [get]
+func (h *Handler) getSpecies(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getSpecies")
+
+	species, err := h.service.Species.GetSpecies()
+	if err != nil {
+		log.Error("failed to get species: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, species)
+}
+
+// @Summary Create species
+// @Description Adds species to catalogue. code is lower snake case, names are keyed by language
+// @Security ApiKeyAuth
+// @Tags species
+// @Accept json
+// @Produce json
+// @Param input body models.Species true "Species"
+// @Success 201 {object} models.Species "Created species"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 409 {object} models.ProblemDTO "Species or alias exists"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species [post]
+func (h *Handler) createSpecies(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createSpecies")
+
+	var input models.Species
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateSpecies(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	species, err := h.service.Species.CreateSpecies(input)
+	if err != nil {
+		log.Error("failed to create species: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created species")
+	c.JSON(http.StatusCreated, species)
+}
+
+// @Summary Update species
+// @Description Replaces names & aliases of species. Code can not be changed
+// @Security ApiKeyAuth
+// @Tags species
+// @Accept json
+// @Produce json
+// @Param code path string true "Species code"
+// @Param input body models.Species true "Species"
+// @Success 200 {object} models.Species "Updated species"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Species not found"
+// @Failure 409 {object} models.ProblemDTO "Alias belongs to another species"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species/{code} [put]
+func (h *Handler) updateSpecies(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.updateSpecies")
+
+	var input models.Species
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.Code = c.Param("code")
+
+	if err := validation.ValidateSpecies(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	species, err := h.service.Species.UpdateSpecies(input)
+	if err != nil {
+		log.Error("failed to update species: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully updated species")
+	c.JSON(http.StatusOK, species)
+}
+
+// @Summary Get breeds
+// @Description Breeds of species. Pet breed is breed code
+// @Security ApiKeyAuth
+// @Tags species
+// @Produce json
+// @Param code path string true "Species code"
+// @Success 200 {object} []models.Breed "Breeds"
+// @Failure 404 {object} models.ProblemDTO "Species not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species/{code}/breeds [get]
+func (h *Handler) getBreeds(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getBreeds")
+
+	breeds, err := h.service.Species.GetBreeds(c.Param("code"))
+	if err != nil {
+		log.Error("failed to get breeds: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, breeds)
+}
+
+// @Summary Create breed
+// @Description Adds breed of species to catalogue
+// @Security ApiKeyAuth
+// @Tags species
+// @Accept json
+// @Produce json
+// @Param code path string true "Species code"
+// @Param input body models.Breed true "Breed"
+// @Success 201 {object} models.Breed "Created breed"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Species not found"
+// @Failure 409 {object} models.ProblemDTO "Breed exists"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species/{code}/breeds [post]
+func (h *Handler) createBreed(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createBreed")
+
+	var input models.Breed
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.SpeciesCode = c.Param("code")
+
+	if err := validation.ValidateBreed(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	breed, err := h.service.Species.CreateBreed(input)
+	if err != nil {
+		log.Error("failed to create breed: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created breed")
+	c.JSON(http.StatusCreated, breed)
+}
+
+// @Summary Update breed
+// @Description Replaces names of breed. Code can not be changed
+// @Security ApiKeyAuth
+// @Tags species
+// @Accept json
+// @Produce json
+// @Param code path string true "Species code"
+// @Param breed_code path string true "Breed code"
+// @Param input body models.Breed true "Breed"
+// @Success 200 {object} models.Breed "Updated breed"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Breed not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species/{code}/breeds/{breed_code} [put]
+func (h *Handler) updateBreed(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.updateBreed")
+
+	var input models.Breed
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.SpeciesCode = c.Param("code")
+	input.Code = c.Param("breed_code")
+
+	if err := validation.ValidateBreed(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	breed, err := h.service.Species.UpdateBreed(input)
+	if err != nil {
+		log.Error("failed to update breed: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully updated breed")
+	c.JSON(http.StatusOK, breed)
+}
+
+// @Summary Get unmapped species
+// @Description Animal types of pets that are not species codes, left by normalization migration. Such pets
+// @Description should be fixed with PUT /pets/{id} before other updates
+// @Security ApiKeyAuth
+// @Tags species
+// @Produce json
+// @Success 200 {object} []models.UnmappedSpecies "Unmapped animal types with pet counts"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/species/unmapped [get]
+func (h *Handler) getUnmappedSpecies(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getUnmappedSpecies")
+
+	unmapped, err := h.service.Species.GetUnmappedSpecies()
+	if err != nil {
+		log.Error("failed to get unmapped species: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unmapped)
+}
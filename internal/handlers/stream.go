@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+)
+
+// streamFlushRows is how many rows are buffered before they are flushed to client
+const streamFlushRows = 100
+
+// streamWriteTimeout is how long one flush of long response may take. Server WriteTimeout limits whole
+// response, streams move write deadline forward before every flush instead, so only stalled client is cut off
+const streamWriteTimeout = 30 * time.Second
+
+// streamError is last NDJSON line of stream failed after first flush
+type streamError struct {
+	Error models.ProblemDTO `json:"error"`
+}
+
+// streamRows writes rows passed by iterate as NDJSON or CSV as soon as they are read. Nothing is sent until
+// buffer is flushed, so error of query is still rendered as problem. Error after that cuts response short,
+// NDJSON stream then ends with {"error": problem} line
+func streamRows[T any](
+	c *gin.Context, format string, csvHeader []string, csvRecord func(T) []string,
+	iterate func(fn func(T) error) error,
+) error {
+	if err := extendWriteDeadline(c); err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(c.Writer)
+
+	var write func(T) error
+	if format == http_utils.CSVContentType {
+		c.Header("Content-Type", format+"; charset=utf-8")
+		// csv writer reuses buf, flushing buf flushes written records
+		writer := csv.NewWriter(buf)
+		if err := writer.Write(csvHeader); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		write = func(row T) error { return writer.Write(csvRecord(row)) }
+	} else {
+		c.Header("Content-Type", format)
+		encoder := json.NewEncoder(buf)
+		write = func(row T) error { return encoder.Encode(row) }
+	}
+	c.Status(http.StatusOK)
+
+	rows := 0
+	flush := func() error {
+		if err := extendWriteDeadline(c); err != nil {
+			return err
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	err := iterate(func(row T) error {
+		if err := write(row); err != nil {
+			return err
+		}
+		rows++
+		if rows%streamFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if c.Writer.Written() && format == http_utils.NDJSONContentType {
+			// rows buffered since last flush are dropped, error line follows complete rows only
+			buf.Reset(c.Writer)
+			if encodeErr := json.NewEncoder(buf).Encode(streamError{Error: newProblem(c, err)}); encodeErr == nil {
+				_ = flush()
+			}
+		}
+		return err
+	}
+	return flush()
+}
+
+// extendWriteDeadline moves write deadline of response streamWriteTimeout forward
+func extendWriteDeadline(c *gin.Context) error {
+	err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+// deadlineWriter moves write deadline of response forward before every write of long response
+type deadlineWriter struct {
+	c *gin.Context
+}
+
+func (w deadlineWriter) Write(data []byte) (int, error) {
+	if err := extendWriteDeadline(w.c); err != nil {
+		return 0, err
+	}
+	return w.c.Writer.Write(data)
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Create study
+// @Description Creates research study. Dates are YYYY-MM-DD, end_date may be omitted for open ended study
+// @Security ApiKeyAuth
+// @Tags studies
+// @Accept json
+// @Produce json
+// @Param input body models.Study true "Study"
+// @Success 201 {object} models.Study "Created study"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 409 {object} models.ProblemDTO "Study with same protocol_id already exists"
+// @Failure 422 {object} models.ProblemDTO "Principal vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies [post]
+func (h *Handler) createStudy(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createStudy")
+
+	var input models.Study
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateStudy(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	study, err := h.service.Research.CreateStudy(input)
+	if err != nil {
+		log.Error("failed to create study: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created study")
+	c.JSON(http.StatusCreated, study)
+}
+
+// @Summary Get studies
+// @Description Research studies, latest started first
+// @Security ApiKeyAuth
+// @Tags studies
+// @Produce json
+// @Success 200 {object} []models.Study "Studies"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies [get]
+func (h *Handler) getStudies(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getStudies")
+
+	studies, err := h.service.Research.GetStudies()
+	if err != nil {
+		log.Error("failed to get studies: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got studies")
+	c.JSON(http.StatusOK, studies)
+}
+
+// @Summary Get study
+// @Security ApiKeyAuth
+// @Tags studies
+// @Produce json
+// @Param id path int true "Study ID"
+// @Success 200 {object} models.Study "Study"
+// @Failure 400 {object} models.ProblemDTO "Invalid study ID"
+// @Failure 404 {object} models.ProblemDTO "Study not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies/{id} [get]
+func (h *Handler) getStudy(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getStudy")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid study ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid study ID", err))
+		return
+	}
+
+	study, err := h.service.Research.GetStudy(uint(id))
+	if err != nil {
+		log.Error("failed to get study: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got study")
+	c.JSON(http.StatusOK, study)
+}
+
+// @Summary Update study
+// @Description Replaces study. All fields are written
+// @Security ApiKeyAuth
+// @Tags studies
+// @Accept json
+// @Produce json
+// @Param id path int true "Study ID"
+// @Param input body models.Study true "Study"
+// @Success 200 {object} models.Study "Updated study"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Study not found"
+// @Failure 409 {object} models.ProblemDTO "Study with same protocol_id already exists"
+// @Failure 422 {object} models.ProblemDTO "Principal vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies/{id} [put]
+func (h *Handler) updateStudy(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.updateStudy")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid study ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid study ID", err))
+		return
+	}
+
+	var input models.Study
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.ID = uint(id)
+
+	if err := validation.ValidateStudy(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	study, err := h.service.Research.UpdateStudy(input)
+	if err != nil {
+		log.Error("failed to update study: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully updated study")
+	c.JSON(http.StatusOK, study)
+}
+
+// @Summary Enroll pet
+// @Description Enrolls pet in study arm. Pet research status is moved to enrolled, pet already enrolled
+// @Description or active in another study keeps its status
+// @Security ApiKeyAuth
+// @Tags studies
+// @Accept json
+// @Produce json
+// @Param id path int true "Study ID"
+// @Param input body models.EnrollingPetDTO true "Enrollment, consent_date is YYYY-MM-DD, actor_id is vet ID"
+// @Success 201 {object} models.Enrollment "Created enrollment"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Study or pet not found"
+// @Failure 409 {object} models.ProblemDTO "Pet already enrolled, study ended or pet can not be enrolled"
+// @Failure 422 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies/{id}/enrollments [post]
+func (h *Handler) enrollPet(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.enrollPet")
+
+	studyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid study ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid study ID", err))
+		return
+	}
+
+	var input models.EnrollingPetDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateEnrollingPet(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	enrollment, err := h.service.Research.EnrollPet(uint(studyID), input)
+	if err != nil {
+		log.Error("failed to enroll pet: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully enrolled pet")
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// @Summary Withdraw pet
+// @Description Ends enrollment of pet. Pet without other active enrollments becomes withdrawn
+// @Security ApiKeyAuth
+// @Tags studies
+// @Accept json
+// @Produce json
+// @Param id path int true "Study ID"
+// @Param pet_id path int true "Pet ID"
+// @Param input body models.WithdrawingPetDTO true "Withdrawal, actor_id is vet ID"
+// @Success 200 {object} models.Enrollment "Withdrawn enrollment"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Active enrollment not found"
+// @Failure 422 {object} models.ProblemDTO "Vet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies/{id}/enrollments/{pet_id}/withdraw [post]
+func (h *Handler) withdrawPet(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.withdrawPet")
+
+	studyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid study ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid study ID", err))
+		return
+	}
+	petID, err := strconv.ParseUint(c.Param("pet_id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	var input models.WithdrawingPetDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateWithdrawingPet(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	enrollment, err := h.service.Research.WithdrawPet(uint(studyID), uint(petID), input)
+	if err != nil {
+		log.Error("failed to withdraw pet: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully withdrew pet")
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// @Summary Get study cohort
+// @Description Enrolled pets including withdrawn ones, each with medical entries made between study start & end
+// @Security ApiKeyAuth
+// @Tags studies
+// @Produce json
+// @Param id path int true "Study ID"
+// @Success 200 {object} models.CohortDTO "Cohort"
+// @Failure 400 {object} models.ProblemDTO "Invalid study ID"
+// @Failure 404 {object} models.ProblemDTO "Study not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/studies/{id}/cohort [get]
+func (h *Handler) getCohort(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getCohort")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid study ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid study ID", err))
+		return
+	}
+
+	cohort, err := h.service.Research.GetCohort(uint(id))
+	if err != nil {
+		log.Error("failed to get cohort: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully got cohort")
+	c.JSON(http.StatusOK, cohort)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	http_utils "github.com/vet-clinic-back/info-service/internal/utils/http-utils"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// @Summary Create vaccine
+// @Description Adds vaccine to catalogue. revaccination_months = 0 means vaccine is given once
+// @Security ApiKeyAuth
+// @Tags vaccinations
+// @Accept json
+// @Produce json
+// @Param input body models.Vaccine true "Vaccine"
+// @Success 201 {object} number "Created vaccine ID"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 409 {object} models.ProblemDTO "Vaccine with same name & species exists"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vaccines [post]
+func (h *Handler) createVaccine(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createVaccine")
+
+	var input models.Vaccine
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+
+	if err := validation.ValidateCreatingVaccine(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	id, err := h.service.Vaccination.CreateVaccine(input)
+	if err != nil {
+		log.Error("failed to create vaccine: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created vaccine")
+	c.JSON(http.StatusCreated, id)
+}
+
+// @Summary Get vaccines
+// @Description Vaccine catalogue
+// @Security ApiKeyAuth
+// @Tags vaccinations
+// @Produce json
+// @Param species query string false "Species, e.g. dog"
+// @Success 200 {object} []models.Vaccine "Vaccines"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vaccines [get]
+func (h *Handler) getVaccines(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getVaccines")
+
+	vaccines, err := h.service.Vaccination.GetVaccines(c.Query("species"))
+	if err != nil {
+		log.Error("failed to get vaccines: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vaccines)
+}
+
+// @Summary Create vaccination
+// @Description Records administered dose. Vaccine should be for pet species
+// @Security ApiKeyAuth
+// @Tags vaccinations
+// @Accept json
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Param input body models.Vaccination true "Dose, administered_at is YYYY-MM-DD"
+// @Success 201 {object} models.Vaccination "Created vaccination with next due date"
+// @Failure 400 {object} models.ProblemDTO "Invalid input body. fields contains invalid fields"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 422 {object} models.ProblemDTO "Vaccine or vet does not exist"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/vaccinations [post]
+func (h *Handler) createVaccination(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.createVaccination")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	var input models.Vaccination
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("failed to bind json: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid input body", err))
+		return
+	}
+	input.PetID = uint(petID)
+
+	if err := validation.ValidateCreatingVaccination(input); err != nil {
+		log.Error("failed to validate input: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	vaccination, err := h.service.Vaccination.CreateVaccination(input)
+	if err != nil {
+		log.Error("failed to create vaccination: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	log.Info("successfully created vaccination")
+	c.JSON(http.StatusCreated, vaccination)
+}
+
+// @Summary Get pet vaccinations
+// @Description Administered doses of pet with next due dates, latest first
+// @Security ApiKeyAuth
+// @Tags vaccinations
+// @Produce json
+// @Param id path int true "Pet ID"
+// @Success 200 {object} []models.Vaccination "Vaccinations"
+// @Failure 400 {object} models.ProblemDTO "Invalid pet ID"
+// @Failure 404 {object} models.ProblemDTO "Pet not found"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/pets/{id}/vaccinations [get]
+func (h *Handler) getVaccinations(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getVaccinations")
+
+	petID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.Error("invalid pet ID: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("invalid pet ID", err))
+		return
+	}
+
+	vaccinations, err := h.service.Vaccination.GetVaccinations(uint(petID))
+	if err != nil {
+		log.Error("failed to get vaccinations: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vaccinations)
+}
+
+// @Summary Get due vaccinations
+// @Description Overdue or upcoming revaccinations of clinic computed from latest dose of each vaccine
+// @Security ApiKeyAuth
+// @Tags vaccinations
+// @Produce json
+// @Param status query string true "overdue or upcoming"
+// @Param within_days query int false "Window of upcoming vaccinations, default 30"
+// @Param vet_id query int false "Veterinarian ID"
+// @Param species query string false "Species"
+// @Param vaccine_id query int false "Vaccine ID"
+// @Param offset query int false "offset"
+// @Param limit query int false "limit"
+// @Success 200 {object} []models.DueVaccination "Due vaccinations ordered by due date"
+// @Failure 400 {object} models.ProblemDTO "Invalid filters"
+// @Failure 500 {object} models.ProblemDTO "Internal server error"
+// @Router /info/v1/vaccinations/due [get]
+func (h *Handler) getDueVaccinations(c *gin.Context) {
+	log := h.log.WithField("op", "Handler.getDueVaccinations")
+
+	filters, err := http_utils.ParseVaccinationDueFilters(c)
+	if err != nil {
+		log.Error("failed to parse filters: ", err.Error())
+		h.newErrorResponse(c, errs.Validation("failed to parse filters", err))
+		return
+	}
+
+	if err := validation.ValidateVaccinationDueFilter(filters); err != nil {
+		log.Error("failed to validate filters: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	due, err := h.service.Vaccination.GetDueVaccinations(filters)
+	if err != nil {
+		log.Error("failed to get due vaccinations: ", err.Error())
+		h.newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, due)
+}
@@ -0,0 +1,63 @@
+package hl7
+
+import (
+	"strings"
+	"time"
+)
+
+// Acknowledgment codes of MSA-1
+const (
+	AckAccept = "AA"
+	AckError  = "AE"
+	AckReject = "AR"
+)
+
+// ACK builds acknowledgement of message. msg can be nil if message could not be parsed,
+// then default delimiters & empty control id are used
+func ACK(msg *Message, code, text string, now time.Time) []byte {
+	delims := DefaultDelimiters
+	var sendingApp, sendingFacility, receivingApp, receivingFacility, controlID, trigger, version string
+	if msg != nil {
+		delims = msg.Delimiters
+		msh := msg.MSH()
+		sendingApp, sendingFacility = msh.Field(3), msh.Field(4)
+		receivingApp, receivingFacility = msh.Field(5), msh.Field(6)
+		controlID = msh.Field(10)
+		trigger = msh.Component(9, 2)
+		version = msh.Field(12)
+	}
+	if version == "" {
+		version = "2.5"
+	}
+
+	messageType := "ACK"
+	if trigger != "" {
+		messageType += string(delims.Component) + trigger + string(delims.Component) + "ACK"
+	}
+
+	field := string(delims.Field)
+	encoding := string([]byte{delims.Component, delims.Repetition, delims.Escape, delims.Subcomponent})
+
+	// receiver of message becomes sender of acknowledgement
+	msh := []string{
+		"MSH", encoding,
+		delims.EscapeText(receivingApp), delims.EscapeText(receivingFacility),
+		delims.EscapeText(sendingApp), delims.EscapeText(sendingFacility),
+		FormatTime(now), "",
+		messageType,
+		"A" + now.Format("060102150405.000"), "P", version,
+	}
+	msa := []string{"MSA", code, delims.EscapeText(controlID), delims.EscapeText(text)}
+
+	return []byte(strings.Join(msh, field) + "\r" + strings.Join(msa, field) + "\r")
+}
+
+// AckCode returns MSA-1 & MSA-3 of acknowledgement
+func AckCode(ack *Message) (code, text string) {
+	for _, segment := range ack.Segments {
+		if segment.Name == "MSA" {
+			return segment.Field(1), segment.Field(3)
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,243 @@
+// Package hl7 parses HL7 v2 pipe-delimited messages and builds acknowledgements
+package hl7
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmptyMessage = errors.New("hl7: empty message")
+	ErrNoMSH        = errors.New("hl7: message should start with MSH segment")
+)
+
+// Delimiters are taken from MSH-1 & MSH-2 of every message
+type Delimiters struct {
+	Field        byte
+	Component    byte
+	Repetition   byte
+	Escape       byte
+	Subcomponent byte
+}
+
+var DefaultDelimiters = Delimiters{Field: '|', Component: '^', Repetition: '~', Escape: '\\', Subcomponent: '&'}
+
+type Message struct {
+	Delimiters Delimiters
+	Segments   []Segment
+}
+
+// Segment keeps raw fields. fields[0] is segment name, fields[n] is n-th field
+type Segment struct {
+	Name   string
+	fields []string
+	delims *Delimiters
+}
+
+// Parse parses message. Segments are separated by CR, LF is accepted for files edited by hand
+func Parse(raw []byte) (*Message, error) {
+	text := strings.ReplaceAll(string(raw), "\r\n", "\r")
+	text = strings.ReplaceAll(text, "\n", "\r")
+	text = strings.Trim(text, "\r")
+	if text == "" {
+		return nil, ErrEmptyMessage
+	}
+	if !strings.HasPrefix(text, "MSH") || len(text) < 8 {
+		return nil, ErrNoMSH
+	}
+
+	msg := &Message{Delimiters: Delimiters{
+		Field:        text[3],
+		Component:    text[4],
+		Repetition:   text[5],
+		Escape:       text[6],
+		Subcomponent: text[7],
+	}}
+
+	for _, line := range strings.Split(text, "\r") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, string(msg.Delimiters.Field))
+		if fields[0] == "MSH" {
+			// MSH-1 is field separator itself, so MSH fields are shifted by one
+			fields = append([]string{"MSH", string(msg.Delimiters.Field)}, fields[1:]...)
+		}
+		msg.Segments = append(msg.Segments, Segment{Name: fields[0], fields: fields, delims: &msg.Delimiters})
+	}
+
+	return msg, nil
+}
+
+// MSH returns message header
+func (m *Message) MSH() Segment {
+	return m.Segments[0]
+}
+
+// Type is MSH-9 as event, e.g. ORU^R01
+func (m *Message) Type() string {
+	msh := m.MSH()
+	return msh.Component(9, 1) + "^" + msh.Component(9, 2)
+}
+
+// ControlID is MSH-10, unique id of message at sender
+func (m *Message) ControlID() string {
+	return m.MSH().Field(10)
+}
+
+// Field returns unescaped n-th field. Only first repetition is returned
+func (s Segment) Field(n int) string {
+	return s.Component(n, 1)
+}
+
+// Component returns unescaped component of first repetition of n-th field, components start at 1
+func (s Segment) Component(n, component int) string {
+	reps := s.Repetitions(n)
+	if len(reps) == 0 {
+		return ""
+	}
+	return reps[0].Component(component)
+}
+
+// Repetitions returns all repetitions of n-th field
+func (s Segment) Repetitions(n int) []Value {
+	if n <= 0 || n >= len(s.fields) || s.fields[n] == "" {
+		return nil
+	}
+	if s.Name == "MSH" && n <= 2 {
+		return []Value{{raw: s.fields[n], delims: &Delimiters{}}}
+	}
+
+	var values []Value
+	for _, rep := range strings.Split(s.fields[n], string(s.delims.Repetition)) {
+		values = append(values, Value{raw: rep, delims: s.delims})
+	}
+	return values
+}
+
+// Value is one repetition of field
+type Value struct {
+	raw    string
+	delims *Delimiters
+}
+
+// Component returns unescaped component, components start at 1
+func (v Value) Component(n int) string {
+	if v.delims.Component == 0 {
+		if n == 1 {
+			return v.raw
+		}
+		return ""
+	}
+	components := strings.Split(v.raw, string(v.delims.Component))
+	if n <= 0 || n > len(components) {
+		return ""
+	}
+	// subcomponents are not used by clinic, first one is taken
+	value := strings.SplitN(components[n-1], string(v.delims.Subcomponent), 2)[0]
+	return v.delims.Unescape(value)
+}
+
+// Unescape replaces \F\ \S\ \T\ \R\ \E\ & \.br\ escape sequences
+func (d Delimiters) Unescape(value string) string {
+	if d.Escape == 0 || strings.IndexByte(value, d.Escape) < 0 {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != d.Escape {
+			b.WriteByte(value[i])
+			continue
+		}
+		end := strings.IndexByte(value[i+1:], d.Escape)
+		if end < 0 {
+			b.WriteString(value[i:])
+			break
+		}
+		seq := value[i+1 : i+1+end]
+		switch seq {
+		case "F":
+			b.WriteByte(d.Field)
+		case "S":
+			b.WriteByte(d.Component)
+		case "T":
+			b.WriteByte(d.Subcomponent)
+		case "R":
+			b.WriteByte(d.Repetition)
+		case "E":
+			b.WriteByte(d.Escape)
+		case ".br":
+			b.WriteByte('\n')
+		default:
+			// unknown sequences like hex or formatting are kept as is
+			b.WriteString(value[i : i+end+2])
+		}
+		i += end + 1
+	}
+	return b.String()
+}
+
+// EscapeText is opposite of Unescape, it is used for text put into acknowledgements
+func (d Delimiters) EscapeText(value string) string {
+	replacer := strings.NewReplacer(
+		string(d.Escape), string([]byte{d.Escape, 'E', d.Escape}),
+		string(d.Field), string([]byte{d.Escape, 'F', d.Escape}),
+		string(d.Component), string([]byte{d.Escape, 'S', d.Escape}),
+		string(d.Subcomponent), string([]byte{d.Escape, 'T', d.Escape}),
+		string(d.Repetition), string([]byte{d.Escape, 'R', d.Escape}),
+		"\r", " ", "\n", " ",
+	)
+	return replacer.Replace(value)
+}
+
+// SegmentsByName returns all segments with name in order of message
+func (m *Message) SegmentsByName(name string) []Segment {
+	var segments []Segment
+	for _, segment := range m.Segments {
+		if segment.Name == name {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// ParseTime parses HL7 TS/DTM: YYYY[MM[DD[HH[MM[SS[.S+]]]]]][+/-ZZZZ]. Time without zone is in loc,
+// analyzers send local time of clinic
+func ParseTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("hl7: empty time")
+	}
+
+	zone := ""
+	if i := strings.IndexAny(value, "+-"); i >= 0 {
+		value, zone = value[:i], value[i:]
+	}
+	fraction := ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value, fraction = value[:i], value[i:]
+	}
+
+	layouts := map[int]string{4: "2006", 6: "200601", 8: "20060102", 10: "2006010215", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("hl7: invalid time %q", value)
+	}
+	if fraction != "" && len(value) == 14 {
+		layout += "." + strings.Repeat("0", len(fraction)-1)
+		value += fraction
+	}
+
+	if zone == "" {
+		return time.ParseInLocation(layout, value, loc)
+	}
+	return time.Parse(layout+"-0700", value+zone)
+}
+
+// FormatTime formats time as HL7 DTM with seconds & zone
+func FormatTime(t time.Time) string {
+	return t.Format("20060102150405-0700")
+}
@@ -0,0 +1,120 @@
+package hl7
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		segments []string
+		err      error
+	}{
+		{"empty", "\r\n", nil, ErrEmptyMessage},
+		{"no MSH", "PID|1||42\r", nil, ErrNoMSH},
+		{"CR separated", "MSH|^~\\&|A\rPID|1\rOBX|1\r", []string{"MSH", "PID", "OBX"}, nil},
+		{"LF separated", "MSH|^~\\&|A\nPID|1\n\nOBX|1\n", []string{"MSH", "PID", "OBX"}, nil},
+		{"CRLF separated", "MSH|^~\\&|A\r\nPID|1\r\n", []string{"MSH", "PID"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Parse([]byte(tt.raw))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if len(msg.Segments) != len(tt.segments) {
+				t.Fatalf("got %d segments, want %d", len(msg.Segments), len(tt.segments))
+			}
+			for i, name := range tt.segments {
+				if msg.Segments[i].Name != name {
+					t.Errorf("segment %d = %s, want %s", i, msg.Segments[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestSegmentFields(t *testing.T) {
+	msg, err := Parse([]byte("MSH|^~\\&|LAB|CLINIC|||20240101120000||ORU^R01|C1|P|2.5\r" +
+		"PID|1||42^^^^PI~S-1^^^^SID||Rex\r" +
+		"OBX|1|ST|GLU^Glucose||5\\S\\6 \\F\\ high\\.br\\ok\\X0D\\|mmol/L\r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msh := msg.MSH()
+	if got := msh.Field(1); got != "|" {
+		t.Errorf("MSH-1 = %q", got)
+	}
+	if got := msh.Field(3); got != "LAB" {
+		t.Errorf("MSH-3 = %q", got)
+	}
+	if got := msg.Type(); got != "ORU^R01" {
+		t.Errorf("type = %q", got)
+	}
+	if got := msg.ControlID(); got != "C1" {
+		t.Errorf("control id = %q", got)
+	}
+
+	pid := msg.SegmentsByName("PID")[0]
+	reps := pid.Repetitions(3)
+	if len(reps) != 2 || reps[0].Component(1) != "42" || reps[1].Component(5) != "SID" {
+		t.Errorf("PID-3 repetitions are wrong: %v", reps)
+	}
+	if got := pid.Field(99); got != "" {
+		t.Errorf("missing field = %q", got)
+	}
+
+	obx := msg.SegmentsByName("OBX")[0]
+	if got, want := obx.Field(5), "5^6 | high\nok\\X0D\\"; got != want {
+		t.Errorf("unescaped value = %q, want %q", got, want)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	value := "a|b^c~d\\e&f"
+	escaped := DefaultDelimiters.EscapeText(value)
+	if escaped != "a\\F\\b\\S\\c\\R\\d\\E\\e\\T\\f" {
+		t.Errorf("escaped = %q", escaped)
+	}
+	if got := DefaultDelimiters.Unescape(escaped); got != value {
+		t.Errorf("round trip = %q, want %q", got, value)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	clinic, err := time.LoadLocation("Asia/Yekaterinburg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value string
+		want  time.Time
+		err   bool
+	}{
+		{"20240102", time.Date(2024, 1, 2, 0, 0, 0, 0, clinic), false},
+		{"202401021530", time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"20240102153045+0300", time.Date(2024, 1, 2, 12, 30, 45, 0, time.UTC), false},
+		{"20240102153045.25-0100", time.Date(2024, 1, 2, 16, 30, 45, 250000000, time.UTC), false},
+		{"", time.Time{}, true},
+		{"2024010", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, clinic)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseTime(%q) err = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package hl7
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const TypeORU = "ORU^R01"
+
+// PatientID is one repetition of PID-3. Type is identifier type code, e.g. PI
+type PatientID struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+func (p PatientID) String() string {
+	if p.Type == "" {
+		return p.ID
+	}
+	return p.ID + " (" + p.Type + ")"
+}
+
+// Observation is OBX segment with its OBR panel
+type Observation struct {
+	PanelCode      string
+	PanelName      string
+	Code           string
+	Name           string
+	ValueType      string
+	Value          string
+	Units          string
+	ReferenceRange string
+	AbnormalFlag   string
+	Status         string
+	ObservedAt     time.Time
+}
+
+// ORU is unsolicited observation result
+type ORU struct {
+	Sender       string
+	ControlID    string
+	PatientIDs   []PatientID
+	Observations []Observation
+}
+
+// ParseORU extracts patient identifiers & observations from ORU^R01 message, times without zone are in loc
+func ParseORU(m *Message, loc *time.Location) (ORU, error) {
+	if m.Type() != TypeORU {
+		return ORU{}, fmt.Errorf("hl7: unsupported message type %s, %s expected", m.Type(), TypeORU)
+	}
+
+	msh := m.MSH()
+	oru := ORU{
+		Sender:    strings.Trim(msh.Field(3)+"^"+msh.Field(4), "^"),
+		ControlID: m.ControlID(),
+	}
+	if oru.ControlID == "" {
+		return ORU{}, errors.New("hl7: MSH-10 message control id is empty")
+	}
+	sentAt, _ := ParseTime(msh.Field(7), loc)
+
+	var panel Segment
+	for _, segment := range m.Segments {
+		switch segment.Name {
+		case "PID":
+			for _, rep := range segment.Repetitions(3) {
+				if id := strings.TrimSpace(rep.Component(1)); id != "" {
+					oru.PatientIDs = append(oru.PatientIDs, PatientID{ID: id, Type: rep.Component(5)})
+				}
+			}
+		case "OBR":
+			panel = segment
+		case "OBX":
+			oru.Observations = append(oru.Observations, parseOBX(segment, panel, sentAt, loc))
+		}
+	}
+
+	if len(oru.PatientIDs) == 0 {
+		return ORU{}, errors.New("hl7: PID-3 patient identifier is empty")
+	}
+	if len(oru.Observations) == 0 {
+		return ORU{}, errors.New("hl7: message has no OBX segments")
+	}
+
+	return oru, nil
+}
+
+// parseOBX reads OBX. Observation time is OBX-14, then OBR-7, then MSH-7
+func parseOBX(obx, obr Segment, sentAt time.Time, loc *time.Location) Observation {
+	observation := Observation{
+		PanelCode:      obr.Component(4, 1),
+		PanelName:      obr.Component(4, 2),
+		Code:           obx.Component(3, 1),
+		Name:           obx.Component(3, 2),
+		ValueType:      obx.Field(2),
+		Units:          obx.Component(6, 1),
+		ReferenceRange: obx.Field(7),
+		AbnormalFlag:   obx.Field(8),
+		Status:         obx.Field(11),
+		ObservedAt:     sentAt,
+	}
+
+	// coded values keep text in second component
+	textComponent := 1
+	if observation.ValueType == "CE" || observation.ValueType == "CWE" {
+		textComponent = 2
+	}
+	var values []string
+	for _, rep := range obx.Repetitions(5) {
+		value := rep.Component(textComponent)
+		if value == "" {
+			value = rep.Component(1)
+		}
+		values = append(values, value)
+	}
+	observation.Value = strings.Join(values, "; ")
+
+	for _, value := range []string{obx.Field(14), obr.Field(7)} {
+		if observedAt, err := ParseTime(value, loc); err == nil {
+			observation.ObservedAt = observedAt
+			break
+		}
+	}
+
+	return observation
+}
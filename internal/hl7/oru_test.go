@@ -0,0 +1,108 @@
+package hl7
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseORU(t *testing.T) {
+	raw := "MSH|^~\\&|VETLAB|CLINIC|INFO||20240101120000+0000||ORU^R01|MSG1|P|2.5\r" +
+		"PID|1||42^^^^PI~643094100000001^^^^CHIP\r" +
+		"OBR|1|||CBC^Complete blood count|||20240101130000\r" +
+		"OBX|1|NM|WBC^White cells||12.5|10*9/L|6-17|N|||F\r" +
+		"OBX|2|CE|MORPH^Morphology||N^Normal~A^Anisocytosis||||||F|||20240101110000+0000\r"
+
+	msg, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// OBR-7 has no offset, it is time of clinic
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oru, err := ParseORU(msg, moscow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if oru.Sender != "VETLAB^CLINIC" || oru.ControlID != "MSG1" {
+		t.Errorf("sender = %q, control id = %q", oru.Sender, oru.ControlID)
+	}
+	if len(oru.PatientIDs) != 2 || oru.PatientIDs[0] != (PatientID{ID: "42", Type: "PI"}) {
+		t.Errorf("patient ids = %v", oru.PatientIDs)
+	}
+	if len(oru.Observations) != 2 {
+		t.Fatalf("got %d observations", len(oru.Observations))
+	}
+
+	wbc := oru.Observations[0]
+	if wbc.PanelCode != "CBC" || wbc.Code != "WBC" || wbc.Value != "12.5" || wbc.Units != "10*9/L" ||
+		wbc.ReferenceRange != "6-17" || wbc.Status != "F" {
+		t.Errorf("numeric observation = %+v", wbc)
+	}
+	if !wbc.ObservedAt.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("observed at OBR-7 expected, got %v", wbc.ObservedAt)
+	}
+
+	morph := oru.Observations[1]
+	if morph.Value != "Normal; Anisocytosis" {
+		t.Errorf("coded value = %q", morph.Value)
+	}
+	if !morph.ObservedAt.Equal(time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("observed at OBX-14 expected, got %v", morph.ObservedAt)
+	}
+}
+
+func TestParseORUErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		err  string
+	}{
+		{"wrong type", "MSH|^~\\&|A||||||ADT^A01|1|P|2.5\rPID|1||42\rOBX|1|NM|X||1\r", "unsupported message type"},
+		{"no control id", "MSH|^~\\&|A||||||ORU^R01||P|2.5\rPID|1||42\rOBX|1|NM|X||1\r", "control id"},
+		{"no patient", "MSH|^~\\&|A||||||ORU^R01|1|P|2.5\rPID|1||\rOBX|1|NM|X||1\r", "PID-3"},
+		{"no results", "MSH|^~\\&|A||||||ORU^R01|1|P|2.5\rPID|1||42\r", "no OBX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Parse([]byte(tt.raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParseORU(msg, time.UTC); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestACK(t *testing.T) {
+	msg, err := Parse([]byte("MSH|^~\\&|VETLAB|CLINIC|INFO|HQ|20240101120000||ORU^R01|MSG1|P|2.4\r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ack, err := Parse(ACK(msg, AckError, "pet|not found", now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msh := ack.MSH()
+	if msh.Field(3) != "INFO" || msh.Field(5) != "VETLAB" || msh.Field(12) != "2.4" {
+		t.Errorf("sender & receiver should be swapped: %v", msh)
+	}
+	if code, text := AckCode(ack); code != AckError || text != "pet|not found" {
+		t.Errorf("ack = %q %q", code, text)
+	}
+
+	rejected, err := Parse(ACK(nil, AckReject, "bad", now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := AckCode(rejected); code != AckReject {
+		t.Errorf("code = %q", code)
+	}
+}
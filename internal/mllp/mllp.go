@@ -0,0 +1,200 @@
+// Package mllp implements HL7 minimal lower layer protocol over TCP.
+// Every message is framed as <VT> message <FS><CR>
+package mllp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+)
+
+const (
+	startBlock    = 0x0b
+	endBlock      = 0x1c
+	carriageRet   = 0x0d
+	maxMessageLen = 1 << 20 // 1 MB
+	idleTimeout   = 5 * time.Minute
+	writeTimeout  = 10 * time.Second
+)
+
+var ErrMessageTooLarge = errors.New("mllp: message is too large")
+
+// Handler processes one message and returns acknowledgement to send back
+type Handler interface {
+	HandleMessage(msg []byte) []byte
+}
+
+// ReadMessage reads one framed message. Bytes before start block are skipped
+func ReadMessage(r *bufio.Reader) ([]byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == startBlock {
+			break
+		}
+	}
+
+	var msg bytes.Buffer
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if b == endBlock {
+			next, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if next != carriageRet {
+				return nil, fmt.Errorf("mllp: expected CR after end block, got 0x%02x", next)
+			}
+			return msg.Bytes(), nil
+		}
+		if msg.Len() >= maxMessageLen {
+			return nil, ErrMessageTooLarge
+		}
+		msg.WriteByte(b)
+	}
+}
+
+// WriteMessage writes framed message
+func WriteMessage(w io.Writer, msg []byte) error {
+	frame := make([]byte, 0, len(msg)+3)
+	frame = append(frame, startBlock)
+	frame = append(frame, msg...)
+	frame = append(frame, endBlock, carriageRet)
+	_, err := w.Write(frame)
+	return err
+}
+
+type Server struct {
+	log     *logging.Logger
+	handler Handler
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+func NewServer(log *logging.Logger, handler Handler) *Server {
+	return &Server{log: log, handler: handler, conns: map[net.Conn]struct{}{}}
+}
+
+// ListenAndServe accepts connections until Shutdown is called
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return listener.Close()
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// Shutdown stops accepting, closes connections & waits for messages in progress
+func (s *Server) Shutdown() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		// unblock reads, message being handled is still acknowledged
+		_ = conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	log := s.log.WithField("op", "mllp.serveConn").WithField("remote", conn.RemoteAddr().String())
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+		s.wg.Done()
+	}()
+
+	log.Debug("connection accepted")
+	reader := bufio.NewReader(conn)
+	for !s.isClosed() {
+		_ = conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		msg, err := ReadMessage(reader)
+		if err != nil {
+			if !s.isClosed() && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Warn("failed to read message: ", err.Error())
+			}
+			return
+		}
+
+		ack := s.handler.HandleMessage(msg)
+
+		_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := WriteMessage(conn, ack); err != nil {
+			log.Error("failed to write ack: ", err.Error())
+			return
+		}
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Send sends message over connection and waits for acknowledgement. It is used by `info mllp-send`
+func Send(conn net.Conn, msg []byte, timeout time.Duration) ([]byte, error) {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if err := WriteMessage(conn, msg); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+	ack, err := ReadMessage(bufio.NewReader(conn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ack: %w", err)
+	}
+	return ack, nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+const (
+	AllergyKindAllergy   = "allergy"
+	AllergyKindCondition = "condition"
+)
+
+const (
+	AllergySeverityLow      = "low"
+	AllergySeverityModerate = "moderate"
+	AllergySeverityHigh     = "high"
+)
+
+// Allergy is known allergy or chronic condition of pet. Only allergies are matched against med entries
+type Allergy struct {
+	ID         uint      `json:"id"`
+	PetID      uint      `json:"pet_id"`
+	Kind       string    `json:"kind"`
+	Substance  string    `json:"substance"`
+	Reaction   string    `json:"reaction,omitempty"`
+	Severity   string    `json:"severity"`
+	RecordedBy uint      `json:"recorded_by"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// AllergenSynonyms is synonym group of allergen. Substance & all synonyms match each other, terms are lower cased
+type AllergenSynonyms struct {
+	Substance string   `json:"substance"`
+	Synonyms  []string `json:"synonyms"`
+}
+
+// AllergyWarning is allergen found in med entry text
+type AllergyWarning struct {
+	AllergyID uint   `json:"allergy_id"`
+	Substance string `json:"substance"`
+	Severity  string `json:"severity"`
+	Reaction  string `json:"reaction,omitempty"`
+	Field     string `json:"field"`
+	Term      string `json:"term"`
+}
+
+// AllergyOverride is log of med entry saved despite high severity allergy
+type AllergyOverride struct {
+	MedicalEntryID uint
+	AllergyID      uint
+	VetID          uint
+	MatchedTerm    string
+	Reason         string
+}
+
+// CreatingMedEntry is med entry with reason to save it despite high severity allergy
+type CreatingMedEntry struct {
+	MedicalEntry
+	AllergyOverrideReason string `json:"allergy_override_reason,omitempty"`
+}
+
+type CreatedMedEntryDTO struct {
+	ID       uint             `json:"id"`
+	Warnings []AllergyWarning `json:"warnings"`
+}
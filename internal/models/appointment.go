@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	AppointmentStatusBooked    = "booked"
+	AppointmentStatusCheckedIn = "checked_in"
+	AppointmentStatusCompleted = "completed"
+	AppointmentStatusNoShow    = "no_show"
+	AppointmentStatusCancelled = "cancelled"
+)
+
+// Appointment is visit of pet to vet. ClinicNumber is branch, by default clinic of vet.
+// MedicalEntryID is entry created on completion
+type Appointment struct {
+	ID             uint      `json:"id"`
+	PetID          uint      `json:"pet_id"`
+	VetID          uint      `json:"vet_id"`
+	ClinicNumber   string    `json:"clinic_number,omitempty"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	Reason         string    `json:"reason"`
+	Status         string    `json:"status"`
+	CancelReason   string    `json:"cancel_reason,omitempty"`
+	MedicalEntryID uint      `json:"medical_entry_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ReschedulingAppointmentDTO moves appointment. Zero VetID keeps vet
+type ReschedulingAppointmentDTO struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	VetID    uint      `json:"vet_id,omitempty"`
+}
+
+type CancellingAppointmentDTO struct {
+	Reason string `json:"reason"`
+}
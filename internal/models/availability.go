@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+const (
+	ScheduleExceptionVacation  = "vacation"
+	ScheduleExceptionSickLeave = "sick_leave"
+	ScheduleExceptionOther     = "other"
+)
+
+// Clinic is branch. Hours are opening hours, clinic without hours is not limited by them
+type Clinic struct {
+	Number   string         `json:"number"`
+	Name     string         `json:"name"`
+	TimeZone string         `json:"time_zone"`
+	Hours    []WorkingHours `json:"hours"`
+}
+
+// WorkingHours is weekly interval in local time of clinic. Weekday is 0 for Sunday, times are HH:MM
+type WorkingHours struct {
+	Weekday int    `json:"weekday"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// ScheduleException is days vet does not work. Dates are YYYY-MM-DD, both inclusive
+type ScheduleException struct {
+	ID        uint   `json:"id"`
+	VetID     uint   `json:"vet_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Kind      string `json:"kind"`
+	Note      string `json:"note,omitempty"`
+}
+
+// TimeBlock is time of vet reserved by staff, optionally for pet
+type TimeBlock struct {
+	ID        uint      `json:"id"`
+	VetID     uint      `json:"vet_id"`
+	PetID     uint      `json:"pet_id,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Slot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// AvailabilityDTO is free slots of vet. Slot times are in time zone of clinic
+type AvailabilityDTO struct {
+	VetID        uint   `json:"vet_id"`
+	ClinicNumber string `json:"clinic_number,omitempty"`
+	TimeZone     string `json:"time_zone"`
+	Slots        []Slot `json:"slots"`
+}
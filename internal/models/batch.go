@@ -0,0 +1,36 @@
+package models
+
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+// PetWithCard is pet with ids of its medical card
+type PetWithCard struct {
+	Pet     Pet
+	OwnerID uint
+	VetID   uint
+}
+
+// BatchResult is result of one batch item. Err is nil for saved item
+type BatchResult struct {
+	ID       uint
+	Warnings []AllergyWarning
+	Err      error
+}
+
+type BatchResultDTO struct {
+	Index    int              `json:"index"`
+	Status   string           `json:"status"` // created | updated | failed | rolled_back
+	ID       uint             `json:"id,omitempty"`
+	Warnings []AllergyWarning `json:"warnings,omitempty"`
+	Error    *ProblemDTO      `json:"error,omitempty"`
+}
+
+type BatchResponseDTO struct {
+	Mode    string           `json:"mode"`
+	Created int              `json:"created,omitempty"`
+	Updated int              `json:"updated,omitempty"`
+	Failed  int              `json:"failed"`
+	Results []BatchResultDTO `json:"results"`
+}
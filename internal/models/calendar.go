@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	CalendarFeedKindVet   = "vet"
+	CalendarFeedKindOwner = "owner"
+)
+
+// CalendarFeed is secret link to iCalendar feed of follow-ups of vet or owner. Token is shown only on creation,
+// revoked feed stops serving events
+type CalendarFeed struct {
+	ID        uint       `json:"id"`
+	Kind      string     `json:"kind"`
+	SubjectID uint       `json:"subject_id"`
+	Token     string     `json:"token,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// FollowUp is recheck recommended at medical entry
+type FollowUp struct {
+	EntryID        uint
+	FollowUpAt     string
+	Disease        string
+	Recommendation string
+	PetID          uint
+	PetName        string
+	OwnerName      string
+	VetName        string
+}
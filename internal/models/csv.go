@@ -0,0 +1,55 @@
+package models
+
+import "strconv"
+
+// PetCSVHeader is header of pets exported as CSV, columns match PetCSVRecord
+var PetCSVHeader = []string{
+	"pet_id", "name", "animal_type", "breed", "gender", "birth_date", "birth_date_estimated", "age", "weight",
+	"microchip", "condition", "behavior", "research_status", "owner_id", "vet_id",
+}
+
+func PetCSVRecord(item OutputPetDTO) []string {
+	pet := item.Pet
+	return []string{
+		strconv.FormatUint(uint64(pet.ID), 10),
+		pet.Name,
+		pet.AnimalType,
+		pet.Breed,
+		pet.Gender,
+		pet.BirthDate,
+		strconv.FormatBool(pet.BirthDateEstimated),
+		strconv.FormatUint(uint64(pet.Age), 10),
+		strconv.FormatFloat(pet.Weight, 'f', -1, 64),
+		pet.Microchip,
+		pet.Condition,
+		pet.Behavior,
+		pet.ResearchStatus,
+		strconv.FormatUint(uint64(item.OwnerID), 10),
+		strconv.FormatUint(uint64(item.VetID), 10),
+	}
+}
+
+// EntryCSVHeader is header of medical entries exported as CSV, columns match EntryCSVRecord
+var EntryCSVHeader = []string{
+	"entry_id", "medical_record_id", "vet_id", "entry_date", "description", "disease", "vaccinations",
+	"recommendation", "follow_up_at", "device_number",
+}
+
+func EntryCSVRecord(entry MedicalEntry) []string {
+	deviceNumber := ""
+	if entry.DeviceNumber != 0 {
+		deviceNumber = strconv.FormatUint(uint64(entry.DeviceNumber), 10)
+	}
+	return []string{
+		strconv.FormatUint(uint64(entry.ID), 10),
+		strconv.FormatUint(uint64(entry.MedicalRecordID), 10),
+		strconv.FormatUint(uint64(entry.VetID), 10),
+		entry.EntryDate,
+		entry.Description,
+		entry.Disease,
+		entry.Vaccinations,
+		entry.Recommendation,
+		entry.FollowUpAt,
+		deviceNumber,
+	}
+}
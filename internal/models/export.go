@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+const (
+	// ExportTypeResearch is anonymized dataset of pets & their medical entries
+	ExportTypeResearch = "research"
+	// ExportTypePets is pets with owner & vet selected by criteria.pets
+	ExportTypePets = "pets"
+	// ExportTypeEntries is medical entries selected by criteria.entries
+	ExportTypeEntries = "entries"
+)
+
+const (
+	ExportFormatCSV = "csv"
+	// ExportFormatParquet has same columns as csv, all of them are optional UTF-8 strings
+	ExportFormatParquet = "parquet"
+)
+
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+	// ExportStatusExpired job is completed but its file is deleted
+	ExportStatusExpired = "expired"
+)
+
+// ExportCriteria selects exported pets & entries of their records. Pagination is ignored, pet & entry
+// of entries filter are used by entries exports only
+type ExportCriteria struct {
+	Pets    PetReqFilter   `json:"pets"`
+	Entries EntryReqFilter `json:"entries"`
+}
+
+type CreatingExportDTO struct {
+	Type     string         `json:"type"`
+	Format   string         `json:"format"`
+	Criteria ExportCriteria `json:"criteria"`
+}
+
+type ExportJob struct {
+	ID       uint           `json:"id"`
+	Type     string         `json:"type"`
+	Status   string         `json:"status"`
+	Format   string         `json:"format"`
+	Criteria ExportCriteria `json:"criteria"`
+	// FilePath is local file of running job, finished file is kept in storage as FileName
+	FilePath string `json:"-"`
+	FileName string `json:"file_name,omitempty"`
+	FileSize int64  `json:"file_size,omitempty"`
+	// ExportedPets & ExportedEntries are progress of running job
+	ExportedPets    uint   `json:"exported_pets"`
+	ExportedEntries uint   `json:"exported_entries"`
+	Error           string `json:"error,omitempty"`
+	// ExpiresAt is when file of completed job is deleted
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ExportManifest describes files of export & anonymization applied to them
+type ExportManifest struct {
+	JobID          uint           `json:"job_id"`
+	Type           string         `json:"type"`
+	GeneratedAt    time.Time      `json:"generated_at"`
+	Criteria       ExportCriteria `json:"criteria"`
+	Anonymization  []string       `json:"anonymization"`
+	Files          []ExportFile   `json:"files"`
+	DroppedColumns []string       `json:"dropped_columns"`
+}
+
+type ExportFile struct {
+	Name   string        `json:"name"`
+	Rows   uint          `json:"rows"`
+	Fields []ExportField `json:"fields"`
+}
+
+type ExportField struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Anonymization is transformation applied to source value, empty if copied as is
+	Anonymization string `json:"anonymization,omitempty"`
+}
@@ -0,0 +1,134 @@
+package models
+
+// FHIR R4 resources. Only elements filled from clinic data are described
+
+const (
+	FHIRBundleTypeSearchset = "searchset"
+	FHIRSearchModeMatch     = "match"
+	FHIRSearchModeInclude   = "include"
+)
+
+type FHIRBundle struct {
+	ResourceType string            `json:"resourceType"`
+	Type         string            `json:"type"`
+	Timestamp    string            `json:"timestamp,omitempty"`
+	Total        int               `json:"total"`
+	Entry        []FHIRBundleEntry `json:"entry"`
+}
+
+type FHIRBundleEntry struct {
+	FullURL  string            `json:"fullUrl"`
+	Resource interface{}       `json:"resource"`
+	Search   *FHIRBundleSearch `json:"search,omitempty"`
+}
+
+type FHIRBundleSearch struct {
+	Mode string `json:"mode"`
+}
+
+type FHIRReference struct {
+	Reference string `json:"reference"`
+	Display   string `json:"display,omitempty"`
+}
+
+type FHIRCoding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+type FHIRCodeableConcept struct {
+	Coding []FHIRCoding `json:"coding,omitempty"`
+	Text   string       `json:"text,omitempty"`
+}
+
+type FHIRExtension struct {
+	URL                  string               `json:"url"`
+	Extension            []FHIRExtension      `json:"extension,omitempty"`
+	ValueCodeableConcept *FHIRCodeableConcept `json:"valueCodeableConcept,omitempty"`
+}
+
+type FHIRHumanName struct {
+	Text string `json:"text"`
+}
+
+type FHIRContactPoint struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+type FHIRPeriod struct {
+	Start string `json:"start,omitempty"`
+}
+
+type FHIRPatient struct {
+	ResourceType        string          `json:"resourceType"`
+	ID                  string          `json:"id"`
+	Extension           []FHIRExtension `json:"extension,omitempty"`
+	Active              bool            `json:"active"`
+	Name                []FHIRHumanName `json:"name,omitempty"`
+	Gender              string          `json:"gender,omitempty"`
+	BirthDate           string          `json:"birthDate,omitempty"`
+	GeneralPractitioner []FHIRReference `json:"generalPractitioner,omitempty"`
+}
+
+type FHIRRelatedPerson struct {
+	ResourceType string                `json:"resourceType"`
+	ID           string                `json:"id"`
+	Patient      FHIRReference         `json:"patient"`
+	Relationship []FHIRCodeableConcept `json:"relationship,omitempty"`
+	Name         []FHIRHumanName       `json:"name,omitempty"`
+	Telecom      []FHIRContactPoint    `json:"telecom,omitempty"`
+}
+
+type FHIRPractitioner struct {
+	ResourceType string             `json:"resourceType"`
+	ID           string             `json:"id"`
+	Name         []FHIRHumanName    `json:"name,omitempty"`
+	Telecom      []FHIRContactPoint `json:"telecom,omitempty"`
+}
+
+type FHIREncounterParticipant struct {
+	Individual FHIRReference `json:"individual"`
+}
+
+type FHIREncounter struct {
+	ResourceType string                     `json:"resourceType"`
+	ID           string                     `json:"id"`
+	Status       string                     `json:"status"`
+	Class        FHIRCoding                 `json:"class"`
+	Subject      FHIRReference              `json:"subject"`
+	Participant  []FHIREncounterParticipant `json:"participant,omitempty"`
+	Period       *FHIRPeriod                `json:"period,omitempty"`
+	ReasonCode   []FHIRCodeableConcept      `json:"reasonCode,omitempty"`
+}
+
+type FHIRCondition struct {
+	ResourceType string              `json:"resourceType"`
+	ID           string              `json:"id"`
+	Code         FHIRCodeableConcept `json:"code"`
+	Subject      FHIRReference       `json:"subject"`
+	Encounter    *FHIRReference      `json:"encounter,omitempty"`
+	RecordedDate string              `json:"recordedDate,omitempty"`
+}
+
+type FHIRImmunization struct {
+	ResourceType       string              `json:"resourceType"`
+	ID                 string              `json:"id"`
+	Status             string              `json:"status"`
+	VaccineCode        FHIRCodeableConcept `json:"vaccineCode"`
+	Patient            FHIRReference       `json:"patient"`
+	Encounter          *FHIRReference      `json:"encounter,omitempty"`
+	OccurrenceDateTime string              `json:"occurrenceDateTime"`
+}
+
+type FHIRCarePlan struct {
+	ResourceType string         `json:"resourceType"`
+	ID           string         `json:"id"`
+	Status       string         `json:"status"`
+	Intent       string         `json:"intent"`
+	Description  string         `json:"description"`
+	Subject      FHIRReference  `json:"subject"`
+	Encounter    *FHIRReference `json:"encounter,omitempty"`
+	Created      string         `json:"created,omitempty"`
+}
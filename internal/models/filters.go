@@ -1,17 +1,96 @@
 package models
 
+import "time"
+
 type PetReqFilter struct {
 	// models.Pet add later
 	PetID   *uint `json:"pet_id"`
 	OwnerID *uint `json:"owner_id"`
 	VetID   *uint `json:"vet_id"`
-	Limit   *uint `json:"limit"`
-	Offset  *uint `json:"offset"`
+	// AgeFrom & AgeTo are full years computed from birth date, both inclusive
+	AgeFrom *uint `json:"age_from"`
+	AgeTo   *uint `json:"age_to"`
+	// ResearchStatus is research stage
+	ResearchStatus *string `json:"research_status"`
+	// ResearchStatuses is any of research stages, set by services only
+	ResearchStatuses []string `json:"-"`
+	Limit            *uint    `json:"limit"`
+	Offset           *uint    `json:"offset"`
 }
 
 type EntryReqFilter struct {
 	PetID   *uint `json:"pet_id"`
 	EntryID *uint `json:"entry_id"`
-	Limit   *uint `json:"limit"`
-	Offset  *uint `json:"offset"`
+	// VetID is vet who made entry
+	VetID *uint `json:"vet_id"`
+	// DateFrom & DateTo are YYYY-MM-DD, both inclusive
+	DateFrom *string `json:"date_from"`
+	DateTo   *string `json:"date_to"`
+	Limit    *uint   `json:"limit"`
+	Offset   *uint   `json:"offset"`
+}
+
+type LabResultReqFilter struct {
+	PetID  *uint `json:"pet_id"`
+	Limit  *uint `json:"limit"`
+	Offset *uint `json:"offset"`
+}
+
+type VaccinationDueReqFilter struct {
+	// Status is overdue or upcoming
+	Status    string  `json:"status"`
+	VetID     *uint   `json:"vet_id"`
+	Species   *string `json:"species"`
+	VaccineID *uint   `json:"vaccine_id"`
+	// WithinDays limits upcoming vaccinations
+	WithinDays *uint `json:"within_days"`
+	Limit      *uint `json:"limit"`
+	Offset     *uint `json:"offset"`
+}
+
+// AppointmentReqFilter selects appointments overlapping From - To
+type AppointmentReqFilter struct {
+	VetID  *uint      `json:"vet_id"`
+	PetID  *uint      `json:"pet_id"`
+	Status *string    `json:"status"`
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+	Limit  *uint      `json:"limit"`
+	Offset *uint      `json:"offset"`
+}
+
+// AvailabilityReqFilter is period of local dates of clinic, both inclusive, & slot length in minutes
+type AvailabilityReqFilter struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Duration uint   `json:"duration"`
+}
+
+// FollowUpReqFilter selects follow-ups of pets of vet or owner dated From (YYYY-MM-DD) or later
+type FollowUpReqFilter struct {
+	VetID   *uint
+	OwnerID *uint
+	From    string
+}
+
+type ReminderReqFilter struct {
+	OwnerID *uint   `json:"owner_id"`
+	PetID   *uint   `json:"pet_id"`
+	Status  *string `json:"status"`
+	Limit   *uint   `json:"limit"`
+	Offset  *uint   `json:"offset"`
+}
+
+type NotificationReqFilter struct {
+	OwnerID    *uint   `json:"owner_id"`
+	ReminderID *uint   `json:"reminder_id"`
+	Status     *string `json:"status"`
+	Limit      *uint   `json:"limit"`
+	Offset     *uint   `json:"offset"`
+}
+
+type JobRunReqFilter struct {
+	JobName *string `json:"job_name"`
+	Limit   *uint   `json:"limit"`
+	Offset  *uint   `json:"offset"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is saved response of request with Idempotency-Key. ResponseStatus is 0 while request is in progress.
+// Key is unique within Scope, which identifies client & route
+type IdempotencyRecord struct {
+	Scope               string
+	Key                 string
+	RequestHash         string
+	ResponseStatus      int
+	ResponseContentType string
+	ResponseBody        []byte
+	// LockedUntil is end of lease of in progress request
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportMapping maps source columns to fields like "pet.name", "owner.email", "entry.disease".
+// Defaults are used when column is absent or empty
+type ImportMapping struct {
+	Columns  map[string]string `json:"columns,omitempty"`
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+type ImportJob struct {
+	ID           uint          `json:"id"`
+	Status       string        `json:"status"`
+	Format       string        `json:"format"`
+	DryRun       bool          `json:"dry_run"`
+	FilePath     string        `json:"-"`
+	Mapping      ImportMapping `json:"mapping"`
+	DefaultVetID uint          `json:"default_vet_id,omitempty"`
+	// ProcessedRows is checkpoint. Resumed job skips processed rows
+	ProcessedRows uint         `json:"processed_rows"`
+	Report        ImportReport `json:"report"`
+	Error         string       `json:"error,omitempty"`
+	// Attempt is number of times job was claimed by worker, only its last worker can save progress
+	Attempt   uint      `json:"attempt"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ImportReport struct {
+	ValidRows      uint             `json:"valid_rows"`
+	InvalidRows    uint             `json:"invalid_rows"`
+	CreatedOwners  uint             `json:"created_owners"`
+	ReusedOwners   uint             `json:"reused_owners"`
+	CreatedPets    uint             `json:"created_pets"`
+	CreatedEntries uint             `json:"created_entries"`
+	Errors         []ImportRowError `json:"errors,omitempty"`
+}
+
+type ImportRowError struct {
+	Row     uint            `json:"row"`
+	Message string          `json:"message"`
+	Fields  []FieldErrorDTO `json:"fields,omitempty"`
+}
+
+// ImportFormatByFilename guesses format by extension, csv is default
+func ImportFormatByFilename(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ndjson", ".jsonl":
+		return ImportFormatNDJSON
+	default:
+		return ImportFormatCSV
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+// ScheduledJob is registered background job with its shared state
+type ScheduledJob struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Schedule    string     `json:"schedule"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	LastRun     *JobRun    `json:"last_run,omitempty"`
+}
+
+// JobRun is one execution of job on replica Host
+type JobRun struct {
+	ID         uint       `json:"id"`
+	JobName    string     `json:"job_name"`
+	Trigger    string     `json:"trigger"`
+	Status     string     `json:"status"`
+	Host       string     `json:"host"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
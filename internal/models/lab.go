@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+const (
+	LabMessageMatched   = "matched"
+	LabMessageReview    = "review"
+	LabMessageDismissed = "dismissed"
+)
+
+// LabMessage is HL7 ORU message received from analyzer
+type LabMessage struct {
+	ID           uint       `json:"id"`
+	Sender       string     `json:"sender"`
+	ControlID    string     `json:"control_id"`
+	Status       string     `json:"status"`
+	PetID        uint       `json:"pet_id,omitempty"`
+	PatientIDs   string     `json:"patient_ids"`
+	ReviewReason string     `json:"review_reason,omitempty"`
+	Raw          string     `json:"raw"`
+	ReceivedAt   time.Time  `json:"received_at"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+}
+
+// LabResult is one observation of analyzer linked to pet medical record
+type LabResult struct {
+	ID              uint      `json:"id"`
+	MessageID       uint      `json:"message_id"`
+	MedicalRecordID uint      `json:"medical_record_id"`
+	PanelCode       string    `json:"panel_code,omitempty"`
+	PanelName       string    `json:"panel_name,omitempty"`
+	Code            string    `json:"code"`
+	Name            string    `json:"name,omitempty"`
+	ValueType       string    `json:"value_type,omitempty"`
+	Value           string    `json:"value"`
+	Units           string    `json:"units,omitempty"`
+	ReferenceRange  string    `json:"reference_range,omitempty"`
+	AbnormalFlag    string    `json:"abnormal_flag,omitempty"`
+	ResultStatus    string    `json:"result_status,omitempty"`
+	ObservedAt      time.Time `json:"observed_at"`
+}
+
+// ResolveLabReviewDTO assigns pet to message from review queue
+type ResolveLabReviewDTO struct {
+	PetID uint `json:"pet_id"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// Measurement is weigh-in of pet. MeasuredAt is YYYY-MM-DD, BodyConditionScore is 1-9, 0 if not assessed
+type Measurement struct {
+	ID                 uint    `json:"id"`
+	PetID              uint    `json:"pet_id"`
+	MeasuredAt         string  `json:"measured_at"`
+	Weight             float64 `json:"weight"`
+	BodyConditionScore uint    `json:"body_condition_score,omitempty"`
+}
+
+// WeightTrendPoint is measurement with change from previous one, first point has no change
+type WeightTrendPoint struct {
+	Measurement
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+}
+
+// WeightLoss is drop of weight from heaviest measurement of window to measurement at To
+type WeightLoss struct {
+	From          string  `json:"from"`
+	To            string  `json:"to"`
+	FromWeight    float64 `json:"from_weight"`
+	ToWeight      float64 `json:"to_weight"`
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// WeightTrend is weight series of pet. RapidLoss is set if weight dropped more than
+// RapidLossPercent within RapidLossDays
+type WeightTrend struct {
+	PetID            uint               `json:"pet_id"`
+	Points           []WeightTrendPoint `json:"points"`
+	ChangePercent    float64            `json:"change_percent"`
+	RapidLossPercent float64            `json:"rapid_loss_percent"`
+	RapidLossDays    int                `json:"rapid_loss_days"`
+	RapidLoss        bool               `json:"rapid_loss"`
+	Losses           []WeightLoss       `json:"losses"`
+}
@@ -7,6 +7,7 @@ package models
 // disease TEXT,
 // vaccinations TEXT,
 // recommendation TEXT,
+// follow_up_at DATE,
 // medical_record_id INTEGER REFERENCES medical_record(id),
 // device_number INTEGER REFERENCES device(id)
 // );
@@ -18,6 +19,7 @@ type MedicalEntry struct {
 	Disease         string `json:"disease"`
 	Vaccinations    string `json:"vaccinations"`
 	Recommendation  string `json:"recommendation"`
+	FollowUpAt      string `json:"follow_up_at,omitempty"` // recommended recheck date YYYY-MM-DD
 	DeviceNumber    uint   `json:"device_number"`
 	MedicalRecordID uint   `json:"medical_record_id"`
 	VetID           uint   `json:"vet_id"`
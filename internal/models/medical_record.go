@@ -1,7 +1,8 @@
 package models
 
 type MedicalRecord struct {
-	vetID    uint `json:"vet_id"`
-	ownderID uint `json:"owner_id"`
-	petID    uint `json:"pet_id"`
+	ID      uint `json:"id"`
+	VetID   uint `json:"vet_id"`
+	OwnerID uint `json:"owner_id"`
+	PetID   uint `json:"pet_id"`
 }
@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+
+	LanguageRU = "ru"
+	LanguageEN = "en"
+)
+
+const (
+	// ReminderKindFollowUp is sent before follow_up_at of med entry
+	ReminderKindFollowUp = "follow_up"
+	// ReminderKindNewEntry is sent when med entry is added to pet record
+	ReminderKindNewEntry = "new_entry"
+	// ReminderKindCustom is created by staff with own message
+	ReminderKindCustom = "custom"
+)
+
+const (
+	ReminderStatusPending = "pending"
+	// ReminderStatusQueued reminder has deliveries that are not sent yet
+	ReminderStatusQueued = "queued"
+	// ReminderStatusSent reminder has at least one sent delivery
+	ReminderStatusSent = "sent"
+	// ReminderStatusFailed all deliveries of reminder failed
+	ReminderStatusFailed    = "failed"
+	ReminderStatusSkipped   = "skipped"
+	ReminderStatusCancelled = "cancelled"
+)
+
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+// NotificationPreferences are channels of owner. OptedOut stops all notifications
+type NotificationPreferences struct {
+	OwnerID   uint      `json:"owner_id"`
+	Language  string    `json:"language"`
+	Email     bool      `json:"email"`
+	SMS       bool      `json:"sms"`
+	OptedOut  bool      `json:"opted_out"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Reminder is message to owner about pet sent at DueAt over enabled channels
+type Reminder struct {
+	ID             uint       `json:"id"`
+	OwnerID        uint       `json:"owner_id"`
+	PetID          uint       `json:"pet_id"`
+	MedicalEntryID uint       `json:"medical_entry_id,omitempty"`
+	Kind           string     `json:"kind"`
+	Message        string     `json:"message,omitempty"`
+	DueAt          time.Time  `json:"due_at"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ProcessedAt    *time.Time `json:"processed_at,omitempty"`
+}
+
+// CreatingReminderDTO is custom reminder, owner is taken from med record of pet
+type CreatingReminderDTO struct {
+	PetID   uint      `json:"pet_id"`
+	DueAt   time.Time `json:"due_at"`
+	Message string    `json:"message"`
+}
+
+// Notification is delivery of reminder over one channel
+type Notification struct {
+	ID            uint       `json:"id"`
+	ReminderID    uint       `json:"reminder_id"`
+	OwnerID       uint       `json:"owner_id"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"`
+	Language      string     `json:"language"`
+	Subject       string     `json:"subject,omitempty"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      uint       `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
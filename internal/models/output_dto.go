@@ -1,6 +1,19 @@
 package models
 
-type ErrorDTO struct {
+// ProblemDTO is RFC 7807 application/problem+json body. code & fields are extension members
+type ProblemDTO struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Code     string          `json:"code"`
+	Fields   []FieldErrorDTO `json:"fields,omitempty"`
+}
+
+type FieldErrorDTO struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
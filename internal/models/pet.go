@@ -1,13 +1,20 @@
 package models
 
+// Pet of clinic. AnimalType & Breed are catalogue codes. Age & AgeMonths are computed from BirthDate
+// on read, Age on input is used to estimate birth date if it is unknown
 type Pet struct {
-	ID             uint    `json:"id"`
-	AnimalType     string  `json:"animal_type"`
-	Name           string  `json:"name"`
-	Gender         string  `json:"gender,omitempty"`
-	Age            uint    `json:"age,omitempty"`
-	Weight         float64 `json:"weight,omitempty"`
-	Condition      string  `json:"condition,omitempty"`
-	Behavior       string  `json:"behavior,omitempty"`
-	ResearchStatus string  `json:"research_status,omitempty"`
+	ID                 uint    `json:"id"`
+	AnimalType         string  `json:"animal_type"`
+	Breed              string  `json:"breed,omitempty"`
+	Name               string  `json:"name"`
+	Gender             string  `json:"gender,omitempty"`
+	Age                uint    `json:"age,omitempty"`
+	AgeMonths          uint    `json:"age_months,omitempty"`
+	BirthDate          string  `json:"birth_date,omitempty"`
+	BirthDateEstimated bool    `json:"birth_date_estimated,omitempty"`
+	Weight             float64 `json:"weight,omitempty"`
+	Condition          string  `json:"condition,omitempty"`
+	Behavior           string  `json:"behavior,omitempty"`
+	ResearchStatus     string  `json:"research_status,omitempty"`
+	Microchip          string  `json:"microchip,omitempty"`
 }
@@ -0,0 +1,10 @@
+package models
+
+// PetRecord is full medical history of pet with card participants
+type PetRecord struct {
+	Pet     Pet            `json:"pet"`
+	Record  MedicalRecord  `json:"record"`
+	Owner   Owner          `json:"owner"`
+	Vet     Vet            `json:"vet"`
+	Entries []MedicalEntry `json:"entries"`
+}
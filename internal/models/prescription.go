@@ -0,0 +1,42 @@
+package models
+
+const (
+	DoseCheckOK         = "ok"
+	DoseCheckOutOfRange = "out_of_range"
+	DoseCheckUnchecked  = "unchecked"
+)
+
+// Prescription is drug prescribed at medical entry. Dates are YYYY-MM-DD
+type Prescription struct {
+	ID             uint    `json:"id"`
+	MedicalEntryID uint    `json:"medical_entry_id"`
+	Drug           string  `json:"drug"`
+	Dose           float64 `json:"dose"`
+	Unit           string  `json:"unit"`
+	Route          string  `json:"route"`
+	Frequency      string  `json:"frequency"`
+	DurationDays   uint    `json:"duration_days,omitempty"`
+	StartDate      string  `json:"start_date"`
+	EndDate        string  `json:"end_date,omitempty"`
+	// DoseOverride confirms dose outside of reference range or dose that can not be checked
+	DoseOverride bool       `json:"dose_override,omitempty"`
+	DoseCheck    *DoseCheck `json:"dose_check,omitempty"`
+}
+
+// DoseCheck is result of checking dose against pet weight & reference range
+type DoseCheck struct {
+	Status     string  `json:"status"`
+	MgPerKg    float64 `json:"mg_per_kg,omitempty"`
+	MinMgPerKg float64 `json:"min_mg_per_kg,omitempty"`
+	MaxMgPerKg float64 `json:"max_mg_per_kg,omitempty"`
+	Message    string  `json:"message,omitempty"`
+}
+
+// DoseRange is reference range of drug. Empty species is range for any species
+type DoseRange struct {
+	ID         uint    `json:"id"`
+	Drug       string  `json:"drug"`
+	Species    string  `json:"species"`
+	MinMgPerKg float64 `json:"min_mg_per_kg"`
+	MaxMgPerKg float64 `json:"max_mg_per_kg"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Research program stages of pet
+const (
+	ResearchStatusCandidate = "candidate"
+	ResearchStatusConsented = "consented"
+	ResearchStatusEnrolled  = "enrolled"
+	ResearchStatusActive    = "active"
+	ResearchStatusWithdrawn = "withdrawn"
+	ResearchStatusCompleted = "completed"
+)
+
+// ConsentedResearchStatuses are stages of pets whose owners consented to research, only they are exported
+// for research
+var ConsentedResearchStatuses = []string{
+	ResearchStatusConsented, ResearchStatusEnrolled, ResearchStatusActive, ResearchStatusCompleted,
+}
+
+// ResearchTransition is change of pet research status. ActorID is vet, 0 for migrated statuses
+type ResearchTransition struct {
+	ID         uint      `json:"id"`
+	PetID      uint      `json:"pet_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason,omitempty"`
+	ActorID    uint      `json:"actor_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ResearchTransitionDTO struct {
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+	ActorID uint   `json:"actor_id"`
+}
+
+// ResearchStatusDTO is current status of pet with transitions, oldest first
+type ResearchStatusDTO struct {
+	PetID   uint                 `json:"pet_id"`
+	Status  string               `json:"status"`
+	History []ResearchTransition `json:"history"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// Species is catalogue entry. Code is stored in pet animal_type, Names are localized by language code.
+// Aliases are other lower cased names of species, e.g. "кот"
+type Species struct {
+	Code    string            `json:"code"`
+	Names   map[string]string `json:"names"`
+	Aliases []string          `json:"aliases"`
+}
+
+// Breed is catalogue entry of species. Code is stored in pet breed
+type Breed struct {
+	SpeciesCode string            `json:"species_code"`
+	Code        string            `json:"code"`
+	Names       map[string]string `json:"names"`
+}
+
+// UnmappedSpecies is animal_type of pets that is not in catalogue
+type UnmappedSpecies struct {
+	AnimalType string `json:"animal_type"`
+	Pets       uint   `json:"pets"`
+}
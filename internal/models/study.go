@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Study is research study pets are enrolled in. Dates are YYYY-MM-DD, open ended study has no end date
+type Study struct {
+	ID                uint   `json:"id"`
+	Title             string `json:"title"`
+	ProtocolID        string `json:"protocol_id"`
+	PrincipalVetID    uint   `json:"principal_vet_id"`
+	StartDate         string `json:"start_date"`
+	EndDate           string `json:"end_date,omitempty"`
+	InclusionCriteria string `json:"inclusion_criteria"`
+}
+
+// Enrollment links pet to study arm. WithdrawnAt is set once pet left study
+type Enrollment struct {
+	ID               uint       `json:"id"`
+	StudyID          uint       `json:"study_id"`
+	PetID            uint       `json:"pet_id"`
+	Arm              string     `json:"arm"`
+	ConsentDate      string     `json:"consent_date"`
+	EnrolledAt       time.Time  `json:"enrolled_at"`
+	WithdrawnAt      *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawalReason string     `json:"withdrawal_reason,omitempty"`
+}
+
+// EnrollingPetDTO is enrollment request. ActorID is vet recorded in research status history
+type EnrollingPetDTO struct {
+	PetID       uint   `json:"pet_id"`
+	Arm         string `json:"arm"`
+	ConsentDate string `json:"consent_date"`
+	ActorID     uint   `json:"actor_id"`
+}
+
+type WithdrawingPetDTO struct {
+	Reason  string `json:"reason"`
+	ActorID uint   `json:"actor_id"`
+}
+
+// CohortMember is enrolled pet with medical entries made within study window
+type CohortMember struct {
+	Enrollment Enrollment     `json:"enrollment"`
+	Pet        Pet            `json:"pet"`
+	Entries    []MedicalEntry `json:"entries"`
+}
+
+type CohortDTO struct {
+	Study   Study          `json:"study"`
+	Members []CohortMember `json:"members"`
+}
@@ -7,3 +7,12 @@ type Owner struct {
 	Phone        string `json:"phone,omitempty"`
 	PasswordHash string `json:"password_hash,omitempty"` // password hash
 }
+
+type Vet struct {
+	ID           uint   `json:"id"`
+	FullName     string `json:"fullname"`
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	Position     string `json:"position,omitempty"`
+	ClinicNumber string `json:"clinic_number,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+const DateLayout = "2006-01-02"
+
+const (
+	VaccinationDueOverdue  = "overdue"
+	VaccinationDueUpcoming = "upcoming"
+)
+
+// Vaccine is catalogue entry. RevaccinationMonths = 0 means vaccine is given once
+type Vaccine struct {
+	ID                  uint   `json:"id"`
+	Name                string `json:"name"`
+	Species             string `json:"species"`
+	RevaccinationMonths uint   `json:"revaccination_months"`
+}
+
+// Vaccination is administered dose. Dates are YYYY-MM-DD
+type Vaccination struct {
+	ID             uint   `json:"id"`
+	PetID          uint   `json:"pet_id"`
+	VaccineID      uint   `json:"vaccine_id"`
+	VaccineName    string `json:"vaccine_name,omitempty"`
+	MedicalEntryID uint   `json:"medical_entry_id,omitempty"`
+	VetID          uint   `json:"vet_id"`
+	LotNumber      string `json:"lot_number"`
+	AdministeredAt string `json:"administered_at"`
+	// NextDueAt is computed from catalogue interval, empty for single dose vaccines
+	NextDueAt string `json:"next_due_at,omitempty"`
+}
+
+// DueVaccination is revaccination computed from latest dose of vaccine
+type DueVaccination struct {
+	PetID              uint   `json:"pet_id"`
+	PetName            string `json:"pet_name"`
+	Species            string `json:"species"`
+	OwnerID            uint   `json:"owner_id"`
+	VetID              uint   `json:"vet_id"`
+	VaccineID          uint   `json:"vaccine_id"`
+	VaccineName        string `json:"vaccine_name"`
+	LastAdministeredAt string `json:"last_administered_at"`
+	NextDueAt          string `json:"next_due_at"`
+	Overdue            bool   `json:"overdue"`
+}
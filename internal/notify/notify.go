@@ -0,0 +1,18 @@
+// Package notify delivers messages to owners over email & SMS
+package notify
+
+import "context"
+
+// Message is rendered notification. Subject is ignored by SMS
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier sends messages over one channel
+type Notifier interface {
+	// Channel is models.NotificationChannel* the notifier serves
+	Channel() string
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+)
+
+// serveSMTP answers one session of plain SMTP and returns commands & data it got
+func serveSMTP(t *testing.T, listener net.Listener) <-chan []string {
+	t.Helper()
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ready")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case inData && line == ".":
+				inData = false
+				reply("250 queued")
+			case inData:
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case line == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case line == "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+		received <- lines
+	}()
+	return received
+}
+
+func TestSMTPNotifierSend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := serveSMTP(t, listener)
+
+	notifier := NewSMTPNotifier(config.SMTPConfig{Addr: listener.Addr().String(), From: "clinic@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notifier.Send(ctx, Message{To: "owner@example.com", Subject: "Recheck", Body: "Hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	session := strings.Join(<-received, "\n")
+	for _, want := range []string{"MAIL FROM:<clinic@example.com>", "RCPT TO:<owner@example.com>", "Subject: Recheck"} {
+		if !strings.Contains(session, want) {
+			t.Errorf("session has no %q:\n%s", want, session)
+		}
+	}
+}
+
+func TestSMTPNotifierSendTimeout(t *testing.T) {
+	// server accepts connection but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	notifier := NewSMTPNotifier(config.SMTPConfig{Addr: listener.Addr().String(), From: "clinic@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := notifier.Send(ctx, Message{To: "owner@example.com", Body: "Hello"}); err == nil {
+		t.Fatal("want error from stalled server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send returned after %v, want deadline of context", elapsed)
+	}
+}
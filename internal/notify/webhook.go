@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+// WebhookSMSNotifier posts SMS as JSON {"to": "+7...", "text": "..."} to gateway. Any 2xx is success
+type WebhookSMSNotifier struct {
+	cfg    config.SMSWebhookConfig
+	client *http.Client
+}
+
+func NewWebhookSMSNotifier(cfg config.SMSWebhookConfig) *WebhookSMSNotifier {
+	return &WebhookSMSNotifier{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (n *WebhookSMSNotifier) Channel() string {
+	return models.NotificationChannelSMS
+}
+
+func (n *WebhookSMSNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(struct {
+		To   string `json:"to"`
+		Text string `json:"text"`
+	}{To: msg.To, Text: msg.Body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sms: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sms gateway responded %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package appointmentservice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	availabilityservice "github.com/vet-clinic-back/info-service/internal/service/availability-service"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// entryDateLayout is layout of medical entry date
+const entryDateLayout = "2006-01-02 15:04:05"
+
+// transitions lists statuses appointment can move to. Completed, no show & cancelled are final
+var transitions = map[string][]string{
+	models.AppointmentStatusBooked: {
+		models.AppointmentStatusCheckedIn, models.AppointmentStatusNoShow, models.AppointmentStatusCancelled,
+	},
+	models.AppointmentStatusCheckedIn: {models.AppointmentStatusCompleted, models.AppointmentStatusCancelled},
+}
+
+func canTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type AppointmentService struct {
+	log          *logging.Logger
+	storage      storage.Info
+	appointments storage.Appointment
+	tx           storage.Transactor
+}
+
+func New(
+	log *logging.Logger, storage storage.Info, appointments storage.Appointment, tx storage.Transactor,
+) *AppointmentService {
+	return &AppointmentService{log: log, storage: storage, appointments: appointments, tx: tx}
+}
+
+// BookAppointment creates appointment if vet works at that time and has no other appointment
+func (s *AppointmentService) BookAppointment(appointment models.Appointment) (models.Appointment, error) {
+	appointment.ID = 0
+	appointment.Status = models.AppointmentStatusBooked
+
+	var id uint
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		if _, err := tx.GetPet(models.Pet{ID: appointment.PetID}); err != nil {
+			return err
+		}
+		vet, err := tx.GetVet(appointment.VetID)
+		if err != nil {
+			return err
+		}
+		if appointment.ClinicNumber == "" {
+			appointment.ClinicNumber = vet.ClinicNumber
+		}
+
+		if err := availabilityservice.CheckWorkingTime(tx, appointment); err != nil {
+			return err
+		}
+		if err := availabilityservice.CheckConflicts(tx, appointment); err != nil {
+			return err
+		}
+
+		id, err = tx.CreateAppointment(appointment)
+		return err
+	})
+	if err != nil {
+		return models.Appointment{}, err
+	}
+
+	return s.appointments.GetAppointment(id)
+}
+
+func (s *AppointmentService) GetAppointment(id uint) (models.Appointment, error) {
+	return s.appointments.GetAppointment(id)
+}
+
+func (s *AppointmentService) GetAppointments(filter models.AppointmentReqFilter) ([]models.Appointment, error) {
+	return s.appointments.GetAppointments(filter)
+}
+
+// RescheduleAppointment moves booked appointment to other time and optionally other vet
+func (s *AppointmentService) RescheduleAppointment(
+	id uint, input models.ReschedulingAppointmentDTO,
+) (models.Appointment, error) {
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		appointment, err := tx.GetAppointment(id)
+		if err != nil {
+			return err
+		}
+		if appointment.Status != models.AppointmentStatusBooked {
+			return errs.Conflict("only booked appointment can be rescheduled, appointment is "+
+				appointment.Status, nil)
+		}
+
+		if input.VetID != 0 && input.VetID != appointment.VetID {
+			vet, err := tx.GetVet(input.VetID)
+			if err != nil {
+				return err
+			}
+			appointment.VetID = vet.ID
+			appointment.ClinicNumber = vet.ClinicNumber
+		}
+		appointment.StartsAt = input.StartsAt
+		appointment.EndsAt = input.EndsAt
+
+		if err := availabilityservice.CheckWorkingTime(tx, appointment); err != nil {
+			return err
+		}
+		if err := availabilityservice.CheckConflicts(tx, appointment); err != nil {
+			return err
+		}
+		return tx.UpdateAppointment(appointment, models.AppointmentStatusBooked)
+	})
+	if err != nil {
+		return models.Appointment{}, err
+	}
+
+	return s.appointments.GetAppointment(id)
+}
+
+func (s *AppointmentService) CancelAppointment(
+	id uint, input models.CancellingAppointmentDTO,
+) (models.Appointment, error) {
+	cancel := func(_ storage.Tx, appointment *models.Appointment) error {
+		appointment.CancelReason = input.Reason
+		return nil
+	}
+	return s.setStatus(id, models.AppointmentStatusCancelled, cancel)
+}
+
+func (s *AppointmentService) CheckInAppointment(id uint) (models.Appointment, error) {
+	return s.setStatus(id, models.AppointmentStatusCheckedIn, nil)
+}
+
+func (s *AppointmentService) MarkNoShow(id uint) (models.Appointment, error) {
+	return s.setStatus(id, models.AppointmentStatusNoShow, nil)
+}
+
+// CompleteAppointment completes appointment and creates medical entry stub in record of pet
+// for vet to fill in. Entry date is start of appointment in time zone of its clinic
+func (s *AppointmentService) CompleteAppointment(id uint) (models.Appointment, error) {
+	complete := func(tx storage.Tx, appointment *models.Appointment) error {
+		record, err := tx.GetMedRecordByPet(appointment.PetID)
+		if err != nil {
+			return err
+		}
+		loc, err := availabilityservice.ClinicLocation(tx, appointment.ClinicNumber)
+		if err != nil {
+			return err
+		}
+
+		description := "Appointment"
+		if appointment.Reason != "" {
+			description += ": " + appointment.Reason
+		}
+		entryID, err := tx.CreateMedEntry(models.MedicalEntry{
+			EntryDate:       appointment.StartsAt.In(loc).Format(entryDateLayout),
+			Description:     description,
+			MedicalRecordID: record.ID,
+			VetID:           appointment.VetID,
+		})
+		if err != nil {
+			return err
+		}
+
+		appointment.MedicalEntryID = entryID
+		return nil
+	}
+	return s.setStatus(id, models.AppointmentStatusCompleted, complete)
+}
+
+// setStatus moves appointment to status. apply changes appointment in same transaction
+func (s *AppointmentService) setStatus(
+	id uint, to string, apply func(tx storage.Tx, appointment *models.Appointment) error,
+) (models.Appointment, error) {
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		appointment, err := tx.GetAppointment(id)
+		if err != nil {
+			return err
+		}
+
+		from := appointment.Status
+		if !canTransition(from, to) {
+			allowed := strings.Join(transitions[from], ", ")
+			if allowed == "" {
+				allowed = "none"
+			}
+			return errs.Conflict(fmt.Sprintf("appointment can not change from %s to %s, allowed: %s",
+				from, to, allowed), nil)
+		}
+
+		appointment.Status = to
+		if apply != nil {
+			if err := apply(tx, &appointment); err != nil {
+				return err
+			}
+		}
+		return tx.UpdateAppointment(appointment, from)
+	})
+	if err != nil {
+		return models.Appointment{}, err
+	}
+
+	s.log.WithField("op", "AppointmentService.setStatus").Infof("appointment %d is %s", id, to)
+	return s.appointments.GetAppointment(id)
+}
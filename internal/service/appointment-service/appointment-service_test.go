@@ -0,0 +1,81 @@
+package appointmentservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+type fakeTx struct {
+	storage.Tx
+	appointment models.Appointment
+	clinics     map[string]models.Clinic
+	entry       models.MedicalEntry
+}
+
+func (f *fakeTx) WithTx(fn func(tx storage.Tx) error) error { return fn(f) }
+
+func (f *fakeTx) GetAppointment(uint) (models.Appointment, error) { return f.appointment, nil }
+
+func (f *fakeTx) UpdateAppointment(appointment models.Appointment, _ string) error {
+	f.appointment = appointment
+	return nil
+}
+
+func (f *fakeTx) GetClinic(number string) (models.Clinic, error) {
+	clinic, ok := f.clinics[number]
+	if !ok {
+		return models.Clinic{}, errs.NotFound("clinic not found", nil)
+	}
+	return clinic, nil
+}
+
+func (f *fakeTx) GetMedRecordByPet(petID uint) (models.MedicalRecord, error) {
+	return models.MedicalRecord{ID: 7, PetID: petID}, nil
+}
+
+func (f *fakeTx) CreateMedEntry(entry models.MedicalEntry) (uint, error) {
+	f.entry = entry
+	return 11, nil
+}
+
+func TestCompleteAppointmentEntryDate(t *testing.T) {
+	// 2026-03-01 21:30 UTC is next day in Vladivostok
+	startsAt := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+	clinics := map[string]models.Clinic{
+		"vvo": {Number: "vvo", TimeZone: "Asia/Vladivostok"},
+		"ny":  {Number: "ny", TimeZone: "America/New_York"},
+	}
+
+	tests := []struct {
+		clinic string
+		want   string
+	}{
+		{"vvo", "2026-03-02 07:30:00"},
+		{"ny", "2026-03-01 16:30:00"},
+		{"unknown", "2026-03-01 21:30:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.clinic, func(t *testing.T) {
+			tx := &fakeTx{clinics: clinics, appointment: models.Appointment{
+				ID: 1, PetID: 2, VetID: 3, ClinicNumber: tt.clinic, StartsAt: startsAt,
+				Status: models.AppointmentStatusCheckedIn,
+			}}
+			s := New(logging.NewLogger(new(bool), new(bool)), nil, tx, tx)
+
+			if _, err := s.CompleteAppointment(1); err != nil {
+				t.Fatalf("CompleteAppointment: %v", err)
+			}
+			if tx.entry.EntryDate != tt.want {
+				t.Errorf("entry date = %s, want %s", tx.entry.EntryDate, tt.want)
+			}
+			if tx.appointment.MedicalEntryID != 11 || tx.appointment.Status != models.AppointmentStatusCompleted {
+				t.Errorf("appointment = %+v, want completed with entry 11", tx.appointment)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package availabilityservice
+
+import (
+	"time"
+	// clinic time zones should not depend on zoneinfo of host
+	_ "time/tzdata"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+type AvailabilityService struct {
+	log          *logging.Logger
+	storage      storage.Info
+	availability storage.Availability
+	appointments storage.Appointment
+	tx           storage.Transactor
+}
+
+func New(
+	log *logging.Logger, storage storage.Info, availability storage.Availability, appointments storage.Appointment,
+	tx storage.Transactor,
+) *AvailabilityService {
+	return &AvailabilityService{
+		log: log, storage: storage, availability: availability, appointments: appointments, tx: tx,
+	}
+}
+
+func (s *AvailabilityService) GetClinics() ([]models.Clinic, error) {
+	return s.availability.GetClinics()
+}
+
+func (s *AvailabilityService) GetClinic(number string) (models.Clinic, error) {
+	return s.availability.GetClinic(number)
+}
+
+// SaveClinic creates or replaces clinic with its opening hours
+func (s *AvailabilityService) SaveClinic(clinic models.Clinic) (models.Clinic, error) {
+	if err := s.availability.SaveClinic(clinic); err != nil {
+		return models.Clinic{}, err
+	}
+	return s.availability.GetClinic(clinic.Number)
+}
+
+func (s *AvailabilityService) GetVetSchedule(vetID uint) ([]models.WorkingHours, error) {
+	if _, err := s.storage.GetVet(vetID); err != nil {
+		return nil, err
+	}
+	return s.availability.GetVetSchedule(vetID)
+}
+
+// SaveVetSchedule replaces weekly working hours of vet
+func (s *AvailabilityService) SaveVetSchedule(vetID uint, hours []models.WorkingHours) ([]models.WorkingHours, error) {
+	if _, err := s.storage.GetVet(vetID); err != nil {
+		return nil, err
+	}
+	if err := s.availability.SaveVetSchedule(vetID, hours); err != nil {
+		return nil, err
+	}
+	return s.availability.GetVetSchedule(vetID)
+}
+
+func (s *AvailabilityService) CreateScheduleException(
+	exception models.ScheduleException,
+) (models.ScheduleException, error) {
+	id, err := s.availability.CreateScheduleException(exception)
+	if err != nil {
+		return models.ScheduleException{}, err
+	}
+	exception.ID = id
+	return exception, nil
+}
+
+// GetScheduleExceptions returns exceptions of vet that did not end yet
+func (s *AvailabilityService) GetScheduleExceptions(vetID uint) ([]models.ScheduleException, error) {
+	if _, err := s.storage.GetVet(vetID); err != nil {
+		return nil, err
+	}
+	return s.availability.GetScheduleExceptions(vetID, time.Now().Format(models.DateLayout), "")
+}
+
+func (s *AvailabilityService) DeleteScheduleException(vetID, id uint) error {
+	return s.availability.DeleteScheduleException(vetID, id)
+}
+
+// CreateTimeBlock reserves time of vet if it does not overlap appointments or other blocks
+func (s *AvailabilityService) CreateTimeBlock(block models.TimeBlock) (models.TimeBlock, error) {
+	var id uint
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		if _, err := tx.GetVet(block.VetID); err != nil {
+			return err
+		}
+		if block.PetID != 0 {
+			if _, err := tx.GetPet(models.Pet{ID: block.PetID}); err != nil {
+				return err
+			}
+		}
+
+		err := CheckConflicts(tx, models.Appointment{
+			VetID: block.VetID, StartsAt: block.StartsAt, EndsAt: block.EndsAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		id, err = tx.CreateTimeBlock(block)
+		return err
+	})
+	if err != nil {
+		return models.TimeBlock{}, err
+	}
+
+	return s.availability.GetTimeBlock(id)
+}
+
+// GetTimeBlocks returns blocks of vet that did not end yet
+func (s *AvailabilityService) GetTimeBlocks(vetID uint) ([]models.TimeBlock, error) {
+	if _, err := s.storage.GetVet(vetID); err != nil {
+		return nil, err
+	}
+	return s.availability.GetTimeBlocks(vetID, time.Now(), time.Now().AddDate(100, 0, 0))
+}
+
+func (s *AvailabilityService) DeleteTimeBlock(vetID, id uint) error {
+	return s.availability.DeleteTimeBlock(vetID, id)
+}
+
+// GetAvailability returns free slots of vet: working hours within opening hours of clinic except
+// exception days, time blocks & active appointments. Dates of filter are local dates of clinic
+func (s *AvailabilityService) GetAvailability(
+	vetID uint, filter models.AvailabilityReqFilter,
+) (models.AvailabilityDTO, error) {
+	vet, err := s.storage.GetVet(vetID)
+	if err != nil {
+		return models.AvailabilityDTO{}, err
+	}
+
+	clinic, loc, err := clinicOf(s.availability, vet.ClinicNumber)
+	if err != nil {
+		return models.AvailabilityDTO{}, err
+	}
+
+	from, err := time.ParseInLocation(models.DateLayout, filter.From, loc)
+	if err != nil {
+		return models.AvailabilityDTO{}, errs.Validation("from should be date YYYY-MM-DD", err)
+	}
+	to, err := time.ParseInLocation(models.DateLayout, filter.To, loc)
+	if err != nil {
+		return models.AvailabilityDTO{}, errs.Validation("to should be date YYYY-MM-DD", err)
+	}
+	end := to.AddDate(0, 0, 1)
+
+	schedule, err := s.availability.GetVetSchedule(vetID)
+	if err != nil {
+		return models.AvailabilityDTO{}, err
+	}
+	exceptions, err := s.availability.GetScheduleExceptions(vetID, filter.From, filter.To)
+	if err != nil {
+		return models.AvailabilityDTO{}, err
+	}
+	busy, err := s.busyIntervals(vetID, from, end)
+	if err != nil {
+		return models.AvailabilityDTO{}, err
+	}
+
+	availability := models.AvailabilityDTO{
+		VetID:        vetID,
+		ClinicNumber: clinic.Number,
+		TimeZone:     loc.String(),
+		Slots: freeSlots(schedule, clinic.Hours, exceptions, busy, from, end, loc,
+			time.Duration(filter.Duration)*time.Minute, time.Now()),
+	}
+	return availability, nil
+}
+
+// busyIntervals returns time blocks & active appointments of vet between from & to
+func (s *AvailabilityService) busyIntervals(vetID uint, from, to time.Time) ([]interval, error) {
+	blocks, err := s.availability.GetTimeBlocks(vetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := s.appointments.GetAppointments(models.AppointmentReqFilter{
+		VetID: &vetID, From: &from, To: &to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]interval, 0, len(blocks)+len(appointments))
+	for _, block := range blocks {
+		busy = append(busy, interval{start: block.StartsAt, end: block.EndsAt})
+	}
+	for _, appointment := range appointments {
+		if appointment.Status == models.AppointmentStatusBooked ||
+			appointment.Status == models.AppointmentStatusCheckedIn {
+			busy = append(busy, interval{start: appointment.StartsAt, end: appointment.EndsAt})
+		}
+	}
+	return busy, nil
+}
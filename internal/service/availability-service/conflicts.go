@@ -0,0 +1,97 @@
+package availabilityservice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// clinicOf returns clinic & its location. Vet of clinic that is not configured works in UTC
+// without opening hours
+func clinicOf(availability storage.Availability, number string) (models.Clinic, *time.Location, error) {
+	clinic, err := availability.GetClinic(number)
+	if errors.Is(err, errs.ErrNotFound) {
+		clinic, err = models.Clinic{Number: number, TimeZone: "UTC"}, nil
+	}
+	if err != nil {
+		return models.Clinic{}, nil, err
+	}
+	loc, err := time.LoadLocation(clinic.TimeZone)
+	if err != nil {
+		return models.Clinic{}, nil, err
+	}
+	return clinic, loc, nil
+}
+
+// ClinicLocation is time zone of clinic, UTC if clinic is not configured
+func ClinicLocation(availability storage.Availability, number string) (*time.Location, error) {
+	_, loc, err := clinicOf(availability, number)
+	return loc, err
+}
+
+// CheckWorkingTime rejects appointment outside of working time of vet, the same working time
+// GetAvailability splits into slots
+func CheckWorkingTime(tx storage.Tx, appointment models.Appointment) error {
+	vet, err := tx.GetVet(appointment.VetID)
+	if err != nil {
+		return err
+	}
+	clinic, loc, err := clinicOf(tx, vet.ClinicNumber)
+	if err != nil {
+		return err
+	}
+
+	start, end := appointment.StartsAt.In(loc), appointment.EndsAt.In(loc)
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, loc)
+
+	schedule, err := tx.GetVetSchedule(vet.ID)
+	if err != nil {
+		return err
+	}
+	exceptions, err := tx.GetScheduleExceptions(vet.ID, from.Format(models.DateLayout),
+		to.AddDate(0, 0, -1).Format(models.DateLayout))
+	if err != nil {
+		return err
+	}
+
+	work := workingIntervals(schedule, clinic.Hours, exceptions, from, to, loc)
+	if !covered(work, interval{start: appointment.StartsAt, end: appointment.EndsAt}) {
+		return errs.Conflict(fmt.Sprintf("vet does not work from %s to %s (%s)",
+			start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"), loc), nil)
+	}
+	return nil
+}
+
+// CheckConflicts locks schedule of vet until end of transaction and rejects appointment
+// overlapping other active appointment or time block of vet
+func CheckConflicts(tx storage.Tx, appointment models.Appointment) error {
+	if err := tx.LockVetSchedule(appointment.VetID); err != nil {
+		return err
+	}
+
+	overlapping, err := tx.GetOverlappingAppointments(appointment)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		other := overlapping[0]
+		return errs.Conflict(fmt.Sprintf("vet already has appointment %d from %s to %s", other.ID,
+			other.StartsAt.Format("2006-01-02 15:04"), other.EndsAt.Format("15:04")), nil)
+	}
+
+	blocks, err := tx.GetTimeBlocks(appointment.VetID, appointment.StartsAt, appointment.EndsAt)
+	if err != nil {
+		return err
+	}
+	if len(blocks) > 0 {
+		block := blocks[0]
+		return errs.Conflict(fmt.Sprintf("vet time from %s to %s is reserved by block %d",
+			block.StartsAt.Format("2006-01-02 15:04"), block.EndsAt.Format("15:04"), block.ID), nil)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package availabilityservice
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+// interval is half-open time interval [start, end)
+type interval struct {
+	start, end time.Time
+}
+
+// dayIntervals returns weekly hours of day in location, sorted by start
+func dayIntervals(hours []models.WorkingHours, day time.Time, loc *time.Location) []interval {
+	var intervals []interval
+	for _, h := range hours {
+		if time.Weekday(h.Weekday) != day.Weekday() {
+			continue
+		}
+		start, err1 := time.Parse("15:04", h.Start)
+		end, err2 := time.Parse("15:04", h.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		intervals = append(intervals, interval{
+			start: time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc),
+			end:   time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc),
+		})
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+	return intervals
+}
+
+// intersect returns parts of a that are within b
+func intersect(a, b []interval) []interval {
+	var result []interval
+	for _, x := range a {
+		for _, y := range b {
+			start, end := x.start, x.end
+			if y.start.After(start) {
+				start = y.start
+			}
+			if y.end.Before(end) {
+				end = y.end
+			}
+			if start.Before(end) {
+				result = append(result, interval{start: start, end: end})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].start.Before(result[j].start) })
+	return result
+}
+
+// subtract returns parts of intervals not covered by busy
+func subtract(intervals, busy []interval) []interval {
+	result := intervals
+	for _, b := range busy {
+		var next []interval
+		for _, x := range result {
+			if !b.start.Before(x.end) || !b.end.After(x.start) {
+				next = append(next, x)
+				continue
+			}
+			if b.start.After(x.start) {
+				next = append(next, interval{start: x.start, end: b.start})
+			}
+			if b.end.Before(x.end) {
+				next = append(next, interval{start: b.end, end: x.end})
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+func excepted(day time.Time, exceptions []models.ScheduleException) bool {
+	date := day.Format(models.DateLayout)
+	for _, exception := range exceptions {
+		// YYYY-MM-DD dates compare as strings
+		if exception.StartDate <= date && date <= exception.EndDate {
+			return true
+		}
+	}
+	return false
+}
+
+// workingIntervals returns working time of each day between from & to: schedule within clinic
+// hours if clinic has them, except exception days
+func workingIntervals(
+	schedule, clinicHours []models.WorkingHours, exceptions []models.ScheduleException,
+	from, to time.Time, loc *time.Location,
+) []interval {
+	var work []interval
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if excepted(day, exceptions) {
+			continue
+		}
+
+		hours := dayIntervals(schedule, day, loc)
+		if len(clinicHours) > 0 {
+			hours = intersect(hours, dayIntervals(clinicHours, day, loc))
+		}
+		work = append(work, hours...)
+	}
+	return work
+}
+
+// covered reports whether x is within intervals, adjacent intervals count as one
+func covered(intervals []interval, x interval) bool {
+	sorted := append([]interval(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	reached := x.start
+	for _, i := range sorted {
+		if i.start.After(reached) {
+			break
+		}
+		if i.end.After(reached) {
+			reached = i.end
+		}
+		if !reached.Before(x.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// freeSlots splits free time of each day between from & to into consecutive slots of duration.
+// Clinic hours limit working hours only if clinic has them, slots starting before now are skipped
+func freeSlots(
+	schedule, clinicHours []models.WorkingHours, exceptions []models.ScheduleException, busy []interval,
+	from, to time.Time, loc *time.Location, duration time.Duration, now time.Time,
+) []models.Slot {
+	slots := []models.Slot{}
+	if duration <= 0 {
+		return slots
+	}
+	work := workingIntervals(schedule, clinicHours, exceptions, from, to, loc)
+	for _, free := range subtract(work, busy) {
+		for start := free.start; !start.Add(duration).After(free.end); start = start.Add(duration) {
+			if start.Before(now) {
+				continue
+			}
+			slots = append(slots, models.Slot{StartsAt: start, EndsAt: start.Add(duration)})
+		}
+	}
+	return slots
+}
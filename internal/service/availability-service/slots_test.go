@@ -0,0 +1,111 @@
+package availabilityservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func TestFreeSlots(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 5, day, hour, minute, 0, 0, loc) }
+	// 2024-05-06 is Monday
+	monday := at(6, 0, 0)
+	schedule := []models.WorkingHours{{Weekday: 1, Start: "09:00", End: "11:00"}}
+
+	tests := []struct {
+		name        string
+		clinicHours []models.WorkingHours
+		exceptions  []models.ScheduleException
+		busy        []interval
+		to          time.Time
+		duration    time.Duration
+		now         time.Time
+		want        []time.Time
+	}{
+		{
+			name: "whole schedule", to: at(7, 0, 0), duration: time.Hour,
+			want: []time.Time{at(6, 9, 0), at(6, 10, 0)},
+		},
+		{
+			name: "clinic hours limit schedule", to: at(7, 0, 0), duration: 30 * time.Minute,
+			clinicHours: []models.WorkingHours{{Weekday: 1, Start: "10:00", End: "18:00"}},
+			want:        []time.Time{at(6, 10, 0), at(6, 10, 30)},
+		},
+		{
+			name: "clinic closed on weekday", to: at(7, 0, 0), duration: 30 * time.Minute,
+			clinicHours: []models.WorkingHours{{Weekday: 2, Start: "09:00", End: "18:00"}},
+		},
+		{
+			name: "exception day", to: at(7, 0, 0), duration: time.Hour,
+			exceptions: []models.ScheduleException{{StartDate: "2024-05-01", EndDate: "2024-05-06"}},
+		},
+		{
+			name: "busy time is skipped", to: at(7, 0, 0), duration: 30 * time.Minute,
+			busy: []interval{{start: at(6, 9, 30), end: at(6, 10, 15)}},
+			want: []time.Time{at(6, 9, 0), at(6, 10, 15)},
+		},
+		{
+			name: "slots before now are skipped", to: at(7, 0, 0), duration: time.Hour,
+			now: at(6, 9, 30), want: []time.Time{at(6, 10, 0)},
+		},
+		{
+			name: "next week", to: at(14, 0, 0), duration: 2 * time.Hour,
+			want: []time.Time{at(6, 9, 0), at(13, 9, 0)},
+		},
+		{name: "no duration", to: at(7, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := freeSlots(schedule, tt.clinicHours, tt.exceptions, tt.busy, monday, tt.to, loc,
+				tt.duration, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d slots %v, want %v", len(got), got, tt.want)
+			}
+			for i, slot := range got {
+				if !slot.StartsAt.Equal(tt.want[i]) || !slot.EndsAt.Equal(tt.want[i].Add(tt.duration)) {
+					t.Errorf("slot %d = %v - %v, want start %v", i, slot.StartsAt, slot.EndsAt, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCovered(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2024, 5, 6, hour, minute, 0, 0, time.UTC) }
+	work := []interval{
+		{start: at(14, 0), end: at(18, 0)},
+		{start: at(9, 0), end: at(12, 0)},
+		{start: at(12, 0), end: at(13, 0)},
+	}
+
+	tests := []struct {
+		name string
+		x    interval
+		want bool
+	}{
+		{"within interval", interval{start: at(9, 30), end: at(10, 0)}, true},
+		{"whole interval", interval{start: at(14, 0), end: at(18, 0)}, true},
+		{"across adjacent intervals", interval{start: at(11, 30), end: at(12, 30)}, true},
+		{"across gap", interval{start: at(12, 30), end: at(14, 30)}, false},
+		{"starts before work", interval{start: at(8, 30), end: at(9, 30)}, false},
+		{"ends after work", interval{start: at(17, 30), end: at(18, 30)}, false},
+		{"outside work", interval{start: at(19, 0), end: at(20, 0)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := covered(work, tt.x); got != tt.want {
+				t.Errorf("covered = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if covered(nil, interval{start: at(9, 0), end: at(10, 0)}) {
+		t.Error("covered without working time")
+	}
+}
@@ -0,0 +1,121 @@
+package calendarservice
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// pastFollowUpDays is how long follow-ups stay in feed after their date
+const pastFollowUpDays = 90
+
+type CalendarService struct {
+	log      *logging.Logger
+	storage  storage.Info
+	calendar storage.Calendar
+}
+
+func New(log *logging.Logger, storage storage.Info, calendar storage.Calendar) *CalendarService {
+	return &CalendarService{log: log, storage: storage, calendar: calendar}
+}
+
+// CreateCalendarFeed issues new feed token for vet or owner. Token is returned only here
+func (s *CalendarService) CreateCalendarFeed(kind string, subjectID uint) (models.CalendarFeed, error) {
+	if err := s.checkSubject(kind, subjectID); err != nil {
+		return models.CalendarFeed{}, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+
+	feed, err := s.calendar.CreateCalendarFeed(
+		models.CalendarFeed{Kind: kind, SubjectID: subjectID}, hashToken(token),
+	)
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+	feed.Token = token
+
+	return feed, nil
+}
+
+// GetCalendarFeeds returns feeds of vet or owner, revoked ones included
+func (s *CalendarService) GetCalendarFeeds(kind string, subjectID uint) ([]models.CalendarFeed, error) {
+	if err := s.checkSubject(kind, subjectID); err != nil {
+		return nil, err
+	}
+	return s.calendar.GetCalendarFeeds(kind, subjectID)
+}
+
+func (s *CalendarService) RevokeCalendarFeed(kind string, subjectID, id uint) error {
+	return s.calendar.RevokeCalendarFeed(kind, subjectID, id)
+}
+
+// GetCalendar renders iCalendar of follow-ups of feed with token
+func (s *CalendarService) GetCalendar(token string) ([]byte, error) {
+	feed, err := s.calendar.GetCalendarFeedByToken(hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	filter := models.FollowUpReqFilter{
+		From: now.AddDate(0, 0, -pastFollowUpDays).Format(models.DateLayout),
+	}
+	name := "Follow-ups"
+	switch feed.Kind {
+	case models.CalendarFeedKindVet:
+		filter.VetID = &feed.SubjectID
+		if vet, err := s.storage.GetVet(feed.SubjectID); err == nil {
+			name = "Follow-ups: " + vet.FullName
+		}
+	case models.CalendarFeedKindOwner:
+		filter.OwnerID = &feed.SubjectID
+	default:
+		return nil, fmt.Errorf("unknown calendar feed kind %q", feed.Kind)
+	}
+
+	followUps, err := s.calendar.GetFollowUps(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderCalendar(name, feed.Kind, followUps, now), nil
+}
+
+func (s *CalendarService) checkSubject(kind string, subjectID uint) error {
+	switch kind {
+	case models.CalendarFeedKindVet:
+		_, err := s.storage.GetVet(subjectID)
+		return err
+	case models.CalendarFeedKindOwner:
+		_, err := s.storage.GetOwner(models.Owner{ID: subjectID})
+		return err
+	default:
+		return fmt.Errorf("unknown calendar feed kind %q", kind)
+	}
+}
+
+// newToken returns 256 bit random token safe for URL path
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is stored instead of token so leaked database does not leak feeds
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
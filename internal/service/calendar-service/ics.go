@@ -0,0 +1,105 @@
+package calendarservice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405Z"
+	// icsLineOctets is max length of content line without CRLF, RFC 5545 3.1
+	icsLineOctets = 75
+)
+
+// renderCalendar writes follow-ups as all-day events. UID depends only on entry so calendar apps update
+// events on refresh instead of duplicating them
+func renderCalendar(name, kind string, followUps []models.FollowUp, now time.Time) []byte {
+	var buf bytes.Buffer
+	w := icsWriter{buf: &buf}
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//vet-clinic//info-service//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.text("X-WR-CALNAME", name)
+	w.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	w.line("X-PUBLISHED-TTL:PT1H")
+
+	stamp := now.UTC().Format(icsDateTimeLayout)
+	for _, followUp := range followUps {
+		day, err := time.Parse(models.DateLayout, followUp.FollowUpAt)
+		if err != nil {
+			continue
+		}
+
+		w.line("BEGIN:VEVENT")
+		w.line(fmt.Sprintf("UID:follow-up-%d@info-service.vet-clinic", followUp.EntryID))
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART;VALUE=DATE:" + day.Format(icsDateLayout))
+		w.line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format(icsDateLayout))
+		w.text("SUMMARY", "Recheck: "+followUp.PetName)
+		w.text("DESCRIPTION", followUpDescription(kind, followUp))
+		w.line("TRANSP:TRANSPARENT")
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// followUpDescription names owner for vet and vet for owner
+func followUpDescription(kind string, followUp models.FollowUp) string {
+	var lines []string
+	if followUp.Disease != "" {
+		lines = append(lines, "Disease: "+followUp.Disease)
+	}
+	if followUp.Recommendation != "" {
+		lines = append(lines, "Recommendation: "+followUp.Recommendation)
+	}
+	if kind == models.CalendarFeedKindVet && followUp.OwnerName != "" {
+		lines = append(lines, "Owner: "+followUp.OwnerName)
+	}
+	if kind == models.CalendarFeedKindOwner && followUp.VetName != "" {
+		lines = append(lines, "Vet: "+followUp.VetName)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type icsWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w icsWriter) text(name, value string) {
+	w.line(name + ":" + escapeText(value))
+}
+
+// line writes content line folded at 75 octets without splitting UTF-8 characters
+func (w icsWriter) line(line string) {
+	limit := icsLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with space
+		limit = icsLineOctets - 1
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
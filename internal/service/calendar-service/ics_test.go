@@ -0,0 +1,110 @@
+package calendarservice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"plain", "plain"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"a\nb", `a\nb`},
+		{"a\r\nb", `a\nb`},
+		{"a\rb", `a\nb`},
+		{`\n`, `\\n`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeText(tt.value); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Recheck"},
+		{"exactly 75 octets", strings.Repeat("a", icsLineOctets)},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"cyrillic", "DESCRIPTION:" + strings.Repeat("Повторный осмотр ", 15)},
+		{"emoji", "SUMMARY:" + strings.Repeat("🐈", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			icsWriter{buf: &buf}.line(tt.line)
+
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line %q does not end with CRLF", out)
+			}
+			physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			var unfolded strings.Builder
+			for i, part := range physical {
+				if len(part) > icsLineOctets {
+					t.Errorf("line %d has %d octets, max is %d", i, len(part), icsLineOctets)
+				}
+				if !utf8.ValidString(part) {
+					t.Errorf("line %d splits UTF-8 character: %q", i, part)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(part, " ") {
+						t.Fatalf("continuation line %d does not start with space: %q", i, part)
+					}
+					part = part[1:]
+				}
+				unfolded.WriteString(part)
+			}
+			if unfolded.String() != tt.line {
+				t.Errorf("unfolded line = %q, want %q", unfolded.String(), tt.line)
+			}
+			if len(tt.line) <= icsLineOctets && len(physical) != 1 {
+				t.Errorf("line of %d octets is folded", len(tt.line))
+			}
+		})
+	}
+}
+
+func TestRenderCalendar(t *testing.T) {
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	followUps := []models.FollowUp{
+		{EntryID: 7, FollowUpAt: "2024-05-31", PetName: "Tom", Disease: "otitis; left ear", OwnerName: "Ann",
+			VetName: "Dr. Lee"},
+		{EntryID: 8, FollowUpAt: "not a date", PetName: "Skipped"},
+	}
+
+	ics := string(renderCalendar("Follow-ups", models.CalendarFeedKindOwner, followUps, now))
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:follow-up-7@info-service.vet-clinic\r\n",
+		"DTSTAMP:20240506T100000Z\r\n",
+		"DTSTART;VALUE=DATE:20240531\r\n",
+		"DTEND;VALUE=DATE:20240601\r\n",
+		"SUMMARY:Recheck: Tom\r\n",
+		`DESCRIPTION:Disease: otitis\; left ear\nVet: Dr. Lee` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, ics)
+		}
+	}
+	if strings.Contains(ics, "Skipped") || strings.Count(ics, "BEGIN:VEVENT") != 1 {
+		t.Errorf("follow-up with invalid date is rendered:\n%s", ics)
+	}
+	if strings.Contains(ics, "Owner: Ann") {
+		t.Errorf("owner feed names owner:\n%s", ics)
+	}
+}
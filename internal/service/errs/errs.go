@@ -0,0 +1,83 @@
+package errs
+
+import "errors"
+
+// Domain error kinds. Check them with errors.Is, storage and service wrap them into *Error
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrForeignKey       = errors.New("foreign key violation")
+	ErrValidation       = errors.New("validation failed")
+	ErrForbidden        = errors.New("forbidden")
+	ErrUnsupportedMedia = errors.New("unsupported media type")
+	ErrKeyReused        = errors.New("idempotency key reused")
+	ErrRolledBack       = errors.New("rolled back")
+	ErrGone             = errors.New("gone")
+)
+
+// Error is domain error. Detail is safe to show to client, Err is internal cause and is only logged
+type Error struct {
+	Kind   error
+	Detail string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Detail + ": " + e.Err.Error()
+	}
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func NotFound(detail string, err error) error {
+	return &Error{Kind: ErrNotFound, Detail: detail, Err: err}
+}
+
+func Conflict(detail string, err error) error {
+	return &Error{Kind: ErrConflict, Detail: detail, Err: err}
+}
+
+func ForeignKey(detail string, err error) error {
+	return &Error{Kind: ErrForeignKey, Detail: detail, Err: err}
+}
+
+func Validation(detail string, err error) error {
+	return &Error{Kind: ErrValidation, Detail: detail, Err: err}
+}
+
+func Forbidden(detail string, err error) error {
+	return &Error{Kind: ErrForbidden, Detail: detail, Err: err}
+}
+
+func UnsupportedMedia(detail string, err error) error {
+	return &Error{Kind: ErrUnsupportedMedia, Detail: detail, Err: err}
+}
+
+func KeyReused(detail string, err error) error {
+	return &Error{Kind: ErrKeyReused, Detail: detail, Err: err}
+}
+
+func RolledBack(detail string, err error) error {
+	return &Error{Kind: ErrRolledBack, Detail: detail, Err: err}
+}
+
+func Gone(detail string, err error) error {
+	return &Error{Kind: ErrGone, Detail: detail, Err: err}
+}
+
+// Detail returns client safe message of domain error or empty string
+func Detail(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Detail
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+package exportservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	schedulerservice "github.com/vet-clinic-back/info-service/internal/service/scheduler-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+const (
+	// progressPets is how often running job saves progress
+	progressPets = 100
+	// staleExportAfter is how long pending or running job may go without progress before it is failed,
+	// replica running it is considered dead
+	staleExportAfter = 30 * time.Minute
+)
+
+type ExportService struct {
+	log     *logging.Logger
+	storage storage.Info
+	exports storage.Export
+	cfg     config.ExportConfig
+}
+
+func New(log *logging.Logger, storage storage.Info, exports storage.Export, cfg config.ExportConfig) *ExportService {
+	return &ExportService{log: log, storage: storage, exports: exports, cfg: cfg}
+}
+
+// CreateExport saves job and runs it in background
+func (s *ExportService) CreateExport(job models.ExportJob) (models.ExportJob, error) {
+	if job.Type == models.ExportTypeResearch && s.cfg.PseudonymKey == "" {
+		return models.ExportJob{}, errs.Forbidden("research exports are disabled, pseudonym key is not configured", nil)
+	}
+
+	// pagination would make export partial, pet of research entries is taken from pets criteria
+	job.Criteria.Pets.Limit, job.Criteria.Pets.Offset = nil, nil
+	job.Criteria.Entries.Limit, job.Criteria.Entries.Offset = nil, nil
+	if job.Type == models.ExportTypeResearch {
+		job.Criteria.Entries.PetID, job.Criteria.Entries.EntryID = nil, nil
+	}
+	job.Status = models.ExportStatusPending
+
+	id, err := s.exports.CreateExportJob(job)
+	if err != nil {
+		return models.ExportJob{}, err
+	}
+
+	job, err = s.exports.GetExportJob(id)
+	if err != nil {
+		return models.ExportJob{}, err
+	}
+
+	go s.runInBackground(job.ID)
+
+	return job, nil
+}
+
+func (s *ExportService) GetExport(id uint) (models.ExportJob, error) {
+	return s.exports.GetExportJob(id)
+}
+
+// GetExportFile returns completed job which file can be downloaded. Expired file is gone even if purge
+// has not deleted it yet
+func (s *ExportService) GetExportFile(id uint) (models.ExportJob, error) {
+	job, err := s.exports.GetExportJob(id)
+	if err != nil {
+		return models.ExportJob{}, err
+	}
+	expired := job.Status == models.ExportStatusExpired ||
+		(job.Status == models.ExportStatusCompleted && job.ExpiresAt != nil && !time.Now().Before(*job.ExpiresAt))
+	if expired {
+		return models.ExportJob{}, errs.Gone("export file expired", nil)
+	}
+	if job.Status != models.ExportStatusCompleted {
+		return models.ExportJob{}, errs.Conflict("export is not completed, job is "+job.Status, nil)
+	}
+	return job, nil
+}
+
+// WriteExportFile copies stored file of job to w
+func (s *ExportService) WriteExportFile(id uint, w io.Writer) error {
+	return s.exports.IterateExportFile(id, func(data []byte) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+func (s *ExportService) runInBackground(id uint) {
+	if _, err := s.RunExport(id); err != nil {
+		s.log.WithField("op", "ExportService.runInBackground").
+			WithField("job_id", id).Error("export failed: ", err.Error())
+	}
+}
+
+// RunExport writes export file synchronously. Failed job is not resumed, new export should be created
+func (s *ExportService) RunExport(id uint) (models.ExportJob, error) {
+	log := s.log.WithField("op", "ExportService.RunExport").WithField("job_id", id)
+
+	job, err := s.exports.GetExportJob(id)
+	if err != nil {
+		return models.ExportJob{}, err
+	}
+	if job.Status != models.ExportStatusPending {
+		return job, nil
+	}
+
+	job.Status = models.ExportStatusRunning
+	if err := s.exports.UpdateExportJob(job); err != nil {
+		return job, err
+	}
+
+	log.Info("export started")
+
+	err = s.process(&job)
+	if err == nil {
+		err = s.storeFile(&job)
+	}
+	if err != nil {
+		job.Status = models.ExportStatusFailed
+		job.Error = err.Error()
+		if updateErr := s.exports.UpdateExportJob(job); updateErr != nil {
+			log.Error("failed to save failed status: ", updateErr.Error())
+		}
+		return job, err
+	}
+
+	expiresAt := time.Now().Add(s.cfg.TTL)
+	job.Status = models.ExportStatusCompleted
+	job.ExpiresAt = &expiresAt
+	if err := s.exports.UpdateExportJob(job); err != nil {
+		// job was failed as stale meanwhile, its file will never be downloaded
+		if deleteErr := s.exports.DeleteExportFile(job.ID); deleteErr != nil {
+			log.Error("failed to delete file of lost job: ", deleteErr.Error())
+		}
+		return job, err
+	}
+
+	log.Infof("export completed, %d pets & %d entries", job.ExportedPets, job.ExportedEntries)
+	return job, nil
+}
+
+func (s *ExportService) process(job *models.ExportJob) error {
+	if err := os.MkdirAll(s.cfg.Dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create export dir: %w", err)
+	}
+
+	switch job.Type {
+	case models.ExportTypeResearch:
+		return s.exportResearch(job)
+	case models.ExportTypePets, models.ExportTypeEntries:
+		return s.exportListing(job)
+	default:
+		return fmt.Errorf("unknown export type %s", job.Type)
+	}
+}
+
+// storeFile moves local file of job to storage, so it can be downloaded from any replica
+func (s *ExportService) storeFile(job *models.ExportJob) error {
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open export file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	size, err := s.exports.SaveExportFile(job.ID, file)
+	if err != nil {
+		return err
+	}
+
+	job.FileName = fmt.Sprintf("export-%d%s", job.ID, filepath.Ext(job.FilePath))
+	job.FileSize = size
+	job.FilePath = ""
+	return nil
+}
+
+// Jobs returns purge of expired export files & recovery of exports abandoned by dead replicas
+func (s *ExportService) Jobs() []schedulerservice.Job {
+	return []schedulerservice.Job{
+		{
+			Name:        "exports.purge",
+			Description: "Deletes files of expired exports",
+			Spec:        "@hourly",
+			Run: func(context.Context) error {
+				return s.purge(time.Now())
+			},
+		},
+		{
+			Name:        "exports.fail-stale",
+			Description: "Fails exports without progress, their replica is gone",
+			Spec:        "@every 5m",
+			Run: func(context.Context) error {
+				failed, err := s.exports.FailStaleExportJobs(staleExportAfter, "export was interrupted, create new one")
+				if err == nil && failed > 0 {
+					s.log.WithField("op", "ExportService.failStale").Warn("failed stale exports: ", failed)
+				}
+				return err
+			},
+		},
+	}
+}
+
+// purge deletes files of jobs expired before now, jobs are kept with expired status
+func (s *ExportService) purge(now time.Time) error {
+	log := s.log.WithField("op", "ExportService.purge")
+
+	jobs, err := s.exports.GetExpiredExportJobs(now)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := s.exports.DeleteExportFile(job.ID); err != nil {
+			return err
+		}
+		job.Status = models.ExportStatusExpired
+		if err := s.exports.UpdateExportJob(job); err != nil {
+			return err
+		}
+	}
+
+	if len(jobs) > 0 {
+		log.Info("deleted expired exports: ", len(jobs))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package exportservice
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+// progressRows is how often running listing export saves progress
+const progressRows = 500
+
+// exportListing writes CSV or parquet file of pets or medical entries, rows are same as in GET listings. Rows
+// are written while they are read, so memory does not grow with export
+func (s *ExportService) exportListing(job *models.ExportJob) (err error) {
+	file, err := os.CreateTemp(s.cfg.Dir, fmt.Sprintf("%s-%d-*%s", job.Type, job.ID, fileExt(job.Format)))
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close export file: %w", closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(file.Name())
+			return
+		}
+		job.FilePath = file.Name()
+	}()
+
+	header := models.EntryCSVHeader
+	if job.Type == models.ExportTypePets {
+		header = models.PetCSVHeader
+	}
+	writer, err := newTableWriter(job.Format, file, header)
+	if err != nil {
+		return err
+	}
+
+	if job.Type == models.ExportTypePets {
+		err = s.writePets(job, writer)
+	} else {
+		err = s.writeEntries(job, writer)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", job.Type, err)
+	}
+	return nil
+}
+
+func (s *ExportService) writePets(job *models.ExportJob, writer tableWriter) error {
+	return s.storage.IteratePetsWithOwnerAndVet(job.Criteria.Pets, func(pet models.OutputPetDTO) error {
+		if err := writer.Write(models.PetCSVRecord(pet)); err != nil {
+			return fmt.Errorf("failed to write pet: %w", err)
+		}
+		job.ExportedPets++
+		if job.ExportedPets%progressRows == 0 {
+			return s.exports.UpdateExportJob(*job)
+		}
+		return nil
+	})
+}
+
+func (s *ExportService) writeEntries(job *models.ExportJob, writer tableWriter) error {
+	return s.storage.IterateMedEntries(job.Criteria.Entries, func(entry models.MedicalEntry) error {
+		if err := writer.Write(models.EntryCSVRecord(entry)); err != nil {
+			return fmt.Errorf("failed to write entry: %w", err)
+		}
+		job.ExportedEntries++
+		if job.ExportedEntries%progressRows == 0 {
+			return s.exports.UpdateExportJob(*job)
+		}
+		return nil
+	})
+}
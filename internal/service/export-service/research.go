@@ -0,0 +1,378 @@
+package exportservice
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+const (
+	// petsTable & entriesTable are names of zip members without extension of format
+	petsTable    = "pets"
+	entriesTable = "entries"
+	manifestFile = "manifest.json"
+	// minNamePartLen skips initials & short words when parts of full names are scrubbed
+	minNamePartLen = 3
+)
+
+const (
+	pseudonymMethod = "HMAC-SHA256 of source ID keyed with clinic pseudonym key, first 16 hex digits"
+	offsetMethod    = "days relative to first exported medical entry of pet"
+	scrubMethod     = "names of pet, owner & vets, e-mails and phone numbers replaced with [NAME], [EMAIL], [PHONE]"
+)
+
+var petFields = []models.ExportField{
+	{Name: "pet_pid", Description: "pet", Anonymization: pseudonymMethod},
+	{Name: "owner_pid", Description: "owner, pets of one household share it", Anonymization: pseudonymMethod},
+	{Name: "vet_pid", Description: "vet of medical record", Anonymization: pseudonymMethod},
+	{Name: "species", Description: "species catalogue code"},
+	{Name: "breed", Description: "breed catalogue code"},
+	{Name: "gender", Description: "Male or Female"},
+	{Name: "weight", Description: "latest weight, kg"},
+	{Name: "birth_offset_days", Description: "birth date, empty if pet has no exported entries",
+		Anonymization: offsetMethod},
+	{Name: "birth_date_estimated", Description: "birth date is approximate"},
+	{Name: "condition", Description: "condition", Anonymization: scrubMethod},
+	{Name: "behavior", Description: "behavior", Anonymization: scrubMethod},
+	{Name: "research_status", Description: "research stage"},
+}
+
+var entryFields = []models.ExportField{
+	{Name: "entry_pid", Description: "medical entry", Anonymization: pseudonymMethod},
+	{Name: "pet_pid", Description: "pet", Anonymization: pseudonymMethod},
+	{Name: "vet_pid", Description: "vet who made entry", Anonymization: pseudonymMethod},
+	{Name: "day_offset", Description: "entry date", Anonymization: offsetMethod},
+	{Name: "description", Description: "description", Anonymization: scrubMethod},
+	{Name: "disease", Description: "disease", Anonymization: scrubMethod},
+	{Name: "vaccinations", Description: "vaccinations", Anonymization: scrubMethod},
+	{Name: "recommendation", Description: "recommendation", Anonymization: scrubMethod},
+}
+
+var droppedColumns = []string{
+	"pet.name", "pet.microchip", "pet.birth_date", "pet.age", "owner.fullname", "owner.email", "owner.phone",
+	"vet.fullname", "vet.email", "vet.phone", "medical_entry.entry_date", "medical_entry.device_number",
+}
+
+var anonymization = []string{
+	"IDs are pseudonyms: " + pseudonymMethod + ". They are stable between exports while key is the same",
+	"dates are " + offsetMethod,
+	"free text: " + scrubMethod,
+	"owner contacts, pet name & microchip are dropped",
+	"only pets with research status " + strings.Join(models.ConsentedResearchStatuses, ", ") + " are exported",
+}
+
+var (
+	emailRe = regexp.MustCompile(`[\p{L}\p{N}._%+-]+@[\p{L}\p{N}.-]+\.\p{L}{2,}`)
+	// phoneRe is 10 digit number grouped 3-3-2-2 with optional country code or trunk 8, e.g.
+	// +7 (912) 345-67-89. Digits around it must not continue number, so dates & longer numbers are kept
+	phoneRe = regexp.MustCompile(
+		`(^|[^\d+])((?:(?:\+\d{1,3}|8)[ -]?)?(?:\(\d{3}\)|\d{3})[ -]?\d{3}[ -]?\d{2}[ -]?\d{2})(\D|$)`,
+	)
+)
+
+// pseudonym is stable ID of entity that can not be reversed without key
+func pseudonym(key []byte, kind string, id uint) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s:%d", kind, id)
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// scrubber replaces names of persons & pet in free text
+type scrubber struct {
+	names *regexp.Regexp
+}
+
+func newScrubber(names ...string) scrubber {
+	var terms []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		terms = append(terms, regexp.QuoteMeta(name))
+		for _, part := range strings.Fields(name) {
+			if utf8.RuneCountInString(part) >= minNamePartLen {
+				terms = append(terms, regexp.QuoteMeta(part))
+			}
+		}
+	}
+	if len(terms) == 0 {
+		return scrubber{}
+	}
+
+	// full names go first so they are replaced as whole
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	return scrubber{names: regexp.MustCompile(
+		`(?i)(^|[^\p{L}\p{N}])(` + strings.Join(terms, "|") + `)([^\p{L}\p{N}]|$)`,
+	)}
+}
+
+func (s scrubber) scrub(text string) string {
+	text = emailRe.ReplaceAllString(text, "[EMAIL]")
+	text = replaceDelimited(phoneRe, text, "[PHONE]")
+	if s.names == nil {
+		return text
+	}
+	return replaceDelimited(s.names, text, "[NAME]")
+}
+
+// replaceDelimited replaces 2nd group of re, 1st & 3rd groups are delimiters kept around it. Go regexp has no
+// lookaround, so match consumes delimiter after it & next match that needs it as delimiter before is skipped,
+// e.g. "Ivan" of "Petrov Ivan" when parts are scrubbed. Skipped match always follows replaced one, so second
+// pass finds it next to replacement & there is no third
+func replaceDelimited(re *regexp.Regexp, text, replacement string) string {
+	for i := 0; i < 2; i++ {
+		text = re.ReplaceAllString(text, "${1}"+replacement+"${3}")
+	}
+	return text
+}
+
+func entryTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", models.DateLayout} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// dayOffset is number of calendar days from first to t
+func dayOffset(first, t time.Time) string {
+	day := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) }
+	return strconv.Itoa(int(day(t).Sub(day(first)).Hours() / 24))
+}
+
+// exportResearch writes zip with anonymized pets, their entries & manifest. Entries are spooled
+// to temp file because zip members are written one after another
+func (s *ExportService) exportResearch(job *models.ExportJob) (err error) {
+	file, err := os.CreateTemp(s.cfg.Dir, fmt.Sprintf("research-%d-*.zip", job.ID))
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close export file: %w", closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(file.Name())
+			return
+		}
+		job.FilePath = file.Name()
+	}()
+
+	petsFile, entriesFile := petsTable+fileExt(job.Format), entriesTable+fileExt(job.Format)
+	spool, err := os.CreateTemp(s.cfg.Dir, "entries-*"+fileExt(job.Format))
+	if err != nil {
+		return fmt.Errorf("failed to create entries file: %w", err)
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	archive := zip.NewWriter(file)
+	petsWriter, err := archive.Create(petsFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", petsFile, err)
+	}
+
+	if err := s.writeResearchRows(job, petsWriter, spool); err != nil {
+		return err
+	}
+
+	entriesWriter, err := archive.Create(entriesFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", entriesFile, err)
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind entries file: %w", err)
+	}
+	if _, err := io.Copy(entriesWriter, bufio.NewReader(spool)); err != nil {
+		return fmt.Errorf("failed to copy entries: %w", err)
+	}
+
+	manifestWriter, err := archive.Create(manifestFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", manifestFile, err)
+	}
+	manifest := models.ExportManifest{
+		JobID:         job.ID,
+		Type:          job.Type,
+		GeneratedAt:   time.Now().UTC(),
+		Criteria:      job.Criteria,
+		Anonymization: anonymization,
+		Files: []models.ExportFile{
+			{Name: petsFile, Rows: job.ExportedPets, Fields: petFields},
+			{Name: entriesFile, Rows: job.ExportedEntries, Fields: entryFields},
+		},
+		DroppedColumns: droppedColumns,
+	}
+	encoder := json.NewEncoder(manifestWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return archive.Close()
+}
+
+func (s *ExportService) writeResearchRows(job *models.ExportJob, petsOut, entriesOut io.Writer) error {
+	key := []byte(s.cfg.PseudonymKey)
+	pets, err := newTableWriter(job.Format, petsOut, fieldNames(petFields))
+	if err != nil {
+		return fmt.Errorf("failed to write pets header: %w", err)
+	}
+	entries, err := newTableWriter(job.Format, entriesOut, fieldNames(entryFields))
+	if err != nil {
+		return fmt.Errorf("failed to write entries header: %w", err)
+	}
+
+	petFilter := job.Criteria.Pets
+	petFilter.ResearchStatuses = models.ConsentedResearchStatuses
+	found, err := s.storage.GetPetsWithOwnerAndVet(petFilter)
+	if err != nil {
+		return err
+	}
+
+	vets := map[uint]string{}
+	vetName := func(id uint) (string, error) {
+		if name, ok := vets[id]; ok {
+			return name, nil
+		}
+		vet, err := s.storage.GetVet(id)
+		if err != nil {
+			return "", err
+		}
+		vets[id] = vet.FullName
+		return vet.FullName, nil
+	}
+
+	for _, item := range found {
+		pet := item.Pet
+		owner, err := s.storage.GetOwner(models.Owner{ID: item.OwnerID})
+		if err != nil {
+			return err
+		}
+
+		filter := job.Criteria.Entries
+		filter.PetID = &pet.ID
+		petEntries, err := s.storage.GetMedEntries(filter)
+		if err != nil {
+			return err
+		}
+
+		names := []string{pet.Name, owner.FullName}
+		for _, vetID := range append([]uint{item.VetID}, entryVets(petEntries)...) {
+			name, err := vetName(vetID)
+			if err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		scrub := newScrubber(names...).scrub
+
+		var (
+			first    time.Time
+			hasFirst bool
+		)
+		for _, entry := range petEntries {
+			if t, ok := entryTime(entry.EntryDate); ok && (!hasFirst || t.Before(first)) {
+				first, hasFirst = t, true
+			}
+		}
+
+		petPID := pseudonym(key, "pet", pet.ID)
+		birthOffset := ""
+		if birth, err := time.Parse(models.DateLayout, pet.BirthDate); err == nil && hasFirst {
+			birthOffset = dayOffset(first, birth)
+		}
+		err = pets.Write([]string{
+			petPID,
+			pseudonym(key, "owner", item.OwnerID),
+			pseudonym(key, "vet", item.VetID),
+			pet.AnimalType,
+			pet.Breed,
+			pet.Gender,
+			strconv.FormatFloat(pet.Weight, 'f', -1, 64),
+			birthOffset,
+			strconv.FormatBool(pet.BirthDateEstimated),
+			scrub(pet.Condition),
+			scrub(pet.Behavior),
+			pet.ResearchStatus,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write pet: %w", err)
+		}
+
+		for _, entry := range petEntries {
+			offset := ""
+			if t, ok := entryTime(entry.EntryDate); ok && hasFirst {
+				offset = dayOffset(first, t)
+			}
+			err := entries.Write([]string{
+				pseudonym(key, "entry", entry.ID),
+				petPID,
+				pseudonym(key, "vet", entry.VetID),
+				offset,
+				scrub(entry.Description),
+				scrub(entry.Disease),
+				scrub(entry.Vaccinations),
+				scrub(entry.Recommendation),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to write entry: %w", err)
+			}
+		}
+
+		job.ExportedPets++
+		job.ExportedEntries += uint(len(petEntries))
+		if job.ExportedPets%progressPets == 0 {
+			if err := s.exports.UpdateExportJob(*job); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := pets.Close(); err != nil {
+		return fmt.Errorf("failed to write pets: %w", err)
+	}
+	if err := entries.Close(); err != nil {
+		return fmt.Errorf("failed to write entries: %w", err)
+	}
+	return nil
+}
+
+// entryVets returns distinct vets of entries
+func entryVets(entries []models.MedicalEntry) []uint {
+	seen := map[uint]bool{}
+	var ids []uint
+	for _, entry := range entries {
+		if entry.VetID != 0 && !seen[entry.VetID] {
+			seen[entry.VetID] = true
+			ids = append(ids, entry.VetID)
+		}
+	}
+	return ids
+}
+
+func fieldNames(fields []models.ExportField) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
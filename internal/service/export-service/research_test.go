@@ -0,0 +1,109 @@
+package exportservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScrub(t *testing.T) {
+	// pet, owner & vets of record
+	scrub := newScrubber("Барсик", "Иванова Мария Петровна", "John Smith", "Al").scrub
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"full name", "Owner John Smith called", "Owner [NAME] called"},
+		{"case of name", "JOHN SMITH called", "[NAME] called"},
+		{"part of name", "Smith called, John came", "[NAME] called, [NAME] came"},
+		{"adjacent parts", "Smith John, Smith", "[NAME] [NAME], [NAME]"},
+		{"three adjacent parts", "smith john smith", "[NAME] [NAME] [NAME]"},
+		{"cyrillic pet", "Барсик ест плохо", "[NAME] ест плохо"},
+		{"cyrillic case", "БАРСИК и ИВАНОВА", "[NAME] и [NAME]"},
+		{"inflected name is kept", "у Барсика аллергия", "у Барсика аллергия"},
+		{"cyrillic full name", "владелец Иванова Мария Петровна", "владелец [NAME]"},
+		{"cyrillic adjacent parts", "Мария Иванова,Петровна", "[NAME] [NAME],[NAME]"},
+		{"part inside word", "Johnson & Smithers", "Johnson & Smithers"},
+		{"short part", "Al called", "[NAME] called"},
+		{"email", "write to j.smith+vet@mail.example.ru", "write to [EMAIL]"},
+		{"cyrillic email", "почта маша@почта.рф", "почта [EMAIL]"},
+		{"phone", "call +7 (912) 345-67-89 today", "call [PHONE] today"},
+		{"phone without spaces", "tel:+79123456789", "tel:[PHONE]"},
+		{"trunk phone", "8 912 345 67 89", "[PHONE]"},
+		{"local phone", "912-345-67-89, 912 345 67 88", "[PHONE], [PHONE]"},
+		{"iso date", "seen 2026-01-15", "seen 2026-01-15"},
+		{"date time", "2026-01-15 10:30:00", "2026-01-15 10:30:00"},
+		{"dotted date", "15.01.2026", "15.01.2026"},
+		{"microchip", "chip 643094100123456", "chip 643094100123456"},
+		{"dose", "give 250 mg 2 times", "give 250 mg 2 times"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrub(tt.text); got != tt.want {
+				t.Errorf("scrub(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrubWithoutNames(t *testing.T) {
+	scrub := newScrubber("", "  ").scrub
+	if got, want := scrub("John, +7 912 345 67 89"), "John, [PHONE]"; got != want {
+		t.Errorf("scrub = %q, want %q", got, want)
+	}
+}
+
+func TestPseudonym(t *testing.T) {
+	key := []byte("key")
+
+	got := pseudonym(key, "pet", 42)
+	if len(got) != 16 {
+		t.Errorf("pseudonym %q has %d digits, want 16", got, len(got))
+	}
+	if again := pseudonym(key, "pet", 42); again != got {
+		t.Errorf("pseudonym is not stable: %q and %q", got, again)
+	}
+
+	others := map[string]string{
+		"other id":   pseudonym(key, "pet", 43),
+		"other kind": pseudonym(key, "owner", 42),
+		"other key":  pseudonym([]byte("other"), "pet", 42),
+	}
+	for name, other := range others {
+		if other == got {
+			t.Errorf("%s has same pseudonym %q", name, got)
+		}
+	}
+}
+
+func TestDayOffset(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		first time.Time
+		t     time.Time
+		want  string
+	}{
+		{"same day", time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC), time.Date(2026, 1, 15, 23, 59, 0, 0, time.UTC), "0"},
+		{"next day in less than 24h", time.Date(2026, 1, 15, 23, 0, 0, 0, time.UTC),
+			time.Date(2026, 1, 16, 1, 0, 0, 0, time.UTC), "1"},
+		{"month end", time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), "1"},
+		{"leap year", time.Date(2028, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2028, 3, 1, 0, 0, 0, 0, time.UTC), "2"},
+		{"before first", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), "-365"},
+		{"dst start", time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), time.Date(2026, 3, 9, 0, 30, 0, 0, newYork), "2"},
+		{"dst end", time.Date(2026, 10, 31, 23, 30, 0, 0, newYork), time.Date(2026, 11, 1, 23, 30, 0, 0, newYork), "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dayOffset(tt.first, tt.t); got != tt.want {
+				t.Errorf("dayOffset = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
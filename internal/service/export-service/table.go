@@ -0,0 +1,93 @@
+package exportservice
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// rowGroupSize bounds bytes parquet writer keeps in memory before they are written as row group
+const rowGroupSize = 8 << 20
+
+// tableWriter writes rows of string columns in format of export
+type tableWriter interface {
+	Write(record []string) error
+	// Close flushes buffered rows, underlying writer is left open
+	Close() error
+}
+
+// newTableWriter writes header of columns in csv, parquet files have optional string column of each name
+func newTableWriter(format string, w io.Writer, columns []string) (tableWriter, error) {
+	switch format {
+	case models.ExportFormatCSV:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(columns); err != nil {
+			return nil, fmt.Errorf("failed to write header: %w", err)
+		}
+		return csvTable{writer: csvWriter}, nil
+	case models.ExportFormatParquet:
+		return newParquetTable(w, columns)
+	default:
+		return nil, fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+// fileExt is extension of files written by table writer of format
+func fileExt(format string) string {
+	if format == models.ExportFormatParquet {
+		return ".parquet"
+	}
+	return ".csv"
+}
+
+type csvTable struct {
+	writer *csv.Writer
+}
+
+func (t csvTable) Write(record []string) error {
+	return t.writer.Write(record)
+}
+
+func (t csvTable) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// parquetTable writes optional UTF-8 columns, empty values are written as nulls
+type parquetTable struct {
+	writer *writer.CSVWriter
+	values []*string
+}
+
+func newParquetTable(w io.Writer, columns []string) (*parquetTable, error) {
+	metadata := make([]string, len(columns))
+	for i, column := range columns {
+		metadata[i] = fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL", column)
+	}
+	parquetWriter, err := writer.NewCSVWriterFromWriter(metadata, w, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	parquetWriter.RowGroupSize = rowGroupSize
+	return &parquetTable{writer: parquetWriter, values: make([]*string, len(columns))}, nil
+}
+
+func (t *parquetTable) Write(record []string) error {
+	if len(record) != len(t.values) {
+		return fmt.Errorf("record has %d values, want %d", len(record), len(t.values))
+	}
+	for i := range record {
+		t.values[i] = nil
+		if record[i] != "" {
+			t.values[i] = &record[i]
+		}
+	}
+	return t.writer.WriteString(t.values)
+}
+
+func (t *parquetTable) Close() error {
+	return t.writer.WriteStop()
+}
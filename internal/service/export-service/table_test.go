@@ -0,0 +1,91 @@
+package exportservice
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+var tableColumns = []string{"pet_pid", "species", "condition"}
+
+var tableRows = [][]string{
+	{"a1", "cat", "Кашель, \"сухой\""},
+	{"b2", "", "fine"},
+}
+
+func writeTable(t *testing.T, format string) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	writer, err := newTableWriter(format, &out, tableColumns)
+	if err != nil {
+		t.Fatalf("newTableWriter: %v", err)
+	}
+	for _, row := range tableRows {
+		if err := writer.Write(row); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestCSVTable(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(writeTable(t, models.ExportFormatCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	want := append([][]string{tableColumns}, tableRows...)
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("csv = %q, want %q", records, want)
+	}
+}
+
+func TestParquetTable(t *testing.T) {
+	file, err := buffer.NewBufferFile(writeTable(t, models.ExportFormatParquet))
+	if err != nil {
+		t.Fatalf("NewBufferFile: %v", err)
+	}
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		t.Fatalf("NewParquetColumnReader: %v", err)
+	}
+	defer pr.ReadStop()
+
+	if rows := pr.GetNumRows(); rows != int64(len(tableRows)) {
+		t.Fatalf("rows = %d, want %d", rows, len(tableRows))
+	}
+	// reader renames columns to Go names, file keeps names of header in its order
+	for i, column := range tableColumns {
+		if name := pr.SchemaHandler.Infos[i+1].ExName; name != column {
+			t.Errorf("column %d = %s, want %s", i, name, column)
+		}
+
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), int64(len(tableRows)))
+		if err != nil {
+			t.Fatalf("read %s: %v", column, err)
+		}
+		for j, row := range tableRows {
+			// empty values are nulls
+			var want interface{}
+			if row[i] != "" {
+				want = row[i]
+			}
+			if values[j] != want {
+				t.Errorf("%s of row %d = %v, want %v", column, j, values[j], want)
+			}
+		}
+	}
+}
+
+func TestNewTableWriterUnknownFormat(t *testing.T) {
+	if _, err := newTableWriter("xlsx", &bytes.Buffer{}, tableColumns); err == nil {
+		t.Error("want error for unknown format")
+	}
+}
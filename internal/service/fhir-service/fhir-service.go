@@ -0,0 +1,125 @@
+package fhirservice
+
+import (
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+type petRecords interface {
+	GetPetRecord(petID uint) (models.PetRecord, error)
+}
+
+type vets interface {
+	GetVet(id uint) (models.Vet, error)
+}
+
+type FHIRService struct {
+	log     *logging.Logger
+	records petRecords
+	vets    vets
+	baseURL string
+}
+
+func New(log *logging.Logger, records petRecords, vets vets, cfg config.FHIRConfig) *FHIRService {
+	return &FHIRService{log: log, records: records, vets: vets, baseURL: cfg.BaseURL}
+}
+
+// Patient returns pet as FHIR Patient with animal extension
+func (s *FHIRService) Patient(petID uint) (models.FHIRPatient, error) {
+	record, err := s.records.GetPetRecord(petID)
+	if err != nil {
+		return models.FHIRPatient{}, err
+	}
+	return toPatient(record), nil
+}
+
+// PatientEverything returns Patient with owner, vets of card & entries and all med entries as searchset
+// Bundle. Configured base URL or requestURL of this request is used to build absolute fullUrl of entries,
+// e.g. https://host/info/v1/fhir
+func (s *FHIRService) PatientEverything(petID uint, requestURL string) (models.FHIRBundle, error) {
+	record, err := s.records.GetPetRecord(petID)
+	if err != nil {
+		return models.FHIRBundle{}, err
+	}
+	vets, err := s.entryVets(record)
+	if err != nil {
+		return models.FHIRBundle{}, err
+	}
+
+	baseURL := s.baseURL
+	if baseURL == "" {
+		baseURL = requestURL
+	}
+
+	bundle := models.FHIRBundle{
+		ResourceType: "Bundle",
+		Type:         models.FHIRBundleTypeSearchset,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+	}
+	add := func(resourceType, id string, resource interface{}, mode string) {
+		bundle.Entry = append(bundle.Entry, models.FHIRBundleEntry{
+			FullURL:  baseURL + "/" + resourceType + "/" + id,
+			Resource: resource,
+			Search:   &models.FHIRBundleSearch{Mode: mode},
+		})
+	}
+
+	patient := toPatient(record)
+	add(patient.ResourceType, patient.ID, patient, models.FHIRSearchModeMatch)
+
+	owner := toRelatedPerson(record)
+	add(owner.ResourceType, owner.ID, owner, models.FHIRSearchModeInclude)
+
+	vet := toPractitioner(record.Vet)
+	add(vet.ResourceType, vet.ID, vet, models.FHIRSearchModeInclude)
+	for _, entryVet := range vets {
+		if entryVet.ID != record.Vet.ID {
+			practitioner := toPractitioner(entryVet)
+			add(practitioner.ResourceType, practitioner.ID, practitioner, models.FHIRSearchModeInclude)
+		}
+	}
+
+	for _, entry := range record.Entries {
+		encounter := toEncounter(record, entry, vets)
+		add(encounter.ResourceType, encounter.ID, encounter, models.FHIRSearchModeInclude)
+
+		if condition, ok := toCondition(record, entry); ok {
+			add(condition.ResourceType, condition.ID, condition, models.FHIRSearchModeInclude)
+		}
+		if immunization, ok := toImmunization(record, entry); ok {
+			add(immunization.ResourceType, immunization.ID, immunization, models.FHIRSearchModeInclude)
+		}
+		if carePlan, ok := toCarePlan(record, entry); ok {
+			add(carePlan.ResourceType, carePlan.ID, carePlan, models.FHIRSearchModeInclude)
+		}
+	}
+
+	// total counts matches only, included resources are not counted
+	bundle.Total = 1
+	return bundle, nil
+}
+
+// entryVets returns vets who made entries of record in order of their first entry
+func (s *FHIRService) entryVets(record models.PetRecord) ([]models.Vet, error) {
+	var vets []models.Vet
+	seen := map[uint]bool{}
+	for _, entry := range record.Entries {
+		if entry.VetID == 0 || seen[entry.VetID] {
+			continue
+		}
+		seen[entry.VetID] = true
+
+		vet := record.Vet
+		if entry.VetID != record.Vet.ID {
+			var err error
+			if vet, err = s.vets.GetVet(entry.VetID); err != nil {
+				return nil, err
+			}
+		}
+		vets = append(vets, vet)
+	}
+	return vets, nil
+}
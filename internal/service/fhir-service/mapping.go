@@ -0,0 +1,193 @@
+package fhirservice
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+const (
+	animalExtensionURL = "http://hl7.org/fhir/StructureDefinition/patient-animal"
+	actCodeSystem      = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+	roleCodeSystem     = "http://terminology.hl7.org/CodeSystem/v3-RoleCode"
+)
+
+var genders = map[string]string{
+	validation.GenderMale:   "male",
+	validation.GenderFemale: "female",
+}
+
+func id(value uint) string {
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+func reference(resourceType string, value uint, display string) models.FHIRReference {
+	return models.FHIRReference{Reference: resourceType + "/" + id(value), Display: display}
+}
+
+func patientRef(pet models.Pet) models.FHIRReference {
+	return reference("Patient", pet.ID, pet.Name)
+}
+
+func encounterRef(entry models.MedicalEntry) *models.FHIRReference {
+	ref := reference("Encounter", entry.ID, "")
+	return &ref
+}
+
+func telecom(email, phone string) []models.FHIRContactPoint {
+	var points []models.FHIRContactPoint
+	if phone != "" {
+		points = append(points, models.FHIRContactPoint{System: "phone", Value: phone})
+	}
+	if email != "" {
+		points = append(points, models.FHIRContactPoint{System: "email", Value: email})
+	}
+	return points
+}
+
+func names(name string) []models.FHIRHumanName {
+	if name == "" {
+		return nil
+	}
+	return []models.FHIRHumanName{{Text: name}}
+}
+
+// dateTime converts stored timestamp to FHIR dateTime. Unknown formats are passed as is
+func dateTime(value string) string {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format(time.RFC3339)
+		}
+	}
+	return value
+}
+
+func toPatient(record models.PetRecord) models.FHIRPatient {
+	pet := record.Pet
+	gender, ok := genders[pet.Gender]
+	if !ok {
+		gender = "unknown"
+	}
+
+	patient := models.FHIRPatient{
+		ResourceType: "Patient",
+		ID:           id(pet.ID),
+		Active:       true,
+		Name:         names(pet.Name),
+		Gender:       gender,
+		BirthDate:    pet.BirthDate,
+	}
+	if pet.AnimalType != "" {
+		patient.Extension = []models.FHIRExtension{{
+			URL: animalExtensionURL,
+			Extension: []models.FHIRExtension{{
+				URL:                  "species",
+				ValueCodeableConcept: &models.FHIRCodeableConcept{Text: pet.AnimalType},
+			}},
+		}}
+	}
+	if record.Vet.ID != 0 {
+		patient.GeneralPractitioner = []models.FHIRReference{reference("Practitioner", record.Vet.ID, record.Vet.FullName)}
+	}
+	return patient
+}
+
+func toRelatedPerson(record models.PetRecord) models.FHIRRelatedPerson {
+	owner := record.Owner
+	return models.FHIRRelatedPerson{
+		ResourceType: "RelatedPerson",
+		ID:           id(owner.ID),
+		Patient:      patientRef(record.Pet),
+		Relationship: []models.FHIRCodeableConcept{{
+			Coding: []models.FHIRCoding{{System: roleCodeSystem, Code: "O", Display: "owner"}},
+			Text:   "owner",
+		}},
+		Name:    names(owner.FullName),
+		Telecom: telecom(owner.Email, owner.Phone),
+	}
+}
+
+func toPractitioner(vet models.Vet) models.FHIRPractitioner {
+	return models.FHIRPractitioner{
+		ResourceType: "Practitioner",
+		ID:           id(vet.ID),
+		Name:         names(vet.FullName),
+		Telecom:      telecom(vet.Email, vet.Phone),
+	}
+}
+
+// toEncounter maps med entry, its participant is vet who made entry
+func toEncounter(record models.PetRecord, entry models.MedicalEntry, vets []models.Vet) models.FHIREncounter {
+	encounter := models.FHIREncounter{
+		ResourceType: "Encounter",
+		ID:           id(entry.ID),
+		Status:       "finished",
+		Class:        models.FHIRCoding{System: actCodeSystem, Code: "AMB", Display: "ambulatory"},
+		Subject:      patientRef(record.Pet),
+	}
+	if entry.EntryDate != "" {
+		encounter.Period = &models.FHIRPeriod{Start: dateTime(entry.EntryDate)}
+	}
+	for _, vet := range vets {
+		if vet.ID == entry.VetID {
+			encounter.Participant = []models.FHIREncounterParticipant{{
+				Individual: reference("Practitioner", vet.ID, vet.FullName),
+			}}
+		}
+	}
+	if description := strings.TrimSpace(entry.Description); description != "" {
+		encounter.ReasonCode = []models.FHIRCodeableConcept{{Text: description}}
+	}
+	return encounter
+}
+
+func toCondition(record models.PetRecord, entry models.MedicalEntry) (models.FHIRCondition, bool) {
+	disease := strings.TrimSpace(entry.Disease)
+	if disease == "" {
+		return models.FHIRCondition{}, false
+	}
+	return models.FHIRCondition{
+		ResourceType: "Condition",
+		ID:           id(entry.ID),
+		Code:         models.FHIRCodeableConcept{Text: disease},
+		Subject:      patientRef(record.Pet),
+		Encounter:    encounterRef(entry),
+		RecordedDate: dateTime(entry.EntryDate),
+	}, true
+}
+
+func toImmunization(record models.PetRecord, entry models.MedicalEntry) (models.FHIRImmunization, bool) {
+	vaccinations := strings.TrimSpace(entry.Vaccinations)
+	if vaccinations == "" {
+		return models.FHIRImmunization{}, false
+	}
+	return models.FHIRImmunization{
+		ResourceType:       "Immunization",
+		ID:                 id(entry.ID),
+		Status:             "completed",
+		VaccineCode:        models.FHIRCodeableConcept{Text: vaccinations},
+		Patient:            patientRef(record.Pet),
+		Encounter:          encounterRef(entry),
+		OccurrenceDateTime: dateTime(entry.EntryDate),
+	}, true
+}
+
+func toCarePlan(record models.PetRecord, entry models.MedicalEntry) (models.FHIRCarePlan, bool) {
+	recommendation := strings.TrimSpace(entry.Recommendation)
+	if recommendation == "" {
+		return models.FHIRCarePlan{}, false
+	}
+	return models.FHIRCarePlan{
+		ResourceType: "CarePlan",
+		ID:           id(entry.ID),
+		Status:       "active",
+		Intent:       "plan",
+		Description:  recommendation,
+		Subject:      patientRef(record.Pet),
+		Encounter:    encounterRef(entry),
+		Created:      dateTime(entry.EntryDate),
+	}, true
+}
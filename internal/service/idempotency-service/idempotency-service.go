@@ -0,0 +1,90 @@
+package idempotencyservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	schedulerservice "github.com/vet-clinic-back/info-service/internal/service/scheduler-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// inProgressLease is how long key of running request is reserved. Key of request that crashed before it finished
+// is taken over by retry after lease ends. Lease is longer than any request may run with server timeouts
+const inProgressLease = time.Minute
+
+type IdempotencyService struct {
+	log     *logging.Logger
+	storage storage.Idempotency
+	ttl     time.Duration
+}
+
+func New(log *logging.Logger, storage storage.Idempotency, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{log: log, storage: storage, ttl: ttl}
+}
+
+// Start reserves key of scope for request with requestHash. If key was already used returns saved record and true.
+// Reuse with another request is errs.ErrKeyReused, reuse while first request is running is errs.ErrConflict
+func (s *IdempotencyService) Start(scope, key, requestHash string) (models.IdempotencyRecord, bool, error) {
+	now := time.Now()
+	reserved, err := s.storage.ReserveIdempotencyKey(models.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		LockedUntil: now.Add(inProgressLease),
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+	if reserved {
+		return models.IdempotencyRecord{}, false, nil
+	}
+
+	rec, err := s.storage.GetIdempotencyKey(scope, key)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			// key was deleted by failed request right now
+			return models.IdempotencyRecord{}, false, errs.Conflict("request with same idempotency key is in progress", err)
+		}
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	if rec.RequestHash != requestHash {
+		return models.IdempotencyRecord{}, false, errs.KeyReused("idempotency key is already used with another request", nil)
+	}
+	if rec.ResponseStatus == 0 {
+		return models.IdempotencyRecord{}, false, errs.Conflict("request with same idempotency key is in progress", nil)
+	}
+
+	return rec, true, nil
+}
+
+// Finish saves response to replay it on retries
+func (s *IdempotencyService) Finish(rec models.IdempotencyRecord) error {
+	return s.storage.SaveIdempotencyResponse(rec)
+}
+
+// Abort releases key so failed request can be retried
+func (s *IdempotencyService) Abort(scope, key string) error {
+	return s.storage.DeleteIdempotencyKey(scope, key)
+}
+
+// Jobs returns purge of expired keys
+func (s *IdempotencyService) Jobs() []schedulerservice.Job {
+	return []schedulerservice.Job{{
+		Name:        "idempotency.purge",
+		Description: "Deletes expired idempotency keys",
+		Spec:        "@hourly",
+		Run: func(context.Context) error {
+			deleted, err := s.storage.DeleteExpiredIdempotencyKeys()
+			if err == nil && deleted > 0 {
+				s.log.WithField("op", "IdempotencyService.purge").Info("deleted expired keys: ", deleted)
+			}
+			return err
+		},
+	}}
+}
@@ -0,0 +1,81 @@
+package importservice
+
+import (
+	"errors"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// dryRun counts what import would create using read only lookups
+type dryRun struct {
+	owners map[string]bool
+	pets   map[string]bool
+}
+
+func newDryRun() *dryRun {
+	return &dryRun{owners: map[string]bool{}, pets: map[string]bool{}}
+}
+
+func (d *dryRun) check(stor storage.Info, row importRow, report *models.ImportReport) error {
+	keys := ownerKeys(row.Owner)
+
+	seen := false
+	for _, key := range keys {
+		seen = seen || d.owners[key]
+	}
+	if !seen {
+		exists, err := ownerExists(stor, row.Owner)
+		if err != nil {
+			return err
+		}
+		if exists {
+			report.ReusedOwners++
+		} else {
+			report.CreatedOwners++
+		}
+	} else {
+		report.ReusedOwners++
+	}
+	for _, key := range keys {
+		d.owners[key] = true
+	}
+
+	if !d.pets[row.petKey()] {
+		d.pets[row.petKey()] = true
+		report.CreatedPets++
+	}
+	if row.hasEntry() {
+		report.CreatedEntries++
+	}
+
+	return nil
+}
+
+func ownerKeys(owner models.Owner) []string {
+	var keys []string
+	if owner.Email != "" {
+		keys = append(keys, "email:"+owner.Email)
+	}
+	if owner.Phone != "" {
+		keys = append(keys, "phone:"+owner.Phone)
+	}
+	return keys
+}
+
+func ownerExists(stor storage.Info, owner models.Owner) (bool, error) {
+	for _, unique := range []models.Owner{{Email: owner.Email}, {Phone: owner.Phone}} {
+		if unique.Email == "" && unique.Phone == "" {
+			continue
+		}
+		_, err := stor.GetOwner(unique)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, errs.ErrNotFound) {
+			return false, err
+		}
+	}
+	return false, nil
+}
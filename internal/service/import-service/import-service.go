@@ -0,0 +1,392 @@
+package importservice
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	researchservice "github.com/vet-clinic-back/info-service/internal/service/research-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+const (
+	// maxReportErrors limits size of report, counters are still correct
+	maxReportErrors = 1000
+	// dryRunProgressRows is how often dry run saves progress
+	dryRunProgressRows = 100
+	// importedPasswordHash can not match any password. Imported owners set password on first login
+	importedPasswordHash = "!imported"
+	// staleRunningAfter is how long running job may go without checkpoint before it is considered abandoned
+	// by crashed worker & can be claimed again
+	staleRunningAfter = 10 * time.Minute
+)
+
+type ImportService struct {
+	log     *logging.Logger
+	storage storage.Info
+	imports storage.Import
+	tx      storage.Transactor
+	dir     string
+}
+
+func New(
+	log *logging.Logger, storage storage.Info, imports storage.Import, tx storage.Transactor, dir string,
+) *ImportService {
+	return &ImportService{log: log, storage: storage, imports: imports, tx: tx, dir: dir}
+}
+
+// CreateImport saves source to import dir and runs job in background
+func (s *ImportService) CreateImport(job models.ImportJob, src io.Reader) (models.ImportJob, error) {
+	if err := validateJob(job); err != nil {
+		return models.ImportJob{}, err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to create import dir: %w", err)
+	}
+	file, err := os.CreateTemp(s.dir, "import-*."+job.Format)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to create import file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, src); err != nil {
+		_ = os.Remove(file.Name())
+		return models.ImportJob{}, fmt.Errorf("failed to save import file: %w", err)
+	}
+
+	job.FilePath = file.Name()
+	job, err = s.createJob(job)
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return models.ImportJob{}, err
+	}
+
+	go s.runInBackground(job.ID)
+
+	return job, nil
+}
+
+// CreateLocalImport creates job for file that is already on disk. Used by import command
+func (s *ImportService) CreateLocalImport(job models.ImportJob) (models.ImportJob, error) {
+	if err := validateJob(job); err != nil {
+		return models.ImportJob{}, err
+	}
+
+	path, err := filepath.Abs(job.FilePath)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to get import file path: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return models.ImportJob{}, errs.Validation("import file is not readable", err)
+	}
+
+	job.FilePath = path
+	return s.createJob(job)
+}
+
+func (s *ImportService) GetImport(id uint) (models.ImportJob, error) {
+	return s.imports.GetImportJob(id)
+}
+
+// ResumeImport continues failed or abandoned running job from checkpoint in background
+func (s *ImportService) ResumeImport(id uint) (models.ImportJob, error) {
+	job, err := s.imports.ClaimImportJob(id, []string{models.ImportStatusFailed}, staleRunningAfter)
+	if err != nil {
+		if errors.Is(err, errs.ErrConflict) {
+			return models.ImportJob{}, errs.Conflict(
+				"only failed or abandoned running import can be resumed, "+errs.Detail(err), err)
+		}
+		return models.ImportJob{}, err
+	}
+
+	go func() {
+		if _, err := s.run(job); err != nil {
+			s.log.WithField("op", "ImportService.ResumeImport").
+				WithField("job_id", id).Error("import failed: ", err.Error())
+		}
+	}()
+
+	return job, nil
+}
+
+func (s *ImportService) createJob(job models.ImportJob) (models.ImportJob, error) {
+	job.Status = models.ImportStatusPending
+	job.ProcessedRows = 0
+	job.Report = models.ImportReport{}
+
+	id, err := s.imports.CreateImportJob(job)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	return s.imports.GetImportJob(id)
+}
+
+func (s *ImportService) runInBackground(id uint) {
+	if _, err := s.RunImport(id); err != nil {
+		s.log.WithField("op", "ImportService.runInBackground").
+			WithField("job_id", id).Error("import failed: ", err.Error())
+	}
+}
+
+// RunImport claims pending, failed or abandoned job and processes it synchronously starting after checkpoint.
+// Invalid rows are reported & skipped, storage failure stops job with failed status so it can be resumed
+func (s *ImportService) RunImport(id uint) (models.ImportJob, error) {
+	job, err := s.imports.GetImportJob(id)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+	if job.Status == models.ImportStatusCompleted {
+		return job, nil
+	}
+
+	job, err = s.imports.ClaimImportJob(
+		id, []string{models.ImportStatusPending, models.ImportStatusFailed}, staleRunningAfter,
+	)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+	return s.run(job)
+}
+
+// run processes claimed job
+func (s *ImportService) run(job models.ImportJob) (models.ImportJob, error) {
+	log := s.log.WithField("op", "ImportService.run").WithField("job_id", job.ID).WithField("attempt", job.Attempt)
+
+	if job.DryRun {
+		// dry run has no side effects, so it always starts from the beginning
+		job.ProcessedRows = 0
+		job.Report = models.ImportReport{}
+	}
+
+	log.Info("import started from row ", job.ProcessedRows+1)
+
+	if err := s.process(&job); err != nil {
+		job.Status = models.ImportStatusFailed
+		job.Error = err.Error()
+		if updateErr := s.imports.UpdateImportJob(job); updateErr != nil {
+			log.Error("failed to save failed status: ", updateErr.Error())
+		}
+		return job, err
+	}
+
+	job.Status = models.ImportStatusCompleted
+	if err := s.imports.UpdateImportJob(job); err != nil {
+		return job, err
+	}
+
+	log.WithField("report", job.Report).Info("import completed")
+	return job, nil
+}
+
+func (s *ImportService) process(job *models.ImportJob) error {
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := newRowReader(job.Format, file)
+	if err != nil {
+		return err
+	}
+
+	dry := newDryRun()
+	var rowNum uint
+	for {
+		source, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		rowNum++
+		if rowNum <= job.ProcessedRows {
+			continue
+		}
+
+		var row importRow
+		if err == nil {
+			row, err = mapRow(source, job.Mapping, job.DefaultVetID)
+		}
+		if err == nil {
+			err = validateRow(row)
+		}
+		if err == nil {
+			row.Pet, err = normalizeSpecies(s.storage, row.Pet)
+		}
+		if err != nil && !isRowError(err) {
+			return fmt.Errorf("failed to read row %d: %w", rowNum, err)
+		}
+
+		if job.DryRun {
+			if err == nil {
+				err = dry.check(s.storage, row, &job.Report)
+			}
+			if err != nil && !isRowError(err) {
+				return err
+			}
+			s.finishRow(job, rowNum, err)
+			if rowNum%dryRunProgressRows == 0 {
+				if err := s.imports.UpdateImportJob(*job); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err == nil {
+			err = s.importRow(job, rowNum, row)
+			if err == nil {
+				continue
+			}
+			if !isRowError(err) {
+				return err
+			}
+		}
+
+		s.finishRow(job, rowNum, err)
+		if err := s.imports.UpdateImportJob(*job); err != nil {
+			return err
+		}
+	}
+}
+
+// importRow writes row and checkpoint in one transaction
+func (s *ImportService) importRow(job *models.ImportJob, rowNum uint, row importRow) error {
+	updated := *job
+	updated.Report.Errors = append([]models.ImportRowError(nil), job.Report.Errors...)
+
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		ownerID, created, err := s.findOrCreateOwner(tx, row.Owner)
+		if err != nil {
+			return err
+		}
+		if created {
+			updated.Report.CreatedOwners++
+		} else {
+			updated.Report.ReusedOwners++
+		}
+
+		petID, err := tx.GetImportPetRef(job.ID, row.petKey())
+		if errors.Is(err, errs.ErrNotFound) {
+			petID, err = tx.CreatePetWithCard(row.Pet, ownerID, row.VetID)
+			if err != nil {
+				return err
+			}
+			if row.ResearchStatus != "" {
+				err := researchservice.ImportResearchStatus(tx, petID, row.ResearchStatus, row.VetID)
+				if err != nil {
+					return err
+				}
+			}
+			if err := tx.SaveImportPetRef(job.ID, row.petKey(), petID); err != nil {
+				return err
+			}
+			updated.Report.CreatedPets++
+		} else if err != nil {
+			return err
+		}
+
+		if row.hasEntry() {
+			record, err := tx.GetMedRecordByPet(petID)
+			if err != nil {
+				return err
+			}
+			entry := row.Entry
+			entry.MedicalRecordID = record.ID
+			if _, err := tx.CreateMedEntry(entry); err != nil {
+				return err
+			}
+			updated.Report.CreatedEntries++
+		}
+
+		s.finishRow(&updated, rowNum, nil)
+		return tx.UpdateImportJob(updated)
+	})
+	if err != nil {
+		return err
+	}
+
+	*job = updated
+	return nil
+}
+
+// findOrCreateOwner deduplicates owners by email, then by phone
+func (s *ImportService) findOrCreateOwner(tx storage.Tx, owner models.Owner) (uint, bool, error) {
+	for _, unique := range []models.Owner{{Email: owner.Email}, {Phone: owner.Phone}} {
+		if unique.Email == "" && unique.Phone == "" {
+			continue
+		}
+		existing, err := tx.GetOwner(unique)
+		if err == nil {
+			return existing.ID, false, nil
+		}
+		if !errors.Is(err, errs.ErrNotFound) {
+			return 0, false, err
+		}
+	}
+
+	owner.PasswordHash = importedPasswordHash
+	id, err := tx.CreateOwner(owner)
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+// finishRow moves checkpoint & adds row result to report
+func (s *ImportService) finishRow(job *models.ImportJob, rowNum uint, err error) {
+	job.ProcessedRows = rowNum
+	if err == nil {
+		job.Report.ValidRows++
+		return
+	}
+
+	job.Report.InvalidRows++
+	if len(job.Report.Errors) >= maxReportErrors {
+		return
+	}
+
+	rowErr := models.ImportRowError{Row: rowNum, Message: errs.Detail(err)}
+	if rowErr.Message == "" {
+		// csv parse errors contain only position & reason
+		rowErr.Message = err.Error()
+	}
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		rowErr.Message = "invalid row"
+		for _, fieldErr := range fieldErrs {
+			rowErr.Fields = append(rowErr.Fields, models.FieldErrorDTO{
+				Field:   fieldErr.Field,
+				Code:    fieldErr.Code,
+				Message: fieldErr.Message,
+			})
+		}
+	}
+	job.Report.Errors = append(job.Report.Errors, rowErr)
+}
+
+// isRowError reports whether error is caused by row data, such rows are skipped
+func isRowError(err error) bool {
+	var parseErr *csv.ParseError
+	return errors.As(err, &parseErr) ||
+		errors.Is(err, errs.ErrValidation) ||
+		errors.Is(err, errs.ErrForeignKey) ||
+		errors.Is(err, errs.ErrConflict)
+}
+
+func validateJob(job models.ImportJob) error {
+	if job.Format != models.ImportFormatCSV && job.Format != models.ImportFormatNDJSON {
+		return errs.Validation(fmt.Sprintf("format should be %s or %s",
+			models.ImportFormatCSV, models.ImportFormatNDJSON), nil)
+	}
+	return validateMapping(job.Mapping)
+}
@@ -0,0 +1,151 @@
+package importservice
+
+import (
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// fakeTx keeps one imported pet & its research history
+type fakeTx struct {
+	storage.Tx
+	pet         models.Pet
+	transitions []models.ResearchTransition
+}
+
+func (f *fakeTx) WithTx(fn func(tx storage.Tx) error) error {
+	return fn(f)
+}
+
+func (f *fakeTx) GetOwner(models.Owner) (models.Owner, error) {
+	return models.Owner{}, errs.NotFound("owner not found", nil)
+}
+
+func (f *fakeTx) CreateOwner(models.Owner) (uint, error) {
+	return 1, nil
+}
+
+func (f *fakeTx) GetImportPetRef(uint, string) (uint, error) {
+	return 0, errs.NotFound("pet ref not found", nil)
+}
+
+func (f *fakeTx) SaveImportPetRef(uint, string, uint) error {
+	return nil
+}
+
+func (f *fakeTx) CreatePetWithCard(pet models.Pet, _, _ uint) (uint, error) {
+	if pet.ResearchStatus == "" {
+		pet.ResearchStatus = models.ResearchStatusCandidate
+	}
+	pet.ID = 7
+	f.pet = pet
+	return pet.ID, nil
+}
+
+func (f *fakeTx) UpdateResearchStatus(_ uint, from, to string) error {
+	if f.pet.ResearchStatus != from {
+		return errs.Conflict("research status changed", nil)
+	}
+	f.pet.ResearchStatus = to
+	return nil
+}
+
+func (f *fakeTx) CreateResearchTransition(transition models.ResearchTransition) (uint, error) {
+	f.transitions = append(f.transitions, transition)
+	return uint(len(f.transitions)), nil
+}
+
+func (f *fakeTx) WithdrawPetEnrollments(uint, string) error {
+	return nil
+}
+
+func (f *fakeTx) UpdateImportJob(models.ImportJob) error {
+	return nil
+}
+
+func TestImportResearchStatus(t *testing.T) {
+	source := map[string]string{
+		"Name": "Tom", "Type": "cat", "Gender": "Male", "Age": "3", "Weight": "4.2", "Condition": "good",
+		"Behavior": "calm", "Owner": "Ann", "Email": "ann@example.com", "Stage": "",
+	}
+	mapping := models.ImportMapping{Columns: map[string]string{
+		"Name": "pet.name", "Type": "pet.animal_type", "Gender": "pet.gender", "Age": "pet.age",
+		"Weight": "pet.weight", "Condition": "pet.condition", "Behavior": "pet.behavior",
+		"Owner": "owner.fullname", "Email": "owner.email", "Stage": "pet.research_status",
+	}}
+
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{"", nil},
+		{models.ResearchStatusCandidate, nil},
+		{models.ResearchStatusConsented, []string{models.ResearchStatusConsented}},
+		{models.ResearchStatusCompleted, []string{
+			models.ResearchStatusConsented, models.ResearchStatusEnrolled, models.ResearchStatusActive,
+			models.ResearchStatusCompleted,
+		}},
+		{models.ResearchStatusWithdrawn, []string{models.ResearchStatusWithdrawn}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			source["Stage"] = tt.status
+			row, err := mapRow(source, mapping, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := validateRow(row); err != nil {
+				t.Fatalf("row with research status %q is invalid: %v", tt.status, err)
+			}
+
+			tx := &fakeTx{}
+			s := New(logging.NewLogger(new(bool), new(bool)), nil, nil, tx, "")
+			job := &models.ImportJob{ID: 1}
+			if err := s.importRow(job, 1, row); err != nil {
+				t.Fatal(err)
+			}
+
+			want := models.ResearchStatusCandidate
+			if len(tt.want) > 0 {
+				want = tt.want[len(tt.want)-1]
+			}
+			if tx.pet.ResearchStatus != want {
+				t.Errorf("research status = %q, want %q", tx.pet.ResearchStatus, want)
+			}
+			if len(tx.transitions) != len(tt.want) {
+				t.Fatalf("transitions = %+v, want stages %v", tx.transitions, tt.want)
+			}
+			from := models.ResearchStatusCandidate
+			for i, transition := range tx.transitions {
+				if transition.FromStatus != from || transition.ToStatus != tt.want[i] ||
+					transition.Reason != "imported" || transition.ActorID != 2 {
+					t.Errorf("transition %d = %+v, want %s -> %s imported by vet 2", i, transition, from, tt.want[i])
+				}
+				from = transition.ToStatus
+			}
+			if job.Report.CreatedPets != 1 || job.Report.ValidRows != 1 {
+				t.Errorf("report = %+v, want 1 created pet & valid row", job.Report)
+			}
+		})
+	}
+}
+
+func TestValidateRowResearchStatus(t *testing.T) {
+	row := importRow{
+		Pet: models.Pet{
+			AnimalType: "cat", Name: "Tom", Gender: "Male", Age: 3, Weight: 4.2, Condition: "good", Behavior: "calm",
+		},
+		Owner:          models.Owner{FullName: "Ann", Email: "ann@example.com"},
+		VetID:          2,
+		ResearchStatus: "none",
+	}
+	err := validateRow(row)
+	if err == nil || err.Error() != "validation failed: pet.research_status: research_status should be one of: "+
+		"candidate, consented, enrolled, active, withdrawn, completed" {
+		t.Errorf("error = %v, want invalid pet.research_status", err)
+	}
+}
@@ -0,0 +1,243 @@
+package importservice
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	speciesservice "github.com/vet-clinic-back/info-service/internal/service/species-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// importRow is one source row. Each row describes owner, pet and optional med entry
+type importRow struct {
+	PetRef string
+	Owner  models.Owner
+	Pet    models.Pet
+	VetID  uint
+	Entry  models.MedicalEntry
+	// ResearchStatus is historical research stage, pet is created as candidate & moved to it
+	ResearchStatus string
+}
+
+// hasEntry reports whether row contains med entry, rows with only pet & owner are allowed
+func (r importRow) hasEntry() bool {
+	e := r.Entry
+	return e.EntryDate != "" || e.Description != "" || e.Disease != "" || e.Vaccinations != "" ||
+		e.Recommendation != ""
+}
+
+// petKey identifies pet inside one import. Without reference pet is identified by owner, type & name
+func (r importRow) petKey() string {
+	if r.PetRef != "" {
+		return "ref:" + r.PetRef
+	}
+	return fmt.Sprintf("owner:%s|%s|%s|%s", r.Owner.Email, r.Owner.Phone,
+		strings.ToLower(r.Pet.AnimalType), strings.ToLower(r.Pet.Name))
+}
+
+type fieldSetter func(row *importRow, value string) error
+
+var fieldSetters = map[string]fieldSetter{
+	"pet.ref":             func(r *importRow, v string) error { r.PetRef = v; return nil },
+	"pet.animal_type":     func(r *importRow, v string) error { r.Pet.AnimalType = v; return nil },
+	"pet.name":            func(r *importRow, v string) error { r.Pet.Name = v; return nil },
+	"pet.breed":           func(r *importRow, v string) error { r.Pet.Breed = v; return nil },
+	"pet.gender":          func(r *importRow, v string) error { r.Pet.Gender = v; return nil },
+	"pet.age":             func(r *importRow, v string) error { return parseUint(v, &r.Pet.Age) },
+	"pet.birth_date":      func(r *importRow, v string) error { return parseDay(v, &r.Pet.BirthDate) },
+	"pet.weight":          func(r *importRow, v string) error { return parseFloat(v, &r.Pet.Weight) },
+	"pet.condition":       func(r *importRow, v string) error { r.Pet.Condition = v; return nil },
+	"pet.behavior":        func(r *importRow, v string) error { r.Pet.Behavior = v; return nil },
+	"pet.research_status": func(r *importRow, v string) error { r.ResearchStatus = v; return nil },
+	"pet.microchip":       func(r *importRow, v string) error { r.Pet.Microchip = v; return nil },
+	"owner.fullname":      func(r *importRow, v string) error { r.Owner.FullName = v; return nil },
+	"owner.email":         func(r *importRow, v string) error { r.Owner.Email = strings.ToLower(v); return nil },
+	"owner.phone":         func(r *importRow, v string) error { r.Owner.Phone = normalizePhone(v); return nil },
+	"card.vet_id":         func(r *importRow, v string) error { return parseUint(v, &r.VetID) },
+	"entry.entry_date":    func(r *importRow, v string) error { return parseDate(v, &r.Entry.EntryDate) },
+	"entry.description":   func(r *importRow, v string) error { r.Entry.Description = v; return nil },
+	"entry.disease":       func(r *importRow, v string) error { r.Entry.Disease = v; return nil },
+	"entry.vaccinations":  func(r *importRow, v string) error { r.Entry.Vaccinations = v; return nil },
+	"entry.recommendation": func(r *importRow, v string) error {
+		r.Entry.Recommendation = v
+		return nil
+	},
+	"entry.follow_up_at":  func(r *importRow, v string) error { return parseDay(v, &r.Entry.FollowUpAt) },
+	"entry.device_number": func(r *importRow, v string) error { return parseUint(v, &r.Entry.DeviceNumber) },
+	"entry.vet_id":        func(r *importRow, v string) error { return parseUint(v, &r.Entry.VetID) },
+}
+
+// ImportFields returns supported mapping targets
+func ImportFields() []string {
+	fields := make([]string, 0, len(fieldSetters))
+	for field := range fieldSetters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// validateMapping checks targets of mapping. Empty mapping means source columns are named as targets
+func validateMapping(mapping models.ImportMapping) error {
+	var fieldErrs validation.Errors
+	for column, target := range mapping.Columns {
+		if _, ok := fieldSetters[target]; !ok {
+			fieldErrs = append(fieldErrs, validation.FieldError{
+				Field:   "mapping.columns." + column,
+				Code:    validation.CodeInvalidEnum,
+				Message: "unknown target " + target + ", supported: " + strings.Join(ImportFields(), ", "),
+			})
+		}
+	}
+	for target := range mapping.Defaults {
+		if _, ok := fieldSetters[target]; !ok {
+			fieldErrs = append(fieldErrs, validation.FieldError{
+				Field:   "mapping.defaults." + target,
+				Code:    validation.CodeInvalidEnum,
+				Message: "unknown target " + target,
+			})
+		}
+	}
+	if len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+	return nil
+}
+
+// mapRow converts source row to import row. Parse errors are returned as validation.Errors
+func mapRow(source map[string]string, mapping models.ImportMapping, defaultVetID uint) (importRow, error) {
+	values := make(map[string]string, len(fieldSetters))
+	for target, value := range mapping.Defaults {
+		values[target] = value
+	}
+	for column, value := range source {
+		target := column
+		if len(mapping.Columns) > 0 {
+			target = mapping.Columns[column]
+		}
+		if _, ok := fieldSetters[target]; ok && value != "" {
+			values[target] = value
+		}
+	}
+
+	row := importRow{VetID: defaultVetID}
+	var fieldErrs validation.Errors
+	for target, value := range values {
+		if err := fieldSetters[target](&row, value); err != nil {
+			fieldErrs = append(fieldErrs, validation.FieldError{
+				Field:   target,
+				Code:    validation.CodeInvalidFormat,
+				Message: err.Error(),
+			})
+		}
+	}
+	if row.Entry.VetID == 0 {
+		row.Entry.VetID = row.VetID
+	}
+
+	if len(fieldErrs) > 0 {
+		return row, fieldErrs
+	}
+	return row, nil
+}
+
+// validateRow runs api validation for each part of row
+func validateRow(row importRow) error {
+	var fieldErrs validation.Errors
+	collect := func(prefix string, err error) {
+		if list, ok := err.(validation.Errors); ok {
+			for _, fieldErr := range list {
+				fieldErr.Field = prefix + fieldErr.Field
+				fieldErrs = append(fieldErrs, fieldErr)
+			}
+		}
+	}
+
+	collect("owner.", validation.ValidateImportedOwner(row.Owner))
+	collect("pet.", validation.ValidateCreatingPet(row.Pet))
+	collect("pet.", validation.ValidateImportedResearchStatus(row.ResearchStatus))
+	if row.VetID == 0 {
+		fieldErrs = append(fieldErrs, validation.FieldError{
+			Field:   "card.vet_id",
+			Code:    validation.CodeRequired,
+			Message: "card.vet_id is required, map column or set default vet",
+		})
+	}
+	if row.hasEntry() {
+		collect("entry.", validation.ValidateImportedMedEntry(row.Entry))
+	}
+
+	if len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+	return nil
+}
+
+// normalizeSpecies maps species & breed of row to catalogue codes, errors are reported as pet fields
+func normalizeSpecies(stor storage.Species, pet models.Pet) (models.Pet, error) {
+	normalized, err := speciesservice.NormalizePet(stor, pet)
+	if list, ok := err.(validation.Errors); ok {
+		for i := range list {
+			list[i].Field = "pet." + list[i].Field
+		}
+		return pet, list
+	}
+	return normalized, err
+}
+
+func parseUint(value string, dst *uint) error {
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("should be positive integer, got %q", value)
+	}
+	*dst = uint(parsed)
+	return nil
+}
+
+func parseFloat(value string, dst *float64) error {
+	parsed, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return fmt.Errorf("should be number, got %q", value)
+	}
+	*dst = parsed
+	return nil
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "02.01.2006 15:04", "02.01.2006"}
+
+func parseDate(value string, dst *string) error {
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			*dst = parsed.Format("2006-01-02 15:04:05")
+			return nil
+		}
+	}
+	return fmt.Errorf("should be date like 2006-01-02 or 02.01.2006, got %q", value)
+}
+
+// parseDay is parseDate without time
+func parseDay(value string, dst *string) error {
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			*dst = parsed.Format(models.DateLayout)
+			return nil
+		}
+	}
+	return fmt.Errorf("should be date like 2006-01-02 or 02.01.2006, got %q", value)
+}
+
+// normalizePhone removes formatting so same phones match on deduplication
+func normalizePhone(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package importservice
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// rowReader reads source rows as column -> value
+type rowReader interface {
+	Next() (map[string]string, error)
+}
+
+func newRowReader(format string, r io.Reader) (rowReader, error) {
+	switch format {
+	case models.ImportFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		header, err := reader.Read()
+		if err != nil {
+			return nil, errs.Validation("failed to read csv header", err)
+		}
+		if len(header) > 0 {
+			// excel adds BOM to utf-8 csv
+			header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+		}
+		return &csvReader{reader: reader, header: header}, nil
+	case models.ImportFormatNDJSON:
+		return &ndjsonReader{reader: bufio.NewReader(r)}, nil
+	default:
+		return nil, errs.Validation(fmt.Sprintf("format should be %s or %s",
+			models.ImportFormatCSV, models.ImportFormatNDJSON), nil)
+	}
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+}
+
+func (r *csvReader) Next() (map[string]string, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(r.header))
+	for i, column := range r.header {
+		if i < len(record) {
+			row[column] = strings.TrimSpace(record[i])
+		}
+	}
+	return row, nil
+}
+
+// maxLineBytes limits ndjson line, longer line is reported as invalid row & skipped
+const maxLineBytes = 16 * 1024 * 1024
+
+type ndjsonReader struct {
+	reader *bufio.Reader
+}
+
+// Next reads flat json object. Nested values are kept as json
+func (r *ndjsonReader) Next() (map[string]string, error) {
+	for {
+		raw, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		line := strings.TrimSpace(string(raw))
+		if line == "" {
+			continue
+		}
+
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			return nil, errs.Validation("invalid json line", err)
+		}
+
+		row := make(map[string]string, len(object))
+		for column, value := range object {
+			row[column] = stringify(value)
+		}
+		return row, nil
+	}
+}
+
+// readLine returns next line. Line longer than maxLineBytes is read to the end & returned as row error
+func (r *ndjsonReader) readLine() ([]byte, error) {
+	var (
+		line    []byte
+		tooLong bool
+	)
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		if !tooLong && len(line)+len(chunk) > maxLineBytes {
+			tooLong, line = true, nil
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err != nil && err != io.EOF:
+			return nil, err
+		case tooLong:
+			return nil, errs.Validation(fmt.Sprintf("line is longer than %d bytes", maxLineBytes), nil)
+		case err == io.EOF && len(line) == 0:
+			return nil, io.EOF
+		}
+		return line, nil
+	}
+}
+
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,204 @@
+package infoservice
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+func (s *InfoService) CreateAllergy(allergy models.Allergy) (models.Allergy, error) {
+	if _, err := s.storage.GetPet(models.Pet{ID: allergy.PetID}); err != nil {
+		return models.Allergy{}, err
+	}
+
+	id, err := s.storage.CreateAllergy(allergy)
+	if err != nil {
+		return models.Allergy{}, err
+	}
+
+	return s.storage.GetAllergy(id)
+}
+
+func (s *InfoService) GetAllergies(petID uint) ([]models.Allergy, error) {
+	if _, err := s.storage.GetPet(models.Pet{ID: petID}); err != nil {
+		return nil, err
+	}
+	return s.storage.GetAllergies(petID)
+}
+
+func (s *InfoService) DeleteAllergy(petID, id uint) error {
+	allergy, err := s.storage.GetAllergy(id)
+	if err != nil {
+		return err
+	}
+	if allergy.PetID != petID {
+		return errs.NotFound("allergy not found", nil)
+	}
+	return s.storage.DeleteAllergy(id)
+}
+
+func (s *InfoService) GetAllergenSynonyms() ([]models.AllergenSynonyms, error) {
+	return s.storage.GetAllergenSynonyms()
+}
+
+// SaveAllergenSynonyms replaces synonym group of substance. Terms are trimmed & lower cased
+func (s *InfoService) SaveAllergenSynonyms(group models.AllergenSynonyms) (models.AllergenSynonyms, error) {
+	group.Substance = strings.ToLower(strings.TrimSpace(group.Substance))
+
+	synonyms := make([]string, 0, len(group.Synonyms))
+	seen := map[string]bool{group.Substance: true}
+	for _, synonym := range group.Synonyms {
+		synonym = strings.ToLower(strings.TrimSpace(synonym))
+		if seen[synonym] {
+			continue
+		}
+		seen[synonym] = true
+		synonyms = append(synonyms, synonym)
+	}
+	group.Synonyms = synonyms
+
+	if err := s.storage.SaveAllergenSynonyms(group); err != nil {
+		return models.AllergenSynonyms{}, err
+	}
+	return group, nil
+}
+
+func (s *InfoService) DeleteAllergenSynonyms(substance string) error {
+	return s.storage.DeleteAllergenSynonyms(strings.ToLower(strings.TrimSpace(substance)))
+}
+
+// allergyWarnings matches allergens of pet and their synonyms with vaccinations & recommendation of entry.
+// Chronic conditions are not matched
+func (s *InfoService) allergyWarnings(stor storage.Info, entry models.MedicalEntry) ([]models.AllergyWarning, error) {
+	warnings := []models.AllergyWarning{}
+	if strings.TrimSpace(entry.Vaccinations) == "" && strings.TrimSpace(entry.Recommendation) == "" {
+		return warnings, nil
+	}
+
+	allergies, err := stor.GetAllergiesByMedRecord(entry.MedicalRecordID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []struct{ name, text string }{
+		{"vaccinations", strings.ToLower(entry.Vaccinations)},
+		{"recommendation", strings.ToLower(entry.Recommendation)},
+	}
+	for _, allergy := range allergies {
+		if allergy.Kind != models.AllergyKindAllergy {
+			continue
+		}
+
+		terms, err := stor.GetAllergenTerms(allergy.Substance)
+		if err != nil {
+			return nil, err
+		}
+		pattern := allergenPattern(terms)
+
+		for _, field := range fields {
+			if term, ok := matchTerm(field.text, pattern); ok {
+				warnings = append(warnings, models.AllergyWarning{
+					AllergyID: allergy.ID,
+					Substance: allergy.Substance,
+					Severity:  allergy.Severity,
+					Reaction:  allergy.Reaction,
+					Field:     field.name,
+					Term:      term,
+				})
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// allergenPattern compiles terms of allergen into one pattern matching any term at start of word.
+// Only start is anchored so inflected forms like "пенициллином" match "пенициллин". Longer terms go first,
+// so "amoxicillin-clavulanate" is reported instead of "amoxicillin". nil pattern matches nothing
+func allergenPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return regexp.MustCompile(`(?:^|[^\p{L}\p{N}])(` + strings.Join(quoted, "|") + `)`)
+}
+
+// matchTerm finds term of allergen pattern in lower cased text
+func matchTerm(text string, pattern *regexp.Regexp) (string, bool) {
+	if pattern == nil {
+		return "", false
+	}
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// requireAllergyOverride rejects high severity matches unless vet gave allergy_override_reason
+func requireAllergyOverride(entry models.CreatingMedEntry, warnings []models.AllergyWarning) error {
+	if strings.TrimSpace(entry.AllergyOverrideReason) != "" {
+		return nil
+	}
+
+	var conflicts validation.Errors
+	for _, warning := range warnings {
+		if warning.Severity != models.AllergySeverityHigh {
+			continue
+		}
+		conflicts = append(conflicts, validation.FieldError{
+			Field: warning.Field,
+			Code:  validation.CodeAllergyConflict,
+			Message: fmt.Sprintf("%q matches high severity allergy to %s, set allergy_override_reason to confirm",
+				warning.Term, warning.Substance),
+		})
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return conflicts
+}
+
+// logAllergyOverrides saves high severity matches confirmed by vet
+func (s *InfoService) logAllergyOverrides(
+	stor storage.Info, entryID uint, entry models.CreatingMedEntry, warnings []models.AllergyWarning,
+) error {
+	for _, warning := range warnings {
+		if warning.Severity != models.AllergySeverityHigh {
+			continue
+		}
+
+		err := stor.CreateAllergyOverride(models.AllergyOverride{
+			MedicalEntryID: entryID,
+			AllergyID:      warning.AllergyID,
+			VetID:          entry.VetID,
+			MatchedTerm:    warning.Term,
+			Reason:         entry.AllergyOverrideReason,
+		})
+		if err != nil {
+			return err
+		}
+
+		s.log.WithField("op", "InfoService.logAllergyOverrides").Warnf(
+			"med entry %d saved despite high severity allergy %d (%s matched %q in %s) by vet %d: %s",
+			entryID, warning.AllergyID, warning.Substance, warning.Term, warning.Field, entry.VetID,
+			entry.AllergyOverrideReason,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package infoservice
+
+import "testing"
+
+func TestMatchTerm(t *testing.T) {
+	pattern := allergenPattern([]string{"penicillin", "amoxicillin", " пенициллин ", "", "amoxicillin-clavulanate"})
+
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"amoxicillin 250 mg", "amoxicillin", true},
+		{"курс: пенициллином 5 дней", "пенициллин", true},
+		{"start (penicillin)", "penicillin", true},
+		{"amoxicillin-clavulanate 2 times a day", "amoxicillin-clavulanate", true},
+		{"benzylpenicillin", "", false},
+		{"no antibiotics", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		term, ok := matchTerm(tt.text, pattern)
+		if term != tt.want || ok != tt.ok {
+			t.Errorf("matchTerm(%q) = %q, %v, want %q, %v", tt.text, term, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := matchTerm("penicillin", allergenPattern(nil)); ok {
+		t.Error("empty terms matched text")
+	}
+	if _, ok := matchTerm("a+b", allergenPattern([]string{"a+b"})); !ok {
+		t.Error("term with regexp meta characters did not match")
+	}
+}
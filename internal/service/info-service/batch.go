@@ -0,0 +1,151 @@
+package infoservice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	speciesservice "github.com/vet-clinic-back/info-service/internal/service/species-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+const maxBatchSize = 500
+
+var errBatchFailed = errors.New("batch item failed")
+
+// CreatePetsBatch creates pets with cards. See runBatch for modes
+func (s *InfoService) CreatePetsBatch(items []models.PetWithCard, mode string) ([]models.BatchResult, error) {
+	return s.runBatch(len(items), mode,
+		func(i int) error {
+			if err := validation.ValidateCreatingPet(items[i].Pet); err != nil {
+				return err
+			}
+			return validation.ValidatePetCard(items[i].OwnerID, items[i].VetID)
+		},
+		func(stor storage.Info, i int) models.BatchResult {
+			pet, err := speciesservice.NormalizePet(stor, items[i].Pet)
+			if err != nil {
+				return models.BatchResult{Err: err}
+			}
+			id, err := stor.CreatePetWithCard(pet, items[i].OwnerID, items[i].VetID)
+			return models.BatchResult{ID: id, Err: err}
+		},
+	)
+}
+
+// CreateMedEntriesBatch creates med entries. Allergies are checked per item like in CreateMedEntry.
+// See runBatch for modes
+func (s *InfoService) CreateMedEntriesBatch(entries []models.CreatingMedEntry, mode string) ([]models.BatchResult, error) {
+	return s.runBatch(len(entries), mode,
+		func(i int) error {
+			return validation.ValidateCreatingMedEntry(entries[i])
+		},
+		func(stor storage.Info, i int) models.BatchResult {
+			created, err := s.createMedEntry(stor, entries[i])
+			return models.BatchResult{ID: created.ID, Warnings: created.Warnings, Err: err}
+		},
+	)
+}
+
+// UpdatePetsBatch replaces pets like UpdatePet, IDs are in items. See runBatch for modes
+func (s *InfoService) UpdatePetsBatch(pets []models.Pet, mode string) ([]models.BatchResult, error) {
+	return s.runBatch(len(pets), mode,
+		func(i int) error {
+			return validation.ValidateBatchUpdatingPet(pets[i])
+		},
+		func(stor storage.Info, i int) models.BatchResult {
+			pet, err := updatePet(stor, pets[i])
+			return models.BatchResult{ID: pet.ID, Err: err}
+		},
+	)
+}
+
+// UpdateMedEntriesBatch replaces med entries, IDs are in items. Allergies are checked per item like
+// on creation. See runBatch for modes
+func (s *InfoService) UpdateMedEntriesBatch(entries []models.CreatingMedEntry, mode string) ([]models.BatchResult, error) {
+	return s.runBatch(len(entries), mode,
+		func(i int) error {
+			return validation.ValidateUpdatingMedEntry(entries[i])
+		},
+		func(stor storage.Info, i int) models.BatchResult {
+			warnings, err := s.updateMedEntry(stor, entries[i])
+			if err != nil {
+				return models.BatchResult{Err: err}
+			}
+			return models.BatchResult{ID: entries[i].ID, Warnings: warnings}
+		},
+	)
+}
+
+// runBatch validates & saves n items. In atomic mode items are saved in one transaction
+// and nothing is saved if any item fails. In best effort mode each item has own transaction.
+// Returned error is not nil only if batch itself is invalid or transaction failed
+func (s *InfoService) runBatch(
+	n int, mode string, validate func(i int) error, save func(stor storage.Info, i int) models.BatchResult,
+) ([]models.BatchResult, error) {
+	if mode != models.BatchModeAtomic && mode != models.BatchModeBestEffort {
+		return nil, errs.Validation(fmt.Sprintf("mode should be %s or %s",
+			models.BatchModeAtomic, models.BatchModeBestEffort), nil)
+	}
+	if n == 0 || n > maxBatchSize {
+		return nil, errs.Validation(fmt.Sprintf("batch should contain 1-%d items", maxBatchSize), nil)
+	}
+
+	results := make([]models.BatchResult, n)
+	invalid := false
+	for i := range results {
+		if err := validate(i); err != nil {
+			results[i].Err = err
+			invalid = true
+		}
+	}
+
+	if mode == models.BatchModeBestEffort {
+		for i := range results {
+			if results[i].Err != nil {
+				continue
+			}
+			err := s.tx.WithTx(func(tx storage.Tx) error {
+				results[i] = save(tx, i)
+				return results[i].Err
+			})
+			if results[i].Err == nil && err != nil {
+				results[i] = models.BatchResult{Err: err}
+			}
+		}
+		return results, nil
+	}
+
+	if !invalid {
+		err := s.tx.WithTx(func(tx storage.Tx) error {
+			failed := false
+			for i := range results {
+				results[i] = save(tx, i)
+				if results[i].Err != nil {
+					failed = true
+				}
+			}
+			if failed {
+				return errBatchFailed
+			}
+			return nil
+		})
+		if err == nil {
+			return results, nil
+		}
+		if !errors.Is(err, errBatchFailed) {
+			return nil, err
+		}
+	}
+
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].ID = 0
+			results[i].Warnings = nil
+			results[i].Err = errs.RolledBack("not saved because another item of atomic batch failed", nil)
+		}
+	}
+	return results, nil
+}
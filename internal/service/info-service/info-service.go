@@ -8,8 +8,9 @@ import (
 type InfoService struct {
 	log     *logging.Logger
 	storage storage.Info
+	tx      storage.Transactor
 }
 
-func New(log *logging.Logger, storage storage.Info) *InfoService {
-	return &InfoService{log: log, storage: storage}
+func New(log *logging.Logger, storage storage.Info, tx storage.Transactor) *InfoService {
+	return &InfoService{log: log, storage: storage, tx: tx}
 }
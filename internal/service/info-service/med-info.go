@@ -1,11 +1,81 @@
 package infoservice
 
-import "github.com/vet-clinic-back/info-service/internal/models"
+import (
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
 
-func (s *InfoService) CreateMedEntry(entry models.MedicalEntry) (uint, error) {
-	return s.storage.CreateMedEntry(entry)
+// CreateMedEntry creates entry & returns allergies of pet found in its text.
+// High severity allergies block entry unless it has allergy_override_reason
+func (s *InfoService) CreateMedEntry(entry models.CreatingMedEntry) (models.CreatedMedEntryDTO, error) {
+	var created models.CreatedMedEntryDTO
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		var err error
+		created, err = s.createMedEntry(tx, entry)
+		return err
+	})
+	if err != nil {
+		return models.CreatedMedEntryDTO{}, err
+	}
+	return created, nil
+}
+
+func (s *InfoService) createMedEntry(stor storage.Info, entry models.CreatingMedEntry) (models.CreatedMedEntryDTO, error) {
+	warnings, err := s.allergyWarnings(stor, entry.MedicalEntry)
+	if err != nil {
+		return models.CreatedMedEntryDTO{}, err
+	}
+	if err := requireAllergyOverride(entry, warnings); err != nil {
+		return models.CreatedMedEntryDTO{}, err
+	}
+
+	id, err := stor.CreateMedEntry(entry.MedicalEntry)
+	if err != nil {
+		return models.CreatedMedEntryDTO{}, err
+	}
+
+	if err := s.logAllergyOverrides(stor, id, entry, warnings); err != nil {
+		return models.CreatedMedEntryDTO{}, err
+	}
+
+	return models.CreatedMedEntryDTO{ID: id, Warnings: warnings}, nil
+}
+
+// updateMedEntry replaces entry, it stays in its med record. Allergies are checked like on creation
+func (s *InfoService) updateMedEntry(stor storage.Info, entry models.CreatingMedEntry) ([]models.AllergyWarning, error) {
+	current, err := stor.GetMedEntries(models.EntryReqFilter{EntryID: &entry.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nil, errs.NotFound("med entry not found", nil)
+	}
+	entry.MedicalRecordID = current[0].MedicalRecordID
+
+	warnings, err := s.allergyWarnings(stor, entry.MedicalEntry)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAllergyOverride(entry, warnings); err != nil {
+		return nil, err
+	}
+
+	if err := stor.UpdateMedEntry(entry.MedicalEntry); err != nil {
+		return nil, err
+	}
+
+	if err := s.logAllergyOverrides(stor, entry.ID, entry, warnings); err != nil {
+		return nil, err
+	}
+	return warnings, nil
 }
 
 func (s *InfoService) GetMedEntries(filters models.EntryReqFilter) ([]models.MedicalEntry, error) {
 	return s.storage.GetMedEntries(filters)
 }
+
+// IterateMedEntries calls fn for each entry without loading all of them
+func (s *InfoService) IterateMedEntries(filters models.EntryReqFilter, fn func(models.MedicalEntry) error) error {
+	return s.storage.IterateMedEntries(filters, fn)
+}
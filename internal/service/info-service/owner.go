@@ -1,8 +1,24 @@
 package infoservice
 
-import "github.com/vet-clinic-back/info-service/internal/models"
+import (
+	"errors"
 
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// CreateOwner creates owner if there is no owner with same email or phone
 func (s *InfoService) CreateOwner(owner models.Owner) (uint, error) {
+	for _, unique := range []models.Owner{{Email: owner.Email}, {Phone: owner.Phone}} {
+		_, err := s.storage.GetOwner(unique)
+		if err == nil {
+			return 0, errs.Conflict("owner with same email or phone already exists", nil)
+		}
+		if !errors.Is(err, errs.ErrNotFound) {
+			return 0, err
+		}
+	}
+
 	return s.storage.CreateOwner(owner)
 }
 
@@ -14,7 +30,18 @@ func (s *InfoService) GetAllOwners() ([]models.Owner, error) {
 	return s.storage.GetAllOwners()
 }
 
+// UpdateOwner updates owner if new email is not used by another owner
 func (s *InfoService) UpdateOwner(owner models.Owner) (models.Owner, error) {
+	if owner.Email != "" {
+		existing, err := s.storage.GetOwner(models.Owner{Email: owner.Email})
+		if err == nil && existing.ID != owner.ID {
+			return models.Owner{}, errs.Conflict("owner with this email already exists", nil)
+		}
+		if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return models.Owner{}, err
+		}
+	}
+
 	return s.storage.UpdateOwner(owner)
 }
 
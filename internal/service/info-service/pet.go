@@ -1,8 +1,18 @@
 package infoservice
 
-import "github.com/vet-clinic-back/info-service/internal/models"
+import (
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	speciesservice "github.com/vet-clinic-back/info-service/internal/service/species-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
 
+// CreatePetWithCard creates pet. Species & breed are replaced with catalogue codes
 func (s *InfoService) CreatePetWithCard(pet models.Pet, ownderID uint, vetID uint) (uint, error) {
+	pet, err := speciesservice.NormalizePet(s.storage, pet)
+	if err != nil {
+		return 0, err
+	}
 	return s.storage.CreatePetWithCard(pet, ownderID, vetID)
 }
 
@@ -14,10 +24,75 @@ func (s *InfoService) GetPets(filter models.PetReqFilter) ([]models.OutputPetDTO
 	return s.storage.GetPetsWithOwnerAndVet(filter)
 }
 
+// IteratePets calls fn for each pet without loading all of them
+func (s *InfoService) IteratePets(filter models.PetReqFilter, fn func(models.OutputPetDTO) error) error {
+	return s.storage.IteratePetsWithOwnerAndVet(filter, fn)
+}
+
+// UpdatePet replaces pet. Species & breed are replaced with catalogue codes, research status
+// can only stay the same
 func (s *InfoService) UpdatePet(pet models.Pet) (models.Pet, error) {
-	return s.storage.UpdatePet(pet)
+	return updatePet(s.storage, pet)
+}
+
+func updatePet(stor storage.Info, pet models.Pet) (models.Pet, error) {
+	current, err := stor.GetPet(models.Pet{ID: pet.ID})
+	if err != nil {
+		return models.Pet{}, err
+	}
+	if pet.ResearchStatus != "" && pet.ResearchStatus != current.ResearchStatus {
+		return models.Pet{}, errs.Conflict("research status is changed with POST /pets/:id/research-status", nil)
+	}
+	pet.ResearchStatus = current.ResearchStatus
+	pet = keepBirthDate(pet, current)
+
+	pet, err = speciesservice.NormalizePet(stor, pet)
+	if err != nil {
+		return models.Pet{}, err
+	}
+	return stor.UpdatePet(pet)
+}
+
+// keepBirthDate keeps saved birth date when update sends only age that saved date still gives,
+// otherwise birth date estimated from age would move with every update
+func keepBirthDate(pet, current models.Pet) models.Pet {
+	if pet.BirthDate == "" && pet.Age > 0 && current.BirthDate != "" && current.Age == pet.Age {
+		pet.BirthDate = current.BirthDate
+		pet.BirthDateEstimated = current.BirthDateEstimated
+	}
+	return pet
 }
 
 func (s *InfoService) DelPetWithCard(id uint) error {
 	return s.storage.DelPetWithCard(id)
 }
+
+// GetPetRecord collects pet, owner, vet & all med entries ordered by date
+func (s *InfoService) GetPetRecord(petID uint) (models.PetRecord, error) {
+	pet, err := s.storage.GetPet(models.Pet{ID: petID})
+	if err != nil {
+		return models.PetRecord{}, err
+	}
+
+	record, err := s.storage.GetMedRecordByPet(petID)
+	if err != nil {
+		return models.PetRecord{}, err
+	}
+
+	owner, err := s.storage.GetOwner(models.Owner{ID: record.OwnerID})
+	if err != nil {
+		return models.PetRecord{}, err
+	}
+
+	vet, err := s.storage.GetVet(record.VetID)
+	if err != nil {
+		return models.PetRecord{}, err
+	}
+
+	entries, err := s.storage.GetMedEntries(models.EntryReqFilter{PetID: &petID})
+	if err != nil {
+		return models.PetRecord{}, err
+	}
+
+	return models.PetRecord{Pet: pet, Record: record, Owner: owner, Vet: vet, Entries: entries}, nil
+}
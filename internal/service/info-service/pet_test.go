@@ -0,0 +1,37 @@
+package infoservice
+
+import (
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func TestKeepBirthDate(t *testing.T) {
+	estimated := models.Pet{Age: 3, BirthDate: "2023-05-10", BirthDateEstimated: true}
+	exact := models.Pet{Age: 3, BirthDate: "2023-02-01"}
+
+	tests := []struct {
+		name          string
+		pet           models.Pet
+		current       models.Pet
+		wantBirthDate string
+		wantEstimated bool
+	}{
+		{"same age keeps estimate", models.Pet{Age: 3}, estimated, "2023-05-10", true},
+		{"same age keeps exact date", models.Pet{Age: 3}, exact, "2023-02-01", false},
+		{"changed age is estimated again", models.Pet{Age: 4}, estimated, "", false},
+		{"sent birth date wins", models.Pet{Age: 3, BirthDate: "2023-01-01"}, estimated, "2023-01-01", false},
+		{"no saved birth date", models.Pet{Age: 3}, models.Pet{}, "", false},
+		{"no age", models.Pet{}, estimated, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keepBirthDate(tt.pet, tt.current)
+			if got.BirthDate != tt.wantBirthDate || got.BirthDateEstimated != tt.wantEstimated {
+				t.Errorf("birth date = %q estimated %v, want %q estimated %v",
+					got.BirthDate, got.BirthDateEstimated, tt.wantBirthDate, tt.wantEstimated)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package labservice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/hl7"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+type LabService struct {
+	log     *logging.Logger
+	storage storage.Info
+	lab     storage.Lab
+	tx      storage.Transactor
+	// loc is clinic zone of analyzer times without offset
+	loc *time.Location
+}
+
+func New(
+	log *logging.Logger, storage storage.Info, lab storage.Lab, tx storage.Transactor, clinic config.ClinicConfig,
+) *LabService {
+	// zone is validated when config is loaded
+	loc, err := time.LoadLocation(clinic.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &LabService{log: log, storage: storage, lab: lab, tx: tx, loc: loc}
+}
+
+// HandleMessage ingests ORU^R01 message from analyzer and returns HL7 acknowledgement.
+// AA - results stored or message is duplicate, AE - message is stored in review queue or storage failed,
+// AR - message can not be processed
+func (s *LabService) HandleMessage(raw []byte) []byte {
+	log := s.log.WithField("op", "LabService.HandleMessage")
+	now := time.Now()
+
+	msg, err := hl7.Parse(raw)
+	if err != nil {
+		log.Warn("failed to parse message: ", err.Error())
+		return hl7.ACK(nil, hl7.AckReject, err.Error(), now)
+	}
+
+	oru, err := hl7.ParseORU(msg, s.loc)
+	if err != nil {
+		log.Warn("failed to parse ORU: ", err.Error())
+		return hl7.ACK(msg, hl7.AckReject, err.Error(), now)
+	}
+	log = log.WithField("control_id", oru.ControlID).WithField("sender", oru.Sender)
+
+	labMsg, err := s.ingest(oru, string(raw))
+	switch {
+	case errors.Is(err, errs.ErrConflict):
+		log.Info("duplicate message")
+		return hl7.ACK(msg, hl7.AckAccept, "duplicate message, already received", now)
+	case err != nil:
+		log.Error("failed to ingest message: ", err.Error())
+		return hl7.ACK(msg, hl7.AckError, "failed to store results", now)
+	case labMsg.Status == models.LabMessageReview:
+		log.Warn("patient not matched: ", labMsg.ReviewReason)
+		return hl7.ACK(msg, hl7.AckError,
+			fmt.Sprintf("patient not matched, queued for review #%d: %s", labMsg.ID, labMsg.ReviewReason), now)
+	}
+
+	log.Infof("stored %d results of pet %d", len(oru.Observations), labMsg.PetID)
+	return hl7.ACK(msg, hl7.AckAccept, "", now)
+}
+
+// ingest saves message and its results. Message of unmatched patient is saved to review queue without results
+func (s *LabService) ingest(oru hl7.ORU, raw string) (models.LabMessage, error) {
+	msg := models.LabMessage{
+		Sender:     oru.Sender,
+		ControlID:  oru.ControlID,
+		Status:     models.LabMessageMatched,
+		PatientIDs: joinPatientIDs(oru.PatientIDs),
+		Raw:        raw,
+	}
+
+	petID, reason, err := s.matchPet(oru.PatientIDs)
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+
+	var record models.MedicalRecord
+	if reason == "" {
+		record, err = s.storage.GetMedRecordByPet(petID)
+		if errors.Is(err, errs.ErrNotFound) {
+			reason = fmt.Sprintf("pet %d has no medical record", petID)
+		} else if err != nil {
+			return models.LabMessage{}, err
+		}
+	}
+
+	if reason != "" {
+		msg.Status = models.LabMessageReview
+		msg.ReviewReason = reason
+	} else {
+		msg.PetID = petID
+	}
+
+	err = s.tx.WithTx(func(tx storage.Tx) error {
+		id, err := tx.CreateLabMessage(msg)
+		if err != nil {
+			return err
+		}
+		msg.ID = id
+
+		if msg.Status != models.LabMessageMatched {
+			return nil
+		}
+		return saveResults(tx, id, record.ID, oru.Observations)
+	})
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+
+	return msg, nil
+}
+
+func (s *LabService) GetLabResults(filter models.LabResultReqFilter) ([]models.LabResult, error) {
+	return s.lab.GetLabResults(filter)
+}
+
+// GetLabReviews returns review queue, oldest first
+func (s *LabService) GetLabReviews() ([]models.LabMessage, error) {
+	return s.lab.GetLabMessages(models.LabMessageReview)
+}
+
+// ResolveLabReview stores results of message from review queue for pet chosen by vet
+func (s *LabService) ResolveLabReview(id, petID uint) (models.LabMessage, error) {
+	msg, err := s.reviewMessage(id)
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+
+	parsed, err := hl7.Parse([]byte(msg.Raw))
+	if err != nil {
+		return models.LabMessage{}, fmt.Errorf("failed to parse stored message: %w", err)
+	}
+	oru, err := hl7.ParseORU(parsed, s.loc)
+	if err != nil {
+		return models.LabMessage{}, fmt.Errorf("failed to parse stored message: %w", err)
+	}
+
+	record, err := s.storage.GetMedRecordByPet(petID)
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+
+	now := time.Now()
+	msg.Status = models.LabMessageMatched
+	msg.PetID = petID
+	msg.ResolvedAt = &now
+
+	err = s.tx.WithTx(func(tx storage.Tx) error {
+		if err := saveResults(tx, msg.ID, record.ID, oru.Observations); err != nil {
+			return err
+		}
+		return tx.UpdateLabMessage(msg)
+	})
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+
+	return msg, nil
+}
+
+// DismissLabReview removes message from review queue without storing results
+func (s *LabService) DismissLabReview(id uint) (models.LabMessage, error) {
+	msg, err := s.reviewMessage(id)
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+
+	now := time.Now()
+	msg.Status = models.LabMessageDismissed
+	msg.ResolvedAt = &now
+
+	if err := s.lab.UpdateLabMessage(msg); err != nil {
+		return models.LabMessage{}, err
+	}
+	return msg, nil
+}
+
+func (s *LabService) reviewMessage(id uint) (models.LabMessage, error) {
+	msg, err := s.lab.GetLabMessage(id)
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+	if msg.Status != models.LabMessageReview {
+		return models.LabMessage{}, errs.Conflict(fmt.Sprintf("lab message is already %s", msg.Status), nil)
+	}
+	return msg, nil
+}
+
+func saveResults(tx storage.Tx, messageID, recordID uint, observations []hl7.Observation) error {
+	for _, observation := range observations {
+		_, err := tx.CreateLabResult(models.LabResult{
+			MessageID:       messageID,
+			MedicalRecordID: recordID,
+			PanelCode:       observation.PanelCode,
+			PanelName:       observation.PanelName,
+			Code:            observation.Code,
+			Name:            observation.Name,
+			ValueType:       observation.ValueType,
+			Value:           observation.Value,
+			Units:           observation.Units,
+			ReferenceRange:  observation.ReferenceRange,
+			AbnormalFlag:    observation.AbnormalFlag,
+			ResultStatus:    observation.Status,
+			ObservedAt:      observation.ObservedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
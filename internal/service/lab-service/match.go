@@ -0,0 +1,98 @@
+package labservice
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vet-clinic-back/info-service/internal/hl7"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// PID-3 identifier type codes. Analyzers should send pet ID as PI and microchip as CHIP.
+// Identifier without type is microchip if it has 15 digits, other ones are unknown: analyzers put their own
+// sample & accession numbers there, so they are never taken as pet IDs
+const (
+	identifierPetID     = "PI"
+	identifierMicrochip = "CHIP"
+)
+
+var isoMicrochip = regexp.MustCompile(`^[0-9]{15}$`)
+
+// matchPet finds the only pet of identifiers. Pet is matched only if every recognised identifier points to it,
+// reason is set when pet is not matched
+func (s *LabService) matchPet(ids []hl7.PatientID) (petID uint, reason string, err error) {
+	matched := map[uint]bool{}
+	var missing []string
+
+	for _, id := range ids {
+		pet := models.Pet{}
+		switch kind := identifierKind(id); kind {
+		case identifierPetID:
+			parsed, err := strconv.ParseUint(id.ID, 10, 32)
+			if err != nil || parsed == 0 {
+				missing = append(missing, id.String())
+				continue
+			}
+			pet.ID = uint(parsed)
+		case identifierMicrochip:
+			pet.Microchip = id.ID
+		default:
+			// sample ids & other identifiers of analyzer
+			continue
+		}
+
+		found, err := s.storage.GetPet(pet)
+		if errors.Is(err, errs.ErrNotFound) {
+			missing = append(missing, id.String())
+			continue
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		matched[found.ID] = true
+	}
+
+	switch {
+	case len(matched) == 0 && len(missing) == 0:
+		return 0, "no pet ID or microchip in PID-3", nil
+	case len(matched) == 0:
+		return 0, "no pet found for " + strings.Join(missing, ", "), nil
+	case len(matched) > 1:
+		return 0, fmt.Sprintf("identifiers match %d different pets", len(matched)), nil
+	}
+
+	for id := range matched {
+		petID = id
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Sprintf("no pet found for %s, other identifiers match pet %d",
+			strings.Join(missing, ", "), petID), nil
+	}
+	return petID, "", nil
+}
+
+func identifierKind(id hl7.PatientID) string {
+	switch strings.ToUpper(id.Type) {
+	case identifierPetID:
+		return identifierPetID
+	case identifierMicrochip:
+		return identifierMicrochip
+	case "":
+		if isoMicrochip.MatchString(id.ID) {
+			return identifierMicrochip
+		}
+	}
+	return ""
+}
+
+func joinPatientIDs(ids []hl7.PatientID) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id.String())
+	}
+	return strings.Join(values, ", ")
+}
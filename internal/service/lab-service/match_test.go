@@ -0,0 +1,85 @@
+package labservice
+
+import (
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/hl7"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+func TestIdentifierKind(t *testing.T) {
+	tests := []struct {
+		id   hl7.PatientID
+		want string
+	}{
+		{hl7.PatientID{ID: "42", Type: "PI"}, identifierPetID},
+		{hl7.PatientID{ID: "42", Type: "pi"}, identifierPetID},
+		{hl7.PatientID{ID: "643094100123456", Type: "CHIP"}, identifierMicrochip},
+		{hl7.PatientID{ID: "643094100123456"}, identifierMicrochip},
+		{hl7.PatientID{ID: "42"}, ""},
+		{hl7.PatientID{ID: "S-1001"}, ""},
+		{hl7.PatientID{ID: "42", Type: "SID"}, ""},
+		{hl7.PatientID{ID: "64309410012345"}, ""},
+	}
+	for _, tt := range tests {
+		if got := identifierKind(tt.id); got != tt.want {
+			t.Errorf("identifierKind(%v) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+// fakePets finds pets by ID & microchip
+type fakePets struct {
+	storage.Info
+	pets []models.Pet
+}
+
+func (f fakePets) GetPet(pet models.Pet) (models.Pet, error) {
+	for _, p := range f.pets {
+		if (pet.ID != 0 && p.ID == pet.ID) || (pet.Microchip != "" && p.Microchip == pet.Microchip) {
+			return p, nil
+		}
+	}
+	return models.Pet{}, errs.NotFound("pet not found", nil)
+}
+
+func TestMatchPet(t *testing.T) {
+	s := &LabService{storage: fakePets{pets: []models.Pet{
+		{ID: 1, Microchip: "643094100000001"},
+		{ID: 2, Microchip: "643094100000002"},
+	}}}
+
+	tests := []struct {
+		name    string
+		ids     []hl7.PatientID
+		want    uint
+		matched bool
+	}{
+		{"pet ID", []hl7.PatientID{{ID: "1", Type: "PI"}}, 1, true},
+		{"untyped microchip", []hl7.PatientID{{ID: "643094100000002"}}, 2, true},
+		{"pet ID & sample number", []hl7.PatientID{{ID: "1", Type: "PI"}, {ID: "2"}}, 1, true},
+		{"agreeing identifiers", []hl7.PatientID{{ID: "1", Type: "PI"}, {ID: "643094100000001", Type: "CHIP"}}, 1, true},
+		{"only sample number", []hl7.PatientID{{ID: "2"}}, 0, false},
+		{"unknown pet", []hl7.PatientID{{ID: "9", Type: "PI"}}, 0, false},
+		{"conflict", []hl7.PatientID{{ID: "1", Type: "PI"}, {ID: "643094100000002", Type: "CHIP"}}, 0, false},
+		{"partial match", []hl7.PatientID{{ID: "1", Type: "PI"}, {ID: "643094100000009", Type: "CHIP"}}, 0, false},
+		{"invalid pet ID", []hl7.PatientID{{ID: "x", Type: "PI"}}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			petID, reason, err := s.matchPet(tt.ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if petID != tt.want {
+				t.Errorf("pet = %d, want %d", petID, tt.want)
+			}
+			if (reason == "") != tt.matched {
+				t.Errorf("reason = %q", reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package measurementservice
+
+import (
+	"math"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+const (
+	// rapidLossPercent of weight lost within rapidLossDays is flagged in trend
+	rapidLossPercent = 10.0
+	rapidLossDays    = 30
+)
+
+type MeasurementService struct {
+	log          *logging.Logger
+	storage      storage.Info
+	measurements storage.Measurement
+}
+
+func New(log *logging.Logger, storage storage.Info, measurements storage.Measurement) *MeasurementService {
+	return &MeasurementService{log: log, storage: storage, measurements: measurements}
+}
+
+// CreateMeasurement saves weigh-in. Pet weight becomes weight of latest measurement
+func (s *MeasurementService) CreateMeasurement(measurement models.Measurement) (models.Measurement, error) {
+	if _, err := s.storage.GetPet(models.Pet{ID: measurement.PetID}); err != nil {
+		return models.Measurement{}, err
+	}
+
+	id, err := s.measurements.CreateMeasurement(measurement)
+	if err != nil {
+		return models.Measurement{}, err
+	}
+
+	return s.measurements.GetMeasurement(id)
+}
+
+func (s *MeasurementService) GetMeasurements(petID uint) ([]models.Measurement, error) {
+	if _, err := s.storage.GetPet(models.Pet{ID: petID}); err != nil {
+		return nil, err
+	}
+	return s.measurements.GetMeasurements(petID)
+}
+
+// GetWeightTrend returns weight series with percent changes and rapid losses
+func (s *MeasurementService) GetWeightTrend(petID uint) (models.WeightTrend, error) {
+	measurements, err := s.GetMeasurements(petID)
+	if err != nil {
+		return models.WeightTrend{}, err
+	}
+
+	trend := weightTrend(measurements)
+	trend.PetID = petID
+	return trend, nil
+}
+
+// weightTrend expects measurements ordered by date. Loss of each weigh-in is measured from the heaviest
+// weigh-in of preceding rapidLossDays
+func weightTrend(measurements []models.Measurement) models.WeightTrend {
+	trend := models.WeightTrend{
+		Points:           make([]models.WeightTrendPoint, 0, len(measurements)),
+		RapidLossPercent: rapidLossPercent,
+		RapidLossDays:    rapidLossDays,
+		Losses:           []models.WeightLoss{},
+	}
+	if len(measurements) == 0 {
+		return trend
+	}
+
+	dates := make([]time.Time, len(measurements))
+	for i, measurement := range measurements {
+		// dates come from storage in DateLayout
+		dates[i], _ = time.Parse(models.DateLayout, measurement.MeasuredAt)
+
+		point := models.WeightTrendPoint{Measurement: measurement}
+		if i > 0 {
+			change := percentChange(measurements[i-1].Weight, measurement.Weight)
+			point.ChangePercent = &change
+		}
+		trend.Points = append(trend.Points, point)
+
+		heaviest := -1
+		for j := i - 1; j >= 0 && dates[i].Sub(dates[j]) <= rapidLossDays*24*time.Hour; j-- {
+			if heaviest == -1 || measurements[j].Weight > measurements[heaviest].Weight {
+				heaviest = j
+			}
+		}
+		if heaviest == -1 {
+			continue
+		}
+		change := percentChange(measurements[heaviest].Weight, measurement.Weight)
+		if -change > rapidLossPercent {
+			trend.Losses = append(trend.Losses, models.WeightLoss{
+				From:          measurements[heaviest].MeasuredAt,
+				To:            measurement.MeasuredAt,
+				FromWeight:    measurements[heaviest].Weight,
+				ToWeight:      measurement.Weight,
+				ChangePercent: change,
+			})
+		}
+	}
+
+	trend.ChangePercent = percentChange(measurements[0].Weight, measurements[len(measurements)-1].Weight)
+	trend.RapidLoss = len(trend.Losses) > 0
+	return trend
+}
+
+// percentChange is rounded to 0.1%
+func percentChange(from, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return math.Round((to-from)/from*1000) / 10
+}
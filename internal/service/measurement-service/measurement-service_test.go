@@ -0,0 +1,99 @@
+package measurementservice
+
+import (
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func weighIns(points ...interface{}) []models.Measurement {
+	var measurements []models.Measurement
+	for i := 0; i < len(points); i += 2 {
+		measurements = append(measurements, models.Measurement{
+			MeasuredAt: points[i].(string),
+			Weight:     points[i+1].(float64),
+		})
+	}
+	return measurements
+}
+
+func TestWeightTrend(t *testing.T) {
+	tests := []struct {
+		name         string
+		measurements []models.Measurement
+		change       float64
+		losses       int
+	}{
+		{
+			name: "no measurements",
+		},
+		{
+			name:         "single measurement",
+			measurements: weighIns("2024-01-01", 10.0),
+		},
+		{
+			name:         "steady gain",
+			measurements: weighIns("2024-01-01", 10.0, "2024-02-01", 10.5, "2024-03-01", 11.0),
+			change:       10,
+		},
+		{
+			name:         "rapid loss within window",
+			measurements: weighIns("2024-01-01", 10.0, "2024-01-15", 8.5),
+			change:       -15,
+			losses:       1,
+		},
+		{
+			name:         "exactly threshold is not rapid",
+			measurements: weighIns("2024-01-01", 10.0, "2024-01-15", 9.0),
+			change:       -10,
+		},
+		{
+			name:         "slow loss outside window",
+			measurements: weighIns("2024-01-01", 10.0, "2024-06-01", 8.0),
+			change:       -20,
+		},
+		{
+			name:         "loss from heaviest point in window",
+			measurements: weighIns("2024-01-01", 10.0, "2024-01-10", 10.4, "2024-01-20", 9.8),
+			change:       -2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := weightTrend(tt.measurements)
+
+			if len(trend.Points) != len(tt.measurements) {
+				t.Fatalf("got %d points, want %d", len(trend.Points), len(tt.measurements))
+			}
+			if len(trend.Points) > 0 && trend.Points[0].ChangePercent != nil {
+				t.Error("first point should have no change")
+			}
+			if trend.ChangePercent != tt.change {
+				t.Errorf("change = %v, want %v", trend.ChangePercent, tt.change)
+			}
+			if len(trend.Losses) != tt.losses {
+				t.Errorf("got %d losses, want %d", len(trend.Losses), tt.losses)
+			}
+			if trend.RapidLoss != (tt.losses > 0) {
+				t.Errorf("rapid loss = %v", trend.RapidLoss)
+			}
+		})
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		from, to, want float64
+	}{
+		{10, 11, 10},
+		{10, 9.5, -5},
+		{3, 2, -33.3},
+		{0, 5, 0},
+	}
+	for _, tt := range tests {
+		if got := percentChange(tt.from, tt.to); got != tt.want {
+			t.Errorf("percentChange(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package notificationservice
+
+import (
+	"errors"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/notify"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+type NotificationService struct {
+	log           *logging.Logger
+	storage       storage.Info
+	notifications storage.Notification
+	tx            storage.Transactor
+	notifiers     map[string]notify.Notifier
+	cfg           config.NotifyConfig
+	clinic        config.ClinicConfig
+}
+
+func New(
+	log *logging.Logger, storage storage.Info, notifications storage.Notification, tx storage.Transactor,
+	notifiers []notify.Notifier, cfg config.NotifyConfig, clinic config.ClinicConfig,
+) *NotificationService {
+	byChannel := make(map[string]notify.Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+	return &NotificationService{
+		log: log, storage: storage, notifications: notifications, tx: tx, notifiers: byChannel, cfg: cfg,
+		clinic: clinic,
+	}
+}
+
+// Notifiers returns channels configured in cfg
+func Notifiers(cfg config.NotifyConfig) []notify.Notifier {
+	var notifiers []notify.Notifier
+	if cfg.SMTP.Addr != "" {
+		notifiers = append(notifiers, notify.NewSMTPNotifier(cfg.SMTP))
+	}
+	if cfg.SMSWebhook.URL != "" {
+		notifiers = append(notifiers, notify.NewWebhookSMSNotifier(cfg.SMSWebhook))
+	}
+	return notifiers
+}
+
+// GetNotificationPreferences returns saved preferences of owner or defaults: russian emails
+func (s *NotificationService) GetNotificationPreferences(ownerID uint) (models.NotificationPreferences, error) {
+	if _, err := s.storage.GetOwner(models.Owner{ID: ownerID}); err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	return preferences(s.notifications, ownerID)
+}
+
+func (s *NotificationService) SaveNotificationPreferences(
+	prefs models.NotificationPreferences,
+) (models.NotificationPreferences, error) {
+	if _, err := s.storage.GetOwner(models.Owner{ID: prefs.OwnerID}); err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	if err := s.notifications.SaveNotificationPreferences(prefs); err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	return s.notifications.GetNotificationPreferences(prefs.OwnerID)
+}
+
+// CreateReminder creates custom reminder to owner of pet
+func (s *NotificationService) CreateReminder(input models.CreatingReminderDTO) (models.Reminder, error) {
+	record, err := s.storage.GetMedRecordByPet(input.PetID)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	if record.OwnerID == 0 {
+		return models.Reminder{}, errs.Conflict("pet has no owner to remind", nil)
+	}
+
+	id, err := s.notifications.CreateReminder(models.Reminder{
+		OwnerID: record.OwnerID,
+		PetID:   input.PetID,
+		Kind:    models.ReminderKindCustom,
+		Message: input.Message,
+		DueAt:   input.DueAt,
+		Status:  models.ReminderStatusPending,
+	})
+	if err != nil {
+		return models.Reminder{}, err
+	}
+
+	return s.notifications.GetReminder(id)
+}
+
+func (s *NotificationService) GetReminder(id uint) (models.Reminder, error) {
+	return s.notifications.GetReminder(id)
+}
+
+func (s *NotificationService) GetReminders(filter models.ReminderReqFilter) ([]models.Reminder, error) {
+	return s.notifications.GetReminders(filter)
+}
+
+// CancelReminder cancels reminder that is not sent yet
+func (s *NotificationService) CancelReminder(id uint) (models.Reminder, error) {
+	if _, err := s.notifications.GetReminder(id); err != nil {
+		return models.Reminder{}, err
+	}
+	err := s.notifications.UpdateReminderStatus(id, models.ReminderStatusPending, models.ReminderStatusCancelled)
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	return s.notifications.GetReminder(id)
+}
+
+func (s *NotificationService) GetNotifications(filter models.NotificationReqFilter) ([]models.Notification, error) {
+	return s.notifications.GetNotifications(filter)
+}
+
+func preferences(stor storage.Notification, ownerID uint) (models.NotificationPreferences, error) {
+	prefs, err := stor.GetNotificationPreferences(ownerID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return models.NotificationPreferences{OwnerID: ownerID, Language: models.LanguageRU, Email: true}, nil
+	}
+	return prefs, err
+}
@@ -0,0 +1,192 @@
+package notificationservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/notify"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+func TestRender(t *testing.T) {
+	data := messageData{
+		OwnerName:      "Мария",
+		PetName:        "Барсик",
+		EntryDate:      "15.01.2026",
+		FollowUpAt:     "29.01.2026",
+		Disease:        "отит",
+		Recommendation: "капли",
+		Message:        "Пора на вакцинацию",
+		ClinicName:     "Vet",
+		ClinicPhone:    "+7 900 000-00-00",
+	}
+
+	tests := []struct {
+		name        string
+		kind        string
+		language    string
+		channel     string
+		data        messageData
+		wantSubject string
+		wantBody    string
+	}{
+		{
+			name: "follow-up email ru", kind: models.ReminderKindFollowUp, language: models.LanguageRU,
+			channel: models.NotificationChannelEmail, data: data,
+			wantSubject: "Повторный осмотр: Барсик",
+			wantBody: "Здравствуйте, Мария!\n\nВрач рекомендовал повторный осмотр питомца Барсик 29.01.2026.\n" +
+				"Рекомендации: капли\n\nЗапишитесь на приём по телефону +7 900 000-00-00.\n\nVet",
+		},
+		{
+			name: "follow-up sms en without phone", kind: models.ReminderKindFollowUp, language: models.LanguageEN,
+			channel:  models.NotificationChannelSMS,
+			data:     messageData{PetName: "Rex", FollowUpAt: "Jan 29, 2026", ClinicName: "Vet"},
+			wantBody: "Vet: recheck for Rex is recommended on Jan 29, 2026.",
+		},
+		{
+			name: "new entry email en without optional lines", kind: models.ReminderKindNewEntry,
+			language: models.LanguageEN, channel: models.NotificationChannelEmail,
+			data:        messageData{OwnerName: "John", PetName: "Rex", EntryDate: "Jan 15, 2026", ClinicName: "Vet"},
+			wantSubject: "New medical record entry for Rex",
+			wantBody:    "Hello John,\n\nAn entry dated Jan 15, 2026 was added to the medical record of Rex.\n\nVet",
+		},
+		{
+			name: "new entry sms ru", kind: models.ReminderKindNewEntry, language: models.LanguageRU,
+			channel: models.NotificationChannelSMS, data: data,
+			wantBody: "Vet: в медкарту Барсик добавлена запись от 15.01.2026.",
+		},
+		{
+			name: "unknown language falls back to ru", kind: models.ReminderKindCustom, language: "de",
+			channel: models.NotificationChannelEmail, data: data,
+			wantSubject: "Напоминание: Барсик",
+			wantBody:    "Здравствуйте, Мария!\n\nПора на вакцинацию\n\nVet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, body, err := render(tt.kind, tt.language, tt.channel, tt.data)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+
+	if _, _, err := render("birthday", models.LanguageRU, models.NotificationChannelEmail, data); err == nil {
+		t.Error("want error for unknown reminder kind")
+	}
+}
+
+func TestFormatDay(t *testing.T) {
+	tests := []struct {
+		value    string
+		language string
+		want     string
+	}{
+		{"2026-01-15", models.LanguageRU, "15.01.2026"},
+		{"2026-01-15 10:30:00", models.LanguageEN, "Jan 15, 2026"},
+		{"2026-01-15", "de", "15.01.2026"},
+		{"", models.LanguageRU, ""},
+		{"soon", models.LanguageRU, "soon"},
+	}
+	for _, tt := range tests {
+		if got := formatDay(tt.value, tt.language); got != tt.want {
+			t.Errorf("formatDay(%q, %s) = %q, want %q", tt.value, tt.language, got, tt.want)
+		}
+	}
+}
+
+type fakeNotifier struct {
+	err error
+}
+
+func (f fakeNotifier) Channel() string { return models.NotificationChannelEmail }
+
+func (f fakeNotifier) Send(context.Context, notify.Message) error { return f.err }
+
+type fakeNotifications struct {
+	storage.Notification
+	saved   models.Notification
+	settled []uint
+}
+
+func (f *fakeNotifications) UpdateNotification(notification models.Notification) error {
+	f.saved = notification
+	return nil
+}
+
+func (f *fakeNotifications) SettleReminder(id uint) error {
+	f.settled = append(f.settled, id)
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	failing := fakeNotifier{err: errors.New("connection refused")}
+
+	tests := []struct {
+		name         string
+		notifier     notify.Notifier
+		attempts     uint
+		wantStatus   string
+		wantAttempts uint
+		wantDelay    time.Duration
+		wantError    string
+	}{
+		{"sent", fakeNotifier{}, 0, models.NotificationStatusSent, 1, 0, ""},
+		{"sent on retry clears error", fakeNotifier{}, 2, models.NotificationStatusSent, 3, 0, ""},
+		{"first failure", failing, 0, models.NotificationStatusPending, 1, time.Minute, "connection refused"},
+		{"second failure", failing, 1, models.NotificationStatusPending, 2, 5 * time.Minute, "connection refused"},
+		{"last retry", failing, 3, models.NotificationStatusPending, 4, 2 * time.Hour, "connection refused"},
+		{"retries run out", failing, 4, models.NotificationStatusFailed, 5, 0, "connection refused"},
+		{"channel disabled", nil, 0, models.NotificationStatusFailed, 1, 0, errNoNotifier.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications := &fakeNotifications{}
+			var notifiers []notify.Notifier
+			if tt.notifier != nil {
+				notifiers = append(notifiers, tt.notifier)
+			}
+			s := New(logging.NewLogger(new(bool), new(bool)), nil, notifications, nil, notifiers,
+				config.NotifyConfig{}, config.ClinicConfig{})
+
+			before := time.Now()
+			s.send(context.Background(), models.Notification{
+				ID: 1, ReminderID: 2, Channel: models.NotificationChannelEmail, Attempts: tt.attempts,
+				Status: models.NotificationStatusPending, LastError: "previous error",
+			})
+
+			got := notifications.saved
+			if got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts || got.LastError != tt.wantError {
+				t.Errorf("notification is %s after %d attempts with error %q, want %s after %d with %q",
+					got.Status, got.Attempts, got.LastError, tt.wantStatus, tt.wantAttempts, tt.wantError)
+			}
+			if tt.wantDelay > 0 {
+				if delay := got.NextAttemptAt.Sub(before); delay < tt.wantDelay || delay > tt.wantDelay+time.Minute {
+					t.Errorf("next attempt in %v, want %v", delay, tt.wantDelay)
+				}
+			}
+			if (got.Status == models.NotificationStatusSent) != (got.SentAt != nil) {
+				t.Errorf("sent at = %v for %s notification", got.SentAt, got.Status)
+			}
+
+			// reminder is settled once delivery stops being pending
+			wantSettled := tt.wantStatus != models.NotificationStatusPending
+			if settled := len(notifications.settled) == 1 && notifications.settled[0] == 2; settled != wantSettled {
+				t.Errorf("settled reminders = %v, want settled %v", notifications.settled, wantSettled)
+			}
+		})
+	}
+}
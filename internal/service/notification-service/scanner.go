@@ -0,0 +1,234 @@
+package notificationservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/notify"
+	schedulerservice "github.com/vet-clinic-back/info-service/internal/service/scheduler-service"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+const (
+	// newEntryWindow limits new entry reminders to recent entries, imported history is not announced
+	newEntryWindow = 24 * time.Hour
+	// scanBatch is max reminders & deliveries processed per scan
+	scanBatch = 100
+	// sendLease hides claimed delivery from other replicas while it is sent
+	sendLease   = 5 * time.Minute
+	sendTimeout = 30 * time.Second
+)
+
+// retryDelays are waits after failed attempts. Delivery fails for good when they run out
+var retryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+// errNoNotifier fails delivery without retries, channel was disabled after it was queued
+var errNoNotifier = errors.New("channel is not configured")
+
+// Jobs returns scanner job if any channel is configured
+func (s *NotificationService) Jobs() []schedulerservice.Job {
+	if len(s.notifiers) == 0 {
+		return nil
+	}
+	return []schedulerservice.Job{{
+		Name:        "notifications.scan",
+		Description: "Creates reminders of med entries, queues due reminders & sends pending notifications",
+		Spec:        "@every " + s.cfg.ScanInterval.String(),
+		Run:         s.Scan,
+	}}
+}
+
+// Scan creates reminders of new med entries & follow-ups, queues deliveries of due reminders
+// and sends pending deliveries. Scan that found nothing returns schedulerservice.ErrIdle
+func (s *NotificationService) Scan(ctx context.Context) error {
+	log := s.log.WithField("op", "NotificationService.Scan")
+	now := time.Now()
+
+	scheduled, err := s.notifications.ScheduleEntryReminders(
+		now.Add(-newEntryWindow), s.cfg.FollowUpLead, s.clinic.TimeZone,
+	)
+	if err != nil {
+		return err
+	}
+	if scheduled > 0 {
+		log.Info("scheduled reminders: ", scheduled)
+	}
+
+	reminders, err := s.notifications.GetDueReminders(now, scanBatch)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range reminders {
+		if err := s.queue(reminder); err != nil {
+			log.WithField("reminder_id", reminder.ID).Error("failed to queue reminder: ", err)
+		}
+	}
+
+	delivered, err := s.deliver(ctx)
+	if err != nil {
+		return err
+	}
+	if scheduled == 0 && len(reminders) == 0 && delivered == 0 {
+		return schedulerservice.ErrIdle
+	}
+	return nil
+}
+
+// queue creates delivery of reminder per enabled channel. Reminder is skipped if owner opted out
+// or has no enabled channel with contact
+func (s *NotificationService) queue(reminder models.Reminder) error {
+	return s.tx.WithTx(func(tx storage.Tx) error {
+		prefs, err := preferences(tx, reminder.OwnerID)
+		if err != nil {
+			return err
+		}
+
+		var notifications []models.Notification
+		if !prefs.OptedOut {
+			notifications, err = s.compose(tx, reminder, prefs)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, notification := range notifications {
+			if _, err := tx.CreateNotification(notification); err != nil {
+				return err
+			}
+		}
+
+		// reminder is sent once any delivery is sent, see SettleReminder
+		status := models.ReminderStatusQueued
+		if len(notifications) == 0 {
+			status = models.ReminderStatusSkipped
+		}
+		// fails if other replica processed reminder first, its deliveries are rolled back then
+		return tx.UpdateReminderStatus(reminder.ID, models.ReminderStatusPending, status)
+	})
+}
+
+func (s *NotificationService) compose(
+	tx storage.Tx, reminder models.Reminder, prefs models.NotificationPreferences,
+) ([]models.Notification, error) {
+	owner, err := tx.GetOwner(models.Owner{ID: reminder.OwnerID})
+	if err != nil {
+		return nil, err
+	}
+	pet, err := tx.GetPet(models.Pet{ID: reminder.PetID})
+	if err != nil {
+		return nil, err
+	}
+
+	data := messageData{
+		OwnerName:   owner.FullName,
+		PetName:     pet.Name,
+		Message:     reminder.Message,
+		ClinicName:  s.clinic.Name,
+		ClinicPhone: s.clinic.Phone,
+	}
+	if reminder.MedicalEntryID != 0 {
+		entries, err := tx.GetMedEntries(models.EntryReqFilter{EntryID: &reminder.MedicalEntryID})
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) > 0 {
+			data.EntryDate = formatDay(entries[0].EntryDate, prefs.Language)
+			data.FollowUpAt = formatDay(entries[0].FollowUpAt, prefs.Language)
+			data.Disease = entries[0].Disease
+			data.Recommendation = entries[0].Recommendation
+		}
+	}
+
+	recipients := map[string]string{}
+	if prefs.Email && owner.Email != "" {
+		recipients[models.NotificationChannelEmail] = owner.Email
+	}
+	if prefs.SMS && owner.Phone != "" {
+		recipients[models.NotificationChannelSMS] = owner.Phone
+	}
+
+	var notifications []models.Notification
+	for _, channel := range []string{models.NotificationChannelEmail, models.NotificationChannelSMS} {
+		recipient, ok := recipients[channel]
+		if !ok || s.notifiers[channel] == nil {
+			continue
+		}
+
+		subject, body, err := render(reminder.Kind, prefs.Language, channel, data)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, models.Notification{
+			ReminderID: reminder.ID,
+			OwnerID:    reminder.OwnerID,
+			Channel:    channel,
+			Recipient:  recipient,
+			Language:   prefs.Language,
+			Subject:    subject,
+			Body:       body,
+			Status:     models.NotificationStatusPending,
+		})
+	}
+
+	return notifications, nil
+}
+
+// deliver sends claimed pending deliveries and schedules retries of failed ones
+func (s *NotificationService) deliver(ctx context.Context) (int, error) {
+	now := time.Now()
+	notifications, err := s.notifications.ClaimNotifications(now, now.Add(sendLease), scanBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, notification := range notifications {
+		if ctx.Err() != nil {
+			// claimed deliveries are retried by next scan after lease ends
+			return 0, ctx.Err()
+		}
+		s.send(ctx, notification)
+	}
+
+	return len(notifications), nil
+}
+
+func (s *NotificationService) send(ctx context.Context, notification models.Notification) {
+	log := s.log.WithField("op", "NotificationService.send").WithField("notification_id", notification.ID)
+
+	err := errNoNotifier
+	if notifier := s.notifiers[notification.Channel]; notifier != nil {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = notifier.Send(sendCtx, notify.Message{
+			To: notification.Recipient, Subject: notification.Subject, Body: notification.Body,
+		})
+		cancel()
+	}
+
+	now := time.Now()
+	notification.Attempts++
+	if err == nil {
+		notification.Status = models.NotificationStatusSent
+		notification.SentAt = &now
+		notification.LastError = ""
+	} else {
+		log.Error("failed to send notification: ", err)
+		notification.LastError = err.Error()
+		if int(notification.Attempts) > len(retryDelays) || err == errNoNotifier {
+			notification.Status = models.NotificationStatusFailed
+		} else {
+			notification.NextAttemptAt = now.Add(retryDelays[notification.Attempts-1])
+		}
+	}
+
+	if err := s.notifications.UpdateNotification(notification); err != nil {
+		log.Error("failed to save notification: ", err)
+		return
+	}
+	if notification.Status != models.NotificationStatusPending {
+		if err := s.notifications.SettleReminder(notification.ReminderID); err != nil {
+			log.Error("failed to settle reminder: ", err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package notificationservice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+// messageData is available in templates
+type messageData struct {
+	OwnerName      string
+	PetName        string
+	EntryDate      string
+	FollowUpAt     string
+	Disease        string
+	Recommendation string
+	Message        string
+	ClinicName     string
+	ClinicPhone    string
+}
+
+type messageTemplate struct {
+	Subject *template.Template
+	Body    *template.Template // email
+	SMS     *template.Template
+}
+
+func newTemplate(subject, body, sms string) messageTemplate {
+	return messageTemplate{
+		Subject: template.Must(template.New("subject").Parse(subject)),
+		Body:    template.Must(template.New("body").Parse(body)),
+		SMS:     template.Must(template.New("sms").Parse(sms)),
+	}
+}
+
+// templates by reminder kind & language
+var templates = map[string]map[string]messageTemplate{
+	models.ReminderKindFollowUp: {
+		models.LanguageRU: newTemplate(
+			"Повторный осмотр: {{.PetName}}",
+			"Здравствуйте, {{.OwnerName}}!\n\n"+
+				"Врач рекомендовал повторный осмотр питомца {{.PetName}} {{.FollowUpAt}}."+
+				"{{if .Recommendation}}\nРекомендации: {{.Recommendation}}{{end}}\n\n"+
+				"Запишитесь на приём{{if .ClinicPhone}} по телефону {{.ClinicPhone}}{{end}}.\n\n{{.ClinicName}}",
+			"{{.ClinicName}}: повторный осмотр {{.PetName}} рекомендован {{.FollowUpAt}}."+
+				"{{if .ClinicPhone}} Запись: {{.ClinicPhone}}{{end}}",
+		),
+		models.LanguageEN: newTemplate(
+			"Recheck for {{.PetName}}",
+			"Hello {{.OwnerName}},\n\n"+
+				"The vet recommended a recheck for {{.PetName}} on {{.FollowUpAt}}."+
+				"{{if .Recommendation}}\nRecommendations: {{.Recommendation}}{{end}}\n\n"+
+				"Please book a visit{{if .ClinicPhone}} at {{.ClinicPhone}}{{end}}.\n\n{{.ClinicName}}",
+			"{{.ClinicName}}: recheck for {{.PetName}} is recommended on {{.FollowUpAt}}."+
+				"{{if .ClinicPhone}} Call {{.ClinicPhone}}{{end}}",
+		),
+	},
+	models.ReminderKindNewEntry: {
+		models.LanguageRU: newTemplate(
+			"Новая запись в медкарте: {{.PetName}}",
+			"Здравствуйте, {{.OwnerName}}!\n\n"+
+				"В медицинскую карту питомца {{.PetName}} добавлена запись от {{.EntryDate}}."+
+				"{{if .Disease}}\nДиагноз: {{.Disease}}{{end}}"+
+				"{{if .Recommendation}}\nРекомендации: {{.Recommendation}}{{end}}\n\n{{.ClinicName}}",
+			"{{.ClinicName}}: в медкарту {{.PetName}} добавлена запись от {{.EntryDate}}.",
+		),
+		models.LanguageEN: newTemplate(
+			"New medical record entry for {{.PetName}}",
+			"Hello {{.OwnerName}},\n\n"+
+				"An entry dated {{.EntryDate}} was added to the medical record of {{.PetName}}."+
+				"{{if .Disease}}\nDiagnosis: {{.Disease}}{{end}}"+
+				"{{if .Recommendation}}\nRecommendations: {{.Recommendation}}{{end}}\n\n{{.ClinicName}}",
+			"{{.ClinicName}}: an entry dated {{.EntryDate}} was added to the record of {{.PetName}}.",
+		),
+	},
+	models.ReminderKindCustom: {
+		models.LanguageRU: newTemplate(
+			"Напоминание: {{.PetName}}",
+			"Здравствуйте, {{.OwnerName}}!\n\n{{.Message}}\n\n{{.ClinicName}}",
+			"{{.ClinicName}}: {{.Message}}",
+		),
+		models.LanguageEN: newTemplate(
+			"Reminder for {{.PetName}}",
+			"Hello {{.OwnerName}},\n\n{{.Message}}\n\n{{.ClinicName}}",
+			"{{.ClinicName}}: {{.Message}}",
+		),
+	},
+}
+
+// dayLayouts are date formats of languages
+var dayLayouts = map[string]string{
+	models.LanguageRU: "02.01.2006",
+	models.LanguageEN: "Jan 2, 2006",
+}
+
+// render fills template of reminder kind for channel. Unknown language falls back to russian
+func render(kind, language, channel string, data messageData) (subject, body string, err error) {
+	byLanguage, ok := templates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for reminder kind %q", kind)
+	}
+	tmpl, ok := byLanguage[language]
+	if !ok {
+		tmpl = byLanguage[models.LanguageRU]
+	}
+
+	bodyTemplate := tmpl.Body
+	if channel == models.NotificationChannelSMS {
+		bodyTemplate = tmpl.SMS
+	} else if subject, err = execute(tmpl.Subject, data); err != nil {
+		return "", "", err
+	}
+
+	body, err = execute(bodyTemplate, data)
+	return subject, body, err
+}
+
+func execute(tmpl *template.Template, data messageData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// formatDay formats date or timestamp of med entry for language. Unparsed value is kept as is
+func formatDay(value, language string) string {
+	if len(value) < len(models.DateLayout) {
+		return value
+	}
+	day, err := time.Parse(models.DateLayout, value[:len(models.DateLayout)])
+	if err != nil {
+		return value
+	}
+	layout, ok := dayLayouts[language]
+	if !ok {
+		layout = dayLayouts[models.LanguageRU]
+	}
+	return day.Format(layout)
+}
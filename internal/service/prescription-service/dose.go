@@ -0,0 +1,123 @@
+package prescriptionservice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// checkDose converts dose to mg/kg by pet weight and compares it with reference range of drug.
+// Doses in ml, tablets & other units can not be converted, they are unchecked & require dose_override
+func (s *PrescriptionService) checkDose(pet models.Pet, prescription models.Prescription) (models.DoseCheck, error) {
+	doseRange, err := s.prescriptions.GetDoseRange(prescription.Drug, pet.AnimalType)
+	if errors.Is(err, errs.ErrNotFound) {
+		return models.DoseCheck{Status: models.DoseCheckUnchecked, Message: "no reference dose range for drug"}, nil
+	}
+	if err != nil {
+		return models.DoseCheck{}, err
+	}
+
+	check := models.DoseCheck{MinMgPerKg: doseRange.MinMgPerKg, MaxMgPerKg: doseRange.MaxMgPerKg}
+	switch prescription.Unit {
+	case "mg/kg":
+		check.MgPerKg = prescription.Dose
+	case "mg":
+		if pet.Weight <= 0 {
+			check.Status = models.DoseCheckUnchecked
+			check.Message = "pet weight is unknown"
+			return check, nil
+		}
+		check.MgPerKg = prescription.Dose / pet.Weight
+	default:
+		check.Status = models.DoseCheckUnchecked
+		check.Message = fmt.Sprintf("dose in %s can not be checked", prescription.Unit)
+		return check, nil
+	}
+
+	check.Status = models.DoseCheckOK
+	if check.MgPerKg < doseRange.MinMgPerKg || check.MgPerKg > doseRange.MaxMgPerKg {
+		check.Status = models.DoseCheckOutOfRange
+		check.Message = fmt.Sprintf("%.2f mg/kg is outside of %g-%g mg/kg",
+			check.MgPerKg, doseRange.MinMgPerKg, doseRange.MaxMgPerKg)
+	}
+	return check, nil
+}
+
+// requireDoseInRange rejects dose out of range and dose that could not be checked, e.g. drug without
+// reference range, unless vet confirmed it with dose_override
+func requireDoseInRange(prescription models.Prescription, check models.DoseCheck) error {
+	if check.Status == models.DoseCheckOK || prescription.DoseOverride {
+		return nil
+	}
+
+	if check.Status == models.DoseCheckUnchecked {
+		return validation.Errors{{
+			Field:   "dose",
+			Code:    validation.CodeDoseUnchecked,
+			Message: "dose can not be checked: " + check.Message + ", set dose_override to confirm",
+		}}
+	}
+	return validation.Errors{{
+		Field:   "dose",
+		Code:    validation.CodeOutOfRange,
+		Message: "dose " + check.Message + ", set dose_override to confirm",
+	}}
+}
+
+func (s *PrescriptionService) CreateDoseRange(doseRange models.DoseRange) (models.DoseRange, error) {
+	doseRange, err := s.normalizeDoseRange(doseRange)
+	if err != nil {
+		return models.DoseRange{}, err
+	}
+
+	id, err := s.prescriptions.CreateDoseRange(doseRange)
+	if err != nil {
+		return models.DoseRange{}, err
+	}
+	doseRange.ID = id
+	return doseRange, nil
+}
+
+func (s *PrescriptionService) GetDoseRanges(drug string) ([]models.DoseRange, error) {
+	return s.prescriptions.GetDoseRanges(drug)
+}
+
+func (s *PrescriptionService) UpdateDoseRange(doseRange models.DoseRange) (models.DoseRange, error) {
+	doseRange, err := s.normalizeDoseRange(doseRange)
+	if err != nil {
+		return models.DoseRange{}, err
+	}
+
+	if err := s.prescriptions.UpdateDoseRange(doseRange); err != nil {
+		return models.DoseRange{}, err
+	}
+	return doseRange, nil
+}
+
+func (s *PrescriptionService) DeleteDoseRange(id uint) error {
+	return s.prescriptions.DeleteDoseRange(id)
+}
+
+// normalizeDoseRange replaces species with catalogue code, so range is found by pet animal_type
+func (s *PrescriptionService) normalizeDoseRange(doseRange models.DoseRange) (models.DoseRange, error) {
+	if doseRange.Species == "" {
+		return doseRange, nil
+	}
+
+	species, err := s.storage.FindSpecies(doseRange.Species)
+	if errors.Is(err, errs.ErrNotFound) {
+		return models.DoseRange{}, validation.Errors{{
+			Field:   "species",
+			Code:    validation.CodeInvalidEnum,
+			Message: "species " + doseRange.Species + " is not in species catalogue",
+		}}
+	}
+	if err != nil {
+		return models.DoseRange{}, err
+	}
+	doseRange.Species = species.Code
+	return doseRange, nil
+}
@@ -0,0 +1,123 @@
+package prescriptionservice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// fakeDoseRanges finds ranges like storage does: species range wins over range for any species
+type fakeDoseRanges struct {
+	storage.Prescription
+	ranges []models.DoseRange
+}
+
+func (f fakeDoseRanges) GetDoseRange(drug, species string) (models.DoseRange, error) {
+	var found *models.DoseRange
+	for i, r := range f.ranges {
+		if !strings.EqualFold(r.Drug, drug) {
+			continue
+		}
+		if strings.EqualFold(r.Species, species) {
+			return r, nil
+		}
+		if r.Species == "" {
+			found = &f.ranges[i]
+		}
+	}
+	if found == nil {
+		return models.DoseRange{}, errs.NotFound("dose range not found", nil)
+	}
+	return *found, nil
+}
+
+func TestCheckDose(t *testing.T) {
+	s := &PrescriptionService{prescriptions: fakeDoseRanges{ranges: []models.DoseRange{
+		{Drug: "Amoxicillin", MinMgPerKg: 10, MaxMgPerKg: 20},
+		{Drug: "Meloxicam", Species: "dog", MinMgPerKg: 0.1, MaxMgPerKg: 0.2},
+		{Drug: "Meloxicam", Species: "cat", MinMgPerKg: 0.05, MaxMgPerKg: 0.1},
+	}}}
+	dog := models.Pet{AnimalType: "dog", Weight: 20}
+	cat := models.Pet{AnimalType: "cat", Weight: 4}
+
+	tests := []struct {
+		name    string
+		pet     models.Pet
+		drug    string
+		dose    float64
+		unit    string
+		status  string
+		mgPerKg float64
+	}{
+		{"mg/kg in range", dog, "Amoxicillin", 15, "mg/kg", models.DoseCheckOK, 15},
+		{"mg converted by weight", dog, "Amoxicillin", 300, "mg", models.DoseCheckOK, 15},
+		{"range bounds are inclusive", dog, "amoxicillin", 200, "mg", models.DoseCheckOK, 10},
+		{"below range", dog, "Amoxicillin", 100, "mg", models.DoseCheckOutOfRange, 5},
+		{"above range", cat, "Amoxicillin", 100, "mg", models.DoseCheckOutOfRange, 25},
+		{"species range wins", cat, "Meloxicam", 0.2, "mg/kg", models.DoseCheckOutOfRange, 0.2},
+		{"range of other species", dog, "Meloxicam", 0.2, "mg/kg", models.DoseCheckOK, 0.2},
+		{"no range for species", models.Pet{AnimalType: "rabbit", Weight: 2}, "Meloxicam", 0.2, "mg/kg",
+			models.DoseCheckUnchecked, 0},
+		{"no range for drug", dog, "Unknownol", 10, "mg/kg", models.DoseCheckUnchecked, 0},
+		{"unknown weight", models.Pet{AnimalType: "dog"}, "Amoxicillin", 300, "mg", models.DoseCheckUnchecked, 0},
+		{"unit can not be converted", dog, "Amoxicillin", 1, "tablet", models.DoseCheckUnchecked, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := s.checkDose(tt.pet, models.Prescription{Drug: tt.drug, Dose: tt.dose, Unit: tt.unit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if check.Status != tt.status {
+				t.Errorf("status = %q, want %q (%s)", check.Status, tt.status, check.Message)
+			}
+			if check.MgPerKg != tt.mgPerKg {
+				t.Errorf("mg/kg = %g, want %g", check.MgPerKg, tt.mgPerKg)
+			}
+			if check.Status != models.DoseCheckOK && check.Message == "" {
+				t.Error("message is empty")
+			}
+		})
+	}
+}
+
+func TestRequireDoseInRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		override bool
+		code     string
+	}{
+		{"ok", models.DoseCheckOK, false, ""},
+		{"out of range", models.DoseCheckOutOfRange, false, validation.CodeOutOfRange},
+		{"out of range confirmed", models.DoseCheckOutOfRange, true, ""},
+		{"unchecked", models.DoseCheckUnchecked, false, validation.CodeDoseUnchecked},
+		{"unchecked confirmed", models.DoseCheckUnchecked, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireDoseInRange(models.Prescription{DoseOverride: tt.override}, models.DoseCheck{Status: tt.status})
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var fieldErrs validation.Errors
+			if !errors.As(err, &fieldErrs) || len(fieldErrs) != 1 {
+				t.Fatalf("err = %v, want one field error", err)
+			}
+			if fieldErrs[0].Field != "dose" || fieldErrs[0].Code != tt.code {
+				t.Errorf("error = %s %s, want dose %s", fieldErrs[0].Field, fieldErrs[0].Code, tt.code)
+			}
+		})
+	}
+}
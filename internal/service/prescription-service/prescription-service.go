@@ -0,0 +1,172 @@
+package prescriptionservice
+
+import (
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+type PrescriptionService struct {
+	log           *logging.Logger
+	storage       storage.Info
+	prescriptions storage.Prescription
+}
+
+func New(log *logging.Logger, storage storage.Info, prescriptions storage.Prescription) *PrescriptionService {
+	return &PrescriptionService{log: log, storage: storage, prescriptions: prescriptions}
+}
+
+// CreatePrescription checks dose against pet weight and saves prescription of medical entry
+func (s *PrescriptionService) CreatePrescription(prescription models.Prescription) (models.Prescription, error) {
+	pet, err := s.prescriptions.GetPetByMedEntry(prescription.MedicalEntryID)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+
+	normalizeDates(&prescription)
+	check, err := s.checkDose(pet, prescription)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	if err := requireDoseInRange(prescription, check); err != nil {
+		return models.Prescription{}, err
+	}
+
+	id, err := s.prescriptions.CreatePrescription(prescription)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+
+	created, err := s.prescriptions.GetPrescription(id)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	created.DoseCheck = &check
+	return created, nil
+}
+
+func (s *PrescriptionService) GetPrescription(entryID, id uint) (models.Prescription, error) {
+	prescription, err := s.entryPrescription(entryID, id)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+
+	pet, err := s.prescriptions.GetPetByMedEntry(entryID)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	if err := s.withDoseChecks(pet, []models.Prescription{prescription}); err != nil {
+		return models.Prescription{}, err
+	}
+	return prescription, nil
+}
+
+func (s *PrescriptionService) GetPrescriptions(entryID uint) ([]models.Prescription, error) {
+	pet, err := s.prescriptions.GetPetByMedEntry(entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	prescriptions, err := s.prescriptions.GetPrescriptions(entryID)
+	if err != nil {
+		return nil, err
+	}
+	return prescriptions, s.withDoseChecks(pet, prescriptions)
+}
+
+// UpdatePrescription replaces prescription. Dose is checked against current pet weight again
+func (s *PrescriptionService) UpdatePrescription(prescription models.Prescription) (models.Prescription, error) {
+	if _, err := s.entryPrescription(prescription.MedicalEntryID, prescription.ID); err != nil {
+		return models.Prescription{}, err
+	}
+
+	pet, err := s.prescriptions.GetPetByMedEntry(prescription.MedicalEntryID)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+
+	normalizeDates(&prescription)
+	check, err := s.checkDose(pet, prescription)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	if err := requireDoseInRange(prescription, check); err != nil {
+		return models.Prescription{}, err
+	}
+
+	if err := s.prescriptions.UpdatePrescription(prescription); err != nil {
+		return models.Prescription{}, err
+	}
+
+	updated, err := s.prescriptions.GetPrescription(prescription.ID)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	updated.DoseCheck = &check
+	return updated, nil
+}
+
+func (s *PrescriptionService) DeletePrescription(entryID, id uint) error {
+	if _, err := s.entryPrescription(entryID, id); err != nil {
+		return err
+	}
+	return s.prescriptions.DeletePrescription(id)
+}
+
+// GetActiveMedications returns prescriptions of pet active today
+func (s *PrescriptionService) GetActiveMedications(petID uint) ([]models.Prescription, error) {
+	pet, err := s.storage.GetPet(models.Pet{ID: petID})
+	if err != nil {
+		return nil, err
+	}
+
+	prescriptions, err := s.prescriptions.GetActivePrescriptions(petID, time.Now().Format(models.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+	return prescriptions, s.withDoseChecks(pet, prescriptions)
+}
+
+// entryPrescription returns prescription only if it belongs to medical entry
+func (s *PrescriptionService) entryPrescription(entryID, id uint) (models.Prescription, error) {
+	prescription, err := s.prescriptions.GetPrescription(id)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	if prescription.MedicalEntryID != entryID {
+		return models.Prescription{}, errs.NotFound("prescription not found", nil)
+	}
+	return prescription, nil
+}
+
+func (s *PrescriptionService) withDoseChecks(pet models.Pet, prescriptions []models.Prescription) error {
+	for i := range prescriptions {
+		check, err := s.checkDose(pet, prescriptions[i])
+		if err != nil {
+			return err
+		}
+		prescriptions[i].DoseCheck = &check
+	}
+	return nil
+}
+
+// normalizeDates fills end date from duration or duration from end date
+func normalizeDates(prescription *models.Prescription) {
+	start, err := time.Parse(models.DateLayout, prescription.StartDate)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case prescription.EndDate == "" && prescription.DurationDays > 0:
+		end := start.AddDate(0, 0, int(prescription.DurationDays)-1)
+		prescription.EndDate = end.Format(models.DateLayout)
+	case prescription.EndDate != "" && prescription.DurationDays == 0:
+		if end, err := time.Parse(models.DateLayout, prescription.EndDate); err == nil {
+			prescription.DurationDays = uint(end.Sub(start).Hours()/24) + 1
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package reportservice
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+const (
+	fontFamily = "main"
+	lineHeight = 5.0
+	cellPad    = 1.0
+)
+
+var entryColumns = []struct {
+	title string
+	width float64
+}{
+	{"Дата", 22},
+	{"Описание", 44},
+	{"Заболевание", 36},
+	{"Вакцинации", 34},
+	{"Рекомендации", 44},
+}
+
+var genders = map[string]string{"Male": "самец", "Female": "самка"}
+
+type pdfRenderer struct {
+	pdf *fpdf.Fpdf
+}
+
+func renderPetRecord(w io.Writer, record models.PetRecord, clinic config.ClinicConfig, fonts pdfFonts) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fonts.regular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fonts.bold)
+	pdf.SetTitle(fmt.Sprintf("Медицинская карта: %s", record.Pet.Name), true)
+	pdf.SetAuthor(clinic.Name, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%s · стр. %d/{nb}", clinic.Name, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	r := &pdfRenderer{pdf: pdf}
+	pdf.AddPage()
+	r.header(clinic)
+	r.title(fmt.Sprintf("Медицинская карта № %d", record.Record.ID))
+	r.section("Питомец")
+	r.fields([][2]string{
+		{"Кличка", record.Pet.Name},
+		{"Вид", record.Pet.AnimalType},
+		{"Пол", translate(genders, record.Pet.Gender)},
+		{"Дата рождения", birthDate(record.Pet)},
+		{"Возраст", age(record.Pet)},
+		{"Вес, кг", floatOrEmpty(record.Pet.Weight)},
+		{"Состояние", record.Pet.Condition},
+		{"Поведение", record.Pet.Behavior},
+	})
+	r.section("Владелец")
+	r.fields([][2]string{
+		{"ФИО", record.Owner.FullName},
+		{"Телефон", record.Owner.Phone},
+		{"Email", record.Owner.Email},
+	})
+	r.section("Лечащий врач")
+	r.fields([][2]string{
+		{"ФИО", record.Vet.FullName},
+		{"Должность", record.Vet.Position},
+		{"Телефон", record.Vet.Phone},
+	})
+	r.section("История приёмов")
+	r.entries(record.Entries)
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to render pdf: %w", err)
+	}
+	return pdf.Output(w)
+}
+
+func (r *pdfRenderer) header(clinic config.ClinicConfig) {
+	left, top, _, _ := r.pdf.GetMargins()
+	textX := left
+	if clinic.LogoPath != "" {
+		r.pdf.ImageOptions(clinic.LogoPath, left, top, 0, 18, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+		textX = left + 25
+	}
+
+	r.pdf.SetXY(textX, top)
+	r.pdf.SetFont(fontFamily, "B", 14)
+	r.pdf.CellFormat(0, 7, clinic.Name, "", 1, "L", false, 0, "")
+
+	r.pdf.SetFont(fontFamily, "", 9)
+	for _, line := range []string{clinic.Address, clinic.Phone, clinic.Email} {
+		if line != "" {
+			r.pdf.SetX(textX)
+			r.pdf.CellFormat(0, 4.5, line, "", 1, "L", false, 0, "")
+		}
+	}
+
+	r.pdf.SetY(top + 22)
+	r.pdf.Line(left, r.pdf.GetY(), 210-left, r.pdf.GetY())
+	r.pdf.Ln(4)
+}
+
+func (r *pdfRenderer) title(text string) {
+	r.pdf.SetFont(fontFamily, "B", 16)
+	r.pdf.CellFormat(0, 9, text, "", 1, "C", false, 0, "")
+	r.pdf.SetFont(fontFamily, "", 9)
+	r.pdf.CellFormat(0, 5, "Сформировано "+time.Now().Format("02.01.2006 15:04"), "", 1, "C", false, 0, "")
+	r.pdf.Ln(3)
+}
+
+func (r *pdfRenderer) section(text string) {
+	r.pdf.Ln(2)
+	r.pdf.SetFont(fontFamily, "B", 12)
+	r.pdf.CellFormat(0, 7, text, "B", 1, "L", false, 0, "")
+	r.pdf.Ln(1)
+}
+
+// fields prints label: value lines, empty values are skipped
+func (r *pdfRenderer) fields(pairs [][2]string) {
+	for _, pair := range pairs {
+		if strings.TrimSpace(pair[1]) == "" {
+			continue
+		}
+		r.pdf.SetFont(fontFamily, "B", 10)
+		r.pdf.CellFormat(40, 6, pair[0]+":", "", 0, "L", false, 0, "")
+		r.pdf.SetFont(fontFamily, "", 10)
+		r.pdf.MultiCell(0, 6, pair[1], "", "L", false)
+	}
+}
+
+func (r *pdfRenderer) entries(entries []models.MedicalEntry) {
+	if len(entries) == 0 {
+		r.pdf.SetFont(fontFamily, "", 10)
+		r.pdf.CellFormat(0, 6, "Записей нет", "", 1, "L", false, 0, "")
+		return
+	}
+
+	r.entriesHeader()
+	r.pdf.SetFont(fontFamily, "", 9)
+	for _, entry := range entries {
+		r.tableRow([]string{
+			formatDate(entry.EntryDate), entry.Description, entry.Disease, entry.Vaccinations, entry.Recommendation,
+		})
+	}
+}
+
+func (r *pdfRenderer) entriesHeader() {
+	r.pdf.SetFont(fontFamily, "B", 9)
+	r.pdf.SetFillColor(230, 230, 230)
+	for _, column := range entryColumns {
+		r.pdf.CellFormat(column.width, 7, column.title, "1", 0, "C", true, 0, "")
+	}
+	r.pdf.Ln(-1)
+	r.pdf.SetFont(fontFamily, "", 9)
+}
+
+// tableRow draws row with wrapped cells of the same height. Row is moved to next page if it does not fit
+func (r *pdfRenderer) tableRow(cells []string) {
+	maxLines := 1
+	lines := make([][]string, len(cells))
+	for i, cell := range cells {
+		lines[i] = r.pdf.SplitText(cell, entryColumns[i].width-2*cellPad)
+		if len(lines[i]) > maxLines {
+			maxLines = len(lines[i])
+		}
+	}
+	height := float64(maxLines)*lineHeight + 2*cellPad
+
+	_, pageHeight := r.pdf.GetPageSize()
+	_, _, _, bottom := r.pdf.GetMargins()
+	if r.pdf.GetY()+height > pageHeight-bottom {
+		r.pdf.AddPage()
+		r.entriesHeader()
+	}
+
+	x, y := r.pdf.GetXY()
+	for i, cellLines := range lines {
+		width := entryColumns[i].width
+		r.pdf.Rect(x, y, width, height, "D")
+		for j, line := range cellLines {
+			r.pdf.SetXY(x+cellPad, y+cellPad+float64(j)*lineHeight)
+			r.pdf.CellFormat(width-2*cellPad, lineHeight, line, "", 0, "L", false, 0, "")
+		}
+		x += width
+	}
+	left, _, _, _ := r.pdf.GetMargins()
+	r.pdf.SetXY(left, y+height)
+}
+
+func formatDate(value string) string {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format("02.01.2006")
+		}
+	}
+	return value
+}
+
+func translate(dict map[string]string, value string) string {
+	if translated, ok := dict[value]; ok {
+		return translated
+	}
+	return value
+}
+
+func birthDate(pet models.Pet) string {
+	if pet.BirthDate == "" {
+		return ""
+	}
+	parsed, err := time.Parse(models.DateLayout, pet.BirthDate)
+	if err != nil {
+		return pet.BirthDate
+	}
+	if pet.BirthDateEstimated {
+		return parsed.Format("02.01.2006") + " (примерно)"
+	}
+	return parsed.Format("02.01.2006")
+}
+
+func age(pet models.Pet) string {
+	if pet.BirthDate == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d л. %d мес.", pet.Age, pet.AgeMonths)
+}
+
+func floatOrEmpty(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,67 @@
+package reportservice
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+type petRecords interface {
+	GetPetRecord(petID uint) (models.PetRecord, error)
+}
+
+type ReportService struct {
+	log     *logging.Logger
+	records petRecords
+	clinic  config.ClinicConfig
+	pdf     config.PDFConfig
+
+	fontsOnce sync.Once
+	fonts     pdfFonts
+	fontsErr  error
+}
+
+type pdfFonts struct {
+	regular []byte
+	bold    []byte
+}
+
+func New(log *logging.Logger, records petRecords, clinic config.ClinicConfig, pdf config.PDFConfig) *ReportService {
+	return &ReportService{log: log, records: records, clinic: clinic, pdf: pdf}
+}
+
+// PetRecordPDF writes printable medical history of pet
+func (s *ReportService) PetRecordPDF(petID uint, w io.Writer) error {
+	record, err := s.records.GetPetRecord(petID)
+	if err != nil {
+		return err
+	}
+
+	fonts, err := s.loadFonts()
+	if err != nil {
+		return err
+	}
+
+	return renderPetRecord(w, record, s.clinic, fonts)
+}
+
+// loadFonts reads fonts once. Core pdf fonts have no cyrillic, so TrueType fonts are required
+func (s *ReportService) loadFonts() (pdfFonts, error) {
+	s.fontsOnce.Do(func() {
+		s.fonts.regular, s.fontsErr = os.ReadFile(s.pdf.FontPath)
+		if s.fontsErr != nil {
+			s.fontsErr = fmt.Errorf("failed to read pdf font, set PDF_FONT_PATH: %w", s.fontsErr)
+			return
+		}
+		s.fonts.bold, s.fontsErr = os.ReadFile(s.pdf.BoldFontPath)
+		if s.fontsErr != nil {
+			s.fontsErr = fmt.Errorf("failed to read pdf bold font, set PDF_FONT_BOLD_PATH: %w", s.fontsErr)
+		}
+	})
+	return s.fonts, s.fontsErr
+}
@@ -0,0 +1,177 @@
+package researchservice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+// transitions lists allowed next stages. Withdrawn pet can be screened again, completed is final
+var transitions = map[string][]string{
+	models.ResearchStatusCandidate: {models.ResearchStatusConsented, models.ResearchStatusWithdrawn},
+	models.ResearchStatusConsented: {models.ResearchStatusEnrolled, models.ResearchStatusWithdrawn},
+	models.ResearchStatusEnrolled:  {models.ResearchStatusActive, models.ResearchStatusWithdrawn},
+	models.ResearchStatusActive:    {models.ResearchStatusCompleted, models.ResearchStatusWithdrawn},
+	models.ResearchStatusWithdrawn: {models.ResearchStatusCandidate},
+	models.ResearchStatusCompleted: {},
+}
+
+// CanTransition reports whether pet in from stage can move to stage to
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// pathTo returns shortest chain of stages leading from one stage to another, without from
+func pathTo(from, to string) ([]string, bool) {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		stage := queue[0]
+		queue = queue[1:]
+		if stage == to {
+			var path []string
+			for ; stage != from; stage = prev[stage] {
+				path = append([]string{stage}, path...)
+			}
+			return path, true
+		}
+		for _, next := range transitions[stage] {
+			if _, seen := prev[next]; !seen {
+				prev[next] = stage
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil, false
+}
+
+type ResearchService struct {
+	log      *logging.Logger
+	storage  storage.Info
+	research storage.Research
+	studies  storage.Study
+	tx       storage.Transactor
+}
+
+func New(
+	log *logging.Logger, storage storage.Info, research storage.Research, studies storage.Study,
+	tx storage.Transactor,
+) *ResearchService {
+	return &ResearchService{log: log, storage: storage, research: research, studies: studies, tx: tx}
+}
+
+func (s *ResearchService) GetResearchStatus(petID uint) (models.ResearchStatusDTO, error) {
+	pet, err := s.storage.GetPet(models.Pet{ID: petID})
+	if err != nil {
+		return models.ResearchStatusDTO{}, err
+	}
+
+	history, err := s.research.GetResearchTransitions(petID)
+	if err != nil {
+		return models.ResearchStatusDTO{}, err
+	}
+
+	return models.ResearchStatusDTO{PetID: petID, Status: pet.ResearchStatus, History: history}, nil
+}
+
+// TransitionResearchStatus moves pet to next stage and records transition. Stages that can not
+// be reached from current one are rejected with conflict. Pet is enrolled only by study enrollment,
+// active & completed stages need active enrollment
+func (s *ResearchService) TransitionResearchStatus(
+	petID uint, input models.ResearchTransitionDTO,
+) (models.ResearchStatusDTO, error) {
+	if input.Status == models.ResearchStatusEnrolled {
+		return models.ResearchStatusDTO{}, errs.Conflict(
+			"pet is enrolled with POST /info/v1/studies/:id/enrollments, not with research status", nil)
+	}
+
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		pet, err := tx.GetPet(models.Pet{ID: petID})
+		if err != nil {
+			return err
+		}
+
+		if !CanTransition(pet.ResearchStatus, input.Status) {
+			allowed := strings.Join(transitions[pet.ResearchStatus], ", ")
+			if allowed == "" {
+				allowed = "none"
+			}
+			return errs.Conflict(fmt.Sprintf("research status can not change from %s to %s, allowed: %s",
+				pet.ResearchStatus, input.Status, allowed), nil)
+		}
+
+		if input.Status == models.ResearchStatusActive || input.Status == models.ResearchStatusCompleted {
+			active, err := tx.CountActiveEnrollments(petID)
+			if err != nil {
+				return err
+			}
+			if active == 0 {
+				return errs.Conflict(fmt.Sprintf("pet without active study enrollment can not be %s",
+					input.Status), nil)
+			}
+		}
+
+		return transition(tx, pet, input.Status, input.Reason, input.ActorID)
+	})
+	if err != nil {
+		return models.ResearchStatusDTO{}, err
+	}
+
+	s.log.WithField("op", "ResearchService.TransitionResearchStatus").
+		Infof("pet %d research status changed to %s by vet %d", petID, input.Status, input.ActorID)
+	return s.GetResearchStatus(petID)
+}
+
+// importedReason is reason of transitions replayed by import
+const importedReason = "imported"
+
+// ImportResearchStatus moves new candidate pet to historical stage of import. Every stage on the way
+// is recorded in history. Study enrollments are not imported, so imported enrolled, active &
+// completed pets have none
+func ImportResearchStatus(tx storage.Tx, petID uint, to string, actorID uint) error {
+	path, ok := pathTo(models.ResearchStatusCandidate, to)
+	if !ok {
+		return errs.Validation("unknown research status "+to, nil)
+	}
+
+	pet := models.Pet{ID: petID, ResearchStatus: models.ResearchStatusCandidate}
+	for _, stage := range path {
+		if err := transition(tx, pet, stage, importedReason, actorID); err != nil {
+			return err
+		}
+		pet.ResearchStatus = stage
+	}
+	return nil
+}
+
+// transition moves pet to stage & records it. Withdrawn pet leaves all its studies
+func transition(tx storage.Tx, pet models.Pet, to, reason string, actorID uint) error {
+	if err := tx.UpdateResearchStatus(pet.ID, pet.ResearchStatus, to); err != nil {
+		return err
+	}
+
+	_, err := tx.CreateResearchTransition(models.ResearchTransition{
+		PetID:      pet.ID,
+		FromStatus: pet.ResearchStatus,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorID:    actorID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if to == models.ResearchStatusWithdrawn {
+		return tx.WithdrawPetEnrollments(pet.ID, reason)
+	}
+	return nil
+}
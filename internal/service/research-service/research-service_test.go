@@ -0,0 +1,179 @@
+package researchservice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+func TestPathTo(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     []string
+		ok       bool
+	}{
+		{models.ResearchStatusCandidate, models.ResearchStatusEnrolled,
+			[]string{models.ResearchStatusConsented, models.ResearchStatusEnrolled}, true},
+		{models.ResearchStatusConsented, models.ResearchStatusEnrolled, []string{models.ResearchStatusEnrolled}, true},
+		{models.ResearchStatusWithdrawn, models.ResearchStatusEnrolled, []string{
+			models.ResearchStatusCandidate, models.ResearchStatusConsented, models.ResearchStatusEnrolled,
+		}, true},
+		{models.ResearchStatusEnrolled, models.ResearchStatusEnrolled, nil, true},
+		{models.ResearchStatusCompleted, models.ResearchStatusEnrolled, nil, false},
+		{"unknown", models.ResearchStatusEnrolled, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			got, ok := pathTo(tt.from, tt.to)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pathTo = %v %v, want %v %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// fakeResearch keeps research state of one pet
+type fakeResearch struct {
+	storage.Tx
+	pet                  models.Pet
+	activeEnrollments    uint
+	transitions          []models.ResearchTransition
+	enrollmentsWithdrawn bool
+}
+
+func (f *fakeResearch) WithTx(fn func(tx storage.Tx) error) error {
+	// changes of failed transaction are rolled back
+	saved := *f
+	saved.transitions = append([]models.ResearchTransition(nil), f.transitions...)
+	if err := fn(f); err != nil {
+		*f = saved
+		return err
+	}
+	return nil
+}
+
+func (f *fakeResearch) GetPet(pet models.Pet) (models.Pet, error) {
+	if pet.ID != f.pet.ID {
+		return models.Pet{}, errs.NotFound("pet not found", nil)
+	}
+	return f.pet, nil
+}
+
+func (f *fakeResearch) UpdateResearchStatus(_ uint, from, to string) error {
+	if f.pet.ResearchStatus != from {
+		return errs.Conflict("research status changed", nil)
+	}
+	f.pet.ResearchStatus = to
+	return nil
+}
+
+func (f *fakeResearch) CreateResearchTransition(transition models.ResearchTransition) (uint, error) {
+	f.transitions = append(f.transitions, transition)
+	return uint(len(f.transitions)), nil
+}
+
+func (f *fakeResearch) GetResearchTransitions(uint) ([]models.ResearchTransition, error) {
+	return f.transitions, nil
+}
+
+func (f *fakeResearch) CountActiveEnrollments(uint) (uint, error) {
+	return f.activeEnrollments, nil
+}
+
+func (f *fakeResearch) WithdrawPetEnrollments(uint, string) error {
+	f.enrollmentsWithdrawn = true
+	f.activeEnrollments = 0
+	return nil
+}
+
+func TestTransitionResearchStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        string
+		enrollments uint
+		input       models.ResearchTransitionDTO
+		wantErr     error
+	}{
+		{"candidate consents", models.ResearchStatusCandidate, 0,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusConsented}, nil},
+		{"candidate can not skip to active", models.ResearchStatusCandidate, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusActive}, errs.ErrConflict},
+		{"candidate can not complete", models.ResearchStatusCandidate, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusCompleted}, errs.ErrConflict},
+		{"enrolled only by study enrollment", models.ResearchStatusConsented, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusEnrolled}, errs.ErrConflict},
+		{"enrolled becomes active", models.ResearchStatusEnrolled, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusActive}, nil},
+		{"active needs enrollment", models.ResearchStatusEnrolled, 0,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusActive}, errs.ErrConflict},
+		{"active completes", models.ResearchStatusActive, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusCompleted}, nil},
+		{"completed needs enrollment", models.ResearchStatusActive, 0,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusCompleted}, errs.ErrConflict},
+		{"active can not go back", models.ResearchStatusActive, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusConsented}, errs.ErrConflict},
+		{"completed is final", models.ResearchStatusCompleted, 0,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusWithdrawn, Reason: "owner moved"},
+			errs.ErrConflict},
+		{"active withdraws", models.ResearchStatusActive, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusWithdrawn, Reason: "owner moved"}, nil},
+		{"withdrawal needs reason", models.ResearchStatusActive, 1,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusWithdrawn}, errs.ErrValidation},
+		{"withdrawn is screened again", models.ResearchStatusWithdrawn, 0,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusCandidate}, nil},
+		{"same status", models.ResearchStatusConsented, 0,
+			models.ResearchTransitionDTO{Status: models.ResearchStatusConsented}, errs.ErrConflict},
+		{"unknown status", models.ResearchStatusCandidate, 0,
+			models.ResearchTransitionDTO{Status: "paused"}, errs.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeResearch{
+				pet:               models.Pet{ID: 1, ResearchStatus: tt.from},
+				activeEnrollments: tt.enrollments,
+			}
+			s := New(logging.NewLogger(new(bool), new(bool)), fake, fake, fake, fake)
+			input := tt.input
+			input.ActorID = 3
+
+			// handler validates input before service
+			err := validation.ValidateResearchTransition(input)
+			var got models.ResearchStatusDTO
+			if err == nil {
+				got, err = s.TransitionResearchStatus(1, input)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if fake.pet.ResearchStatus != tt.from || len(fake.transitions) != 0 {
+					t.Errorf("rejected transition changed status to %s with history %+v",
+						fake.pet.ResearchStatus, fake.transitions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := models.ResearchTransition{
+				PetID: 1, FromStatus: tt.from, ToStatus: input.Status, Reason: input.Reason, ActorID: 3,
+			}
+			if got.Status != input.Status || len(got.History) != 1 || got.History[0] != want {
+				t.Errorf("status = %s with history %+v, want %s with %+v", got.Status, got.History, input.Status, want)
+			}
+			if withdrawn := input.Status == models.ResearchStatusWithdrawn; fake.enrollmentsWithdrawn != withdrawn {
+				t.Errorf("enrollments withdrawn = %v, want %v", fake.enrollmentsWithdrawn, withdrawn)
+			}
+		})
+	}
+}
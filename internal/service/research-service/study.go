@@ -0,0 +1,159 @@
+package researchservice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+func (s *ResearchService) CreateStudy(study models.Study) (models.Study, error) {
+	id, err := s.studies.CreateStudy(study)
+	if err != nil {
+		return models.Study{}, err
+	}
+	return s.studies.GetStudy(id)
+}
+
+func (s *ResearchService) GetStudy(id uint) (models.Study, error) {
+	return s.studies.GetStudy(id)
+}
+
+func (s *ResearchService) GetStudies() ([]models.Study, error) {
+	return s.studies.GetStudies()
+}
+
+func (s *ResearchService) UpdateStudy(study models.Study) (models.Study, error) {
+	if err := s.studies.UpdateStudy(study); err != nil {
+		return models.Study{}, err
+	}
+	return s.studies.GetStudy(study.ID)
+}
+
+// EnrollPet adds pet to study arm. Pet research status is moved to enrolled through intermediate
+// stages, pet already enrolled in another study keeps its status
+func (s *ResearchService) EnrollPet(studyID uint, input models.EnrollingPetDTO) (models.Enrollment, error) {
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		study, err := tx.GetStudy(studyID)
+		if err != nil {
+			return err
+		}
+		if study.EndDate != "" && study.EndDate < time.Now().Format(models.DateLayout) {
+			return errs.Conflict(fmt.Sprintf("study %s ended on %s", study.ProtocolID, study.EndDate), nil)
+		}
+
+		pet, err := tx.GetPet(models.Pet{ID: input.PetID})
+		if err != nil {
+			return err
+		}
+
+		var path []string
+		if pet.ResearchStatus != models.ResearchStatusEnrolled && pet.ResearchStatus != models.ResearchStatusActive {
+			var ok bool
+			if path, ok = pathTo(pet.ResearchStatus, models.ResearchStatusEnrolled); !ok {
+				return errs.Conflict(fmt.Sprintf("pet with research status %s can not be enrolled",
+					pet.ResearchStatus), nil)
+			}
+		}
+
+		_, err = tx.CreateEnrollment(models.Enrollment{
+			StudyID:     studyID,
+			PetID:       input.PetID,
+			Arm:         input.Arm,
+			ConsentDate: input.ConsentDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("enrolled in study %s", study.ProtocolID)
+		for _, stage := range path {
+			if err := transition(tx, pet, stage, reason, input.ActorID); err != nil {
+				return err
+			}
+			pet.ResearchStatus = stage
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	s.log.WithField("op", "ResearchService.EnrollPet").
+		Infof("pet %d enrolled in study %d by vet %d", input.PetID, studyID, input.ActorID)
+	return s.studies.GetEnrollment(studyID, input.PetID)
+}
+
+// WithdrawPet ends enrollment of pet. Pet without other active enrollments becomes withdrawn
+func (s *ResearchService) WithdrawPet(
+	studyID, petID uint, input models.WithdrawingPetDTO,
+) (models.Enrollment, error) {
+	err := s.tx.WithTx(func(tx storage.Tx) error {
+		if err := tx.WithdrawEnrollment(studyID, petID, input.Reason); err != nil {
+			return err
+		}
+
+		active, err := tx.CountActiveEnrollments(petID)
+		if err != nil || active > 0 {
+			return err
+		}
+
+		pet, err := tx.GetPet(models.Pet{ID: petID})
+		if err != nil {
+			return err
+		}
+		if !CanTransition(pet.ResearchStatus, models.ResearchStatusWithdrawn) {
+			return nil
+		}
+		return transition(tx, pet, models.ResearchStatusWithdrawn, input.Reason, input.ActorID)
+	})
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	s.log.WithField("op", "ResearchService.WithdrawPet").
+		Infof("pet %d withdrawn from study %d by vet %d", petID, studyID, input.ActorID)
+	return s.studies.GetEnrollment(studyID, petID)
+}
+
+// GetCohort returns enrolled pets, withdrawn ones included, with medical entries made between
+// study start & end
+func (s *ResearchService) GetCohort(studyID uint) (models.CohortDTO, error) {
+	study, err := s.studies.GetStudy(studyID)
+	if err != nil {
+		return models.CohortDTO{}, err
+	}
+
+	enrollments, err := s.studies.GetEnrollments(studyID)
+	if err != nil {
+		return models.CohortDTO{}, err
+	}
+
+	filter := models.EntryReqFilter{DateFrom: &study.StartDate}
+	if study.EndDate != "" {
+		filter.DateTo = &study.EndDate
+	}
+
+	cohort := models.CohortDTO{Study: study, Members: make([]models.CohortMember, 0, len(enrollments))}
+	for _, enrollment := range enrollments {
+		pet, err := s.storage.GetPet(models.Pet{ID: enrollment.PetID})
+		if err != nil {
+			return models.CohortDTO{}, err
+		}
+
+		filter.PetID = &enrollment.PetID
+		entries, err := s.storage.GetMedEntries(filter)
+		if err != nil {
+			return models.CohortDTO{}, err
+		}
+		if entries == nil {
+			entries = []models.MedicalEntry{}
+		}
+
+		cohort.Members = append(cohort.Members, models.CohortMember{Enrollment: enrollment, Pet: pet, Entries: entries})
+	}
+
+	return cohort, nil
+}
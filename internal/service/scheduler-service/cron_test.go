@@ -0,0 +1,89 @@
+package schedulerservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"*/15 9-17 * * 1-5", false},
+		{"0 0 1,15 * *", false},
+		{"5/20 * * * *", false},
+		{"0 0 * * 7", false},
+		{"@daily", false},
+		{"@every 90s", false},
+		{"", true},
+		{"* * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"a * * * *", true},
+		{"@yearly", true},
+		{"@every 500ms", true},
+		{"@every soon", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSchedule(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-05-06 is Monday
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	now := at(5, 6, 10, 7)
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", now, at(5, 6, 10, 8)},
+		{"*/15 * * * *", now, at(5, 6, 10, 15)},
+		{"5/20 * * * *", now, at(5, 6, 10, 25)},
+		{"@hourly", now, at(5, 6, 11, 0)},
+		{"@daily", now, at(5, 7, 0, 0)},
+		{"@weekly", now, at(5, 12, 0, 0)},
+		{"@monthly", now, at(6, 1, 0, 0)},
+		{"30 9 * * *", now, at(5, 7, 9, 30)},
+		{"0 8 * * 6", now, at(5, 11, 8, 0)},
+		{"0 8 * * 7", now, at(5, 12, 8, 0)},
+		{"0 0 29 2 *", now, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week matches when both are restricted
+		{"0 0 13 * 5", now, at(5, 10, 0, 0)},
+		{"0 0 30 2 *", now, time.Time{}},
+		{"@every 90s", now.Add(30 * time.Second), now.Add(2 * time.Minute)},
+		// next run is after from even if from matches
+		{"7 10 * * *", now, at(5, 7, 10, 7)},
+		// time zone of from does not matter
+		{"0 12 * * *", now.In(time.FixedZone("UTC+3", 3*3600)), at(5, 6, 12, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package schedulerservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+)
+
+const (
+	// pollInterval is how often due jobs are checked
+	pollInterval = 15 * time.Second
+	// runRetention is how long run history is kept
+	runRetention = 30 * 24 * time.Hour
+)
+
+// ErrIdle is returned by job that had nothing to do. Such scheduled runs are not kept in history
+var ErrIdle = errors.New("nothing to do")
+
+// orphanedRunError is error of runs left running by replica that stopped during run
+const orphanedRunError = "replica stopped during run"
+
+// Job is periodic background task. Spec is cron expression, see ParseSchedule
+type Job struct {
+	Name        string
+	Description string
+	Spec        string
+	Run         func(ctx context.Context) error
+}
+
+// JobSource is implemented by services having background jobs
+type JobSource interface {
+	Jobs() []Job
+}
+
+type registeredJob struct {
+	Job
+	schedule Schedule
+}
+
+// SchedulerService runs registered jobs on schedule. Postgres advisory lock of job lets only one replica
+// run it, next run time is shared by replicas so every run happens once
+type SchedulerService struct {
+	log  *logging.Logger
+	jobs storage.Job
+	host string
+
+	mu         sync.Mutex
+	registered map[string]registeredJob
+	running    map[string]bool
+	ctx        context.Context
+}
+
+func New(log *logging.Logger, jobs storage.Job) *SchedulerService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	s := &SchedulerService{
+		log: log, jobs: jobs, host: host, registered: map[string]registeredJob{}, running: map[string]bool{},
+		ctx: context.Background(),
+	}
+	s.MustRegister(Job{
+		Name:        "scheduler.purge-runs",
+		Description: "Deletes job run history older than 30 days",
+		Spec:        "@daily",
+		Run:         s.purgeRuns,
+	})
+	return s
+}
+
+// Register adds job. Name should be unique, jobs registered after Run are not run
+func (s *SchedulerService) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job should have name & run function")
+	}
+	schedule, err := ParseSchedule(job.Spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule of job %s: %w", job.Name, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("schedule of job %s never fires", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.registered[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+	s.registered[job.Name] = registeredJob{Job: job, schedule: schedule}
+	return nil
+}
+
+// MustRegister is Register for jobs registered at startup
+func (s *SchedulerService) MustRegister(jobs ...Job) {
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Run runs due jobs until ctx is done
+func (s *SchedulerService) Run(ctx context.Context) {
+	log := s.log.WithField("op", "SchedulerService.Run")
+
+	s.mu.Lock()
+	s.ctx = ctx
+	jobs := make([]registeredJob, 0, len(s.registered))
+	for _, job := range s.registered {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		if err := s.jobs.SaveJobSchedule(job.Name, job.Spec, job.schedule.Next(time.Now())); err != nil {
+			log.WithField("job", job.Name).Error("failed to save job schedule: ", err)
+		}
+		s.failOrphanedRunsOnStart(ctx, job.Name)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		for _, job := range jobs {
+			if s.start(job.Name) {
+				go func(job registeredJob) {
+					defer s.finish(job.Name)
+					s.runIfDue(ctx, job)
+				}(job)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetJobs returns registered jobs with next run & last run
+func (s *SchedulerService) GetJobs() ([]models.ScheduledJob, error) {
+	saved, err := s.jobs.GetScheduledJobs()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.ScheduledJob, len(saved))
+	for _, job := range saved {
+		byName[job.Name] = job
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]models.ScheduledJob, 0, len(s.registered))
+	for name, registered := range s.registered {
+		job := byName[name]
+		job.Name, job.Description, job.Schedule = name, registered.Description, registered.Spec
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	return jobs, nil
+}
+
+func (s *SchedulerService) GetJobRuns(filter models.JobRunReqFilter) ([]models.JobRun, error) {
+	return s.jobs.GetJobRuns(filter)
+}
+
+// TriggerJob runs job now in background. Schedule of job is not changed.
+// Conflict is returned if job is running on any replica
+func (s *SchedulerService) TriggerJob(name string) (models.JobRun, error) {
+	s.mu.Lock()
+	job, ok := s.registered[name]
+	ctx := s.ctx
+	s.mu.Unlock()
+	if !ok {
+		return models.JobRun{}, errs.NotFound("job not found", nil)
+	}
+
+	if !s.start(name) {
+		return models.JobRun{}, errs.Conflict("job is running", nil)
+	}
+	unlock, locked, err := s.jobs.LockJob(ctx, name)
+	if err != nil || !locked {
+		s.finish(name)
+		if err != nil {
+			return models.JobRun{}, err
+		}
+		return models.JobRun{}, errs.Conflict("job is running on other replica", nil)
+	}
+	s.failOrphanedRuns(name)
+
+	run, err := s.createRun(name, models.JobTriggerManual)
+	if err != nil {
+		s.release(name, unlock)
+		s.finish(name)
+		return models.JobRun{}, err
+	}
+
+	go func() {
+		defer s.finish(name)
+		defer s.release(name, unlock)
+		s.execute(ctx, job, run)
+	}()
+
+	return run, nil
+}
+
+// runIfDue runs job if its shared next run time passed. Next run is saved before run, so crashed run
+// is not repeated in a loop
+func (s *SchedulerService) runIfDue(ctx context.Context, job registeredJob) {
+	log := s.log.WithField("op", "SchedulerService.runIfDue").WithField("job", job.Name)
+
+	unlock, locked, err := s.jobs.LockJob(ctx, job.Name)
+	if err != nil {
+		log.Error("failed to lock job: ", err)
+		return
+	}
+	if !locked {
+		return
+	}
+	defer s.release(job.Name, unlock)
+
+	next, err := s.jobs.GetJobNextRun(job.Name)
+	if errors.Is(err, errs.ErrNotFound) {
+		// schedule was not saved on start, job runs on next occurrence
+		if err := s.jobs.SaveJobSchedule(job.Name, job.Spec, job.schedule.Next(time.Now())); err != nil {
+			log.Error("failed to save job schedule: ", err)
+		}
+		return
+	}
+	if err != nil {
+		log.Error("failed to get next run: ", err)
+		return
+	}
+	now := time.Now()
+	if now.Before(next) {
+		return
+	}
+	if err := s.jobs.SetJobNextRun(job.Name, job.schedule.Next(now)); err != nil {
+		log.Error("failed to save next run: ", err)
+		return
+	}
+	s.failOrphanedRuns(job.Name)
+
+	run, err := s.createRun(job.Name, models.JobTriggerSchedule)
+	if err != nil {
+		log.Error("failed to create run: ", err)
+		return
+	}
+	s.execute(ctx, job, run)
+}
+
+func (s *SchedulerService) createRun(name, trigger string) (models.JobRun, error) {
+	run := models.JobRun{
+		JobName:   name,
+		Trigger:   trigger,
+		Status:    models.JobRunStatusRunning,
+		Host:      s.host,
+		StartedAt: time.Now(),
+	}
+	id, err := s.jobs.CreateJobRun(run)
+	if err != nil {
+		return models.JobRun{}, err
+	}
+	run.ID = id
+	return run, nil
+}
+
+// execute runs job & saves result. Panic of job fails run instead of crashing service
+func (s *SchedulerService) execute(ctx context.Context, job registeredJob, run models.JobRun) {
+	log := s.log.WithField("op", "SchedulerService.execute").WithField("job", job.Name)
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		return job.Run(ctx)
+	}()
+
+	// manual runs are kept, they were asked for
+	if errors.Is(err, ErrIdle) && run.Trigger == models.JobTriggerSchedule {
+		if err := s.jobs.DeleteJobRun(run.ID); err != nil {
+			log.Error("failed to delete idle run: ", err)
+		}
+		return
+	}
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Status = models.JobRunStatusSucceeded
+	if err != nil && !errors.Is(err, ErrIdle) {
+		log.Error("job failed: ", err)
+		run.Status = models.JobRunStatusFailed
+		run.Error = err.Error()
+	}
+	if err := s.jobs.FinishJobRun(run); err != nil {
+		log.Error("failed to save run: ", err)
+	}
+}
+
+// start marks job as running on this replica, false if it is already running
+func (s *SchedulerService) start(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[name] {
+		return false
+	}
+	s.running[name] = true
+	return true
+}
+
+func (s *SchedulerService) finish(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, name)
+}
+
+func (s *SchedulerService) release(name string, unlock func() error) {
+	if err := unlock(); err != nil {
+		s.log.WithField("job", name).Error("failed to unlock job: ", err)
+	}
+}
+
+// failOrphanedRuns fails runs of job left running. Caller holds lock of job & runs it on this replica,
+// so no other run of job is alive
+func (s *SchedulerService) failOrphanedRuns(name string) {
+	failed, err := s.jobs.FailRunningJobRuns(name, orphanedRunError, time.Now())
+	if err != nil {
+		s.log.WithField("job", name).Error("failed to fail orphaned runs: ", err)
+		return
+	}
+	if failed > 0 {
+		s.log.WithField("job", name).Warn("failed orphaned runs: ", failed)
+	}
+}
+
+// failOrphanedRunsOnStart fails runs of job left running if job is not running on other replica,
+// so runs of rarely scheduled jobs do not stay running until their next run
+func (s *SchedulerService) failOrphanedRunsOnStart(ctx context.Context, name string) {
+	if !s.start(name) {
+		return
+	}
+	defer s.finish(name)
+
+	unlock, locked, err := s.jobs.LockJob(ctx, name)
+	if err != nil {
+		s.log.WithField("job", name).Error("failed to lock job: ", err)
+		return
+	}
+	if !locked {
+		return
+	}
+	defer s.release(name, unlock)
+	s.failOrphanedRuns(name)
+}
+
+func (s *SchedulerService) purgeRuns(context.Context) error {
+	_, err := s.jobs.DeleteJobRuns(time.Now().Add(-runRetention))
+	return err
+}
@@ -1,9 +1,29 @@
 package service
 
 import (
+	"context"
+	"io"
+
+	"github.com/vet-clinic-back/info-service/internal/config"
 	"github.com/vet-clinic-back/info-service/internal/logging"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	appointmentservice "github.com/vet-clinic-back/info-service/internal/service/appointment-service"
+	availabilityservice "github.com/vet-clinic-back/info-service/internal/service/availability-service"
+	calendarservice "github.com/vet-clinic-back/info-service/internal/service/calendar-service"
+	exportservice "github.com/vet-clinic-back/info-service/internal/service/export-service"
+	fhirservice "github.com/vet-clinic-back/info-service/internal/service/fhir-service"
+	idempotencyservice "github.com/vet-clinic-back/info-service/internal/service/idempotency-service"
+	importservice "github.com/vet-clinic-back/info-service/internal/service/import-service"
 	infoservice "github.com/vet-clinic-back/info-service/internal/service/info-service"
+	labservice "github.com/vet-clinic-back/info-service/internal/service/lab-service"
+	measurementservice "github.com/vet-clinic-back/info-service/internal/service/measurement-service"
+	notificationservice "github.com/vet-clinic-back/info-service/internal/service/notification-service"
+	prescriptionservice "github.com/vet-clinic-back/info-service/internal/service/prescription-service"
+	reportservice "github.com/vet-clinic-back/info-service/internal/service/report-service"
+	researchservice "github.com/vet-clinic-back/info-service/internal/service/research-service"
+	schedulerservice "github.com/vet-clinic-back/info-service/internal/service/scheduler-service"
+	speciesservice "github.com/vet-clinic-back/info-service/internal/service/species-service"
+	vaccinationservice "github.com/vet-clinic-back/info-service/internal/service/vaccination-service"
 	"github.com/vet-clinic-back/info-service/internal/storage"
 )
 
@@ -11,8 +31,18 @@ type Info interface {
 	CreatePetWithCard(pet models.Pet, ownderID uint, vetID uint) (uint, error)
 	GetPet(pet models.Pet) (models.Pet, error)
 	GetPets(filter models.PetReqFilter) ([]models.OutputPetDTO, error)
+	IteratePets(filter models.PetReqFilter, fn func(models.OutputPetDTO) error) error
 	UpdatePet(pet models.Pet) (models.Pet, error)
 	DelPetWithCard(id uint) error
+	GetPetRecord(petID uint) (models.PetRecord, error)
+	CreatePetsBatch(items []models.PetWithCard, mode string) ([]models.BatchResult, error)
+	UpdatePetsBatch(pets []models.Pet, mode string) ([]models.BatchResult, error)
+	CreateAllergy(allergy models.Allergy) (models.Allergy, error)
+	GetAllergies(petID uint) ([]models.Allergy, error)
+	DeleteAllergy(petID, id uint) error
+	GetAllergenSynonyms() ([]models.AllergenSynonyms, error)
+	SaveAllergenSynonyms(group models.AllergenSynonyms) (models.AllergenSynonyms, error)
+	DeleteAllergenSynonyms(substance string) error
 	// owner is used at auth service
 	CreateOwner(user models.Owner) (uint, error)
 	GetOwner(owner models.Owner) (models.Owner, error)
@@ -22,19 +52,204 @@ type Info interface {
 }
 
 type MedInfo interface {
-	CreateMedEntry(entry models.MedicalEntry) (uint, error)
+	CreateMedEntry(entry models.CreatingMedEntry) (models.CreatedMedEntryDTO, error)
 	GetMedEntries(models.EntryReqFilter) ([]models.MedicalEntry, error)
+	IterateMedEntries(filters models.EntryReqFilter, fn func(models.MedicalEntry) error) error
+	CreateMedEntriesBatch(entries []models.CreatingMedEntry, mode string) ([]models.BatchResult, error)
+	UpdateMedEntriesBatch(entries []models.CreatingMedEntry, mode string) ([]models.BatchResult, error)
+}
+
+type Import interface {
+	CreateImport(job models.ImportJob, src io.Reader) (models.ImportJob, error)
+	CreateLocalImport(job models.ImportJob) (models.ImportJob, error)
+	GetImport(id uint) (models.ImportJob, error)
+	ResumeImport(id uint) (models.ImportJob, error)
+	RunImport(id uint) (models.ImportJob, error)
+}
+
+type Report interface {
+	PetRecordPDF(petID uint, w io.Writer) error
+}
+
+type FHIR interface {
+	Patient(petID uint) (models.FHIRPatient, error)
+	PatientEverything(petID uint, baseURL string) (models.FHIRBundle, error)
+}
+
+type Lab interface {
+	HandleMessage(msg []byte) []byte
+	GetLabResults(filter models.LabResultReqFilter) ([]models.LabResult, error)
+	GetLabReviews() ([]models.LabMessage, error)
+	ResolveLabReview(id, petID uint) (models.LabMessage, error)
+	DismissLabReview(id uint) (models.LabMessage, error)
+}
+
+type Vaccination interface {
+	CreateVaccine(vaccine models.Vaccine) (uint, error)
+	GetVaccines(species string) ([]models.Vaccine, error)
+	CreateVaccination(vaccination models.Vaccination) (models.Vaccination, error)
+	GetVaccinations(petID uint) ([]models.Vaccination, error)
+	GetDueVaccinations(filter models.VaccinationDueReqFilter) ([]models.DueVaccination, error)
+}
+
+type Prescription interface {
+	CreatePrescription(prescription models.Prescription) (models.Prescription, error)
+	GetPrescription(entryID, id uint) (models.Prescription, error)
+	GetPrescriptions(entryID uint) ([]models.Prescription, error)
+	UpdatePrescription(prescription models.Prescription) (models.Prescription, error)
+	DeletePrescription(entryID, id uint) error
+	GetActiveMedications(petID uint) ([]models.Prescription, error)
+	CreateDoseRange(doseRange models.DoseRange) (models.DoseRange, error)
+	GetDoseRanges(drug string) ([]models.DoseRange, error)
+	UpdateDoseRange(doseRange models.DoseRange) (models.DoseRange, error)
+	DeleteDoseRange(id uint) error
+}
+
+type Measurement interface {
+	CreateMeasurement(measurement models.Measurement) (models.Measurement, error)
+	GetMeasurements(petID uint) ([]models.Measurement, error)
+	GetWeightTrend(petID uint) (models.WeightTrend, error)
+}
+
+type Species interface {
+	CreateSpecies(species models.Species) (models.Species, error)
+	UpdateSpecies(species models.Species) (models.Species, error)
+	GetSpecies() ([]models.Species, error)
+	CreateBreed(breed models.Breed) (models.Breed, error)
+	UpdateBreed(breed models.Breed) (models.Breed, error)
+	GetBreeds(speciesCode string) ([]models.Breed, error)
+	GetUnmappedSpecies() ([]models.UnmappedSpecies, error)
+}
+
+type Research interface {
+	GetResearchStatus(petID uint) (models.ResearchStatusDTO, error)
+	TransitionResearchStatus(petID uint, input models.ResearchTransitionDTO) (models.ResearchStatusDTO, error)
+	CreateStudy(study models.Study) (models.Study, error)
+	GetStudy(id uint) (models.Study, error)
+	GetStudies() ([]models.Study, error)
+	UpdateStudy(study models.Study) (models.Study, error)
+	EnrollPet(studyID uint, input models.EnrollingPetDTO) (models.Enrollment, error)
+	WithdrawPet(studyID, petID uint, input models.WithdrawingPetDTO) (models.Enrollment, error)
+	GetCohort(studyID uint) (models.CohortDTO, error)
+}
+
+type Appointment interface {
+	BookAppointment(appointment models.Appointment) (models.Appointment, error)
+	GetAppointment(id uint) (models.Appointment, error)
+	GetAppointments(filter models.AppointmentReqFilter) ([]models.Appointment, error)
+	RescheduleAppointment(id uint, input models.ReschedulingAppointmentDTO) (models.Appointment, error)
+	CancelAppointment(id uint, input models.CancellingAppointmentDTO) (models.Appointment, error)
+	CheckInAppointment(id uint) (models.Appointment, error)
+	MarkNoShow(id uint) (models.Appointment, error)
+	CompleteAppointment(id uint) (models.Appointment, error)
+}
+
+type Availability interface {
+	GetClinics() ([]models.Clinic, error)
+	GetClinic(number string) (models.Clinic, error)
+	SaveClinic(clinic models.Clinic) (models.Clinic, error)
+	GetVetSchedule(vetID uint) ([]models.WorkingHours, error)
+	SaveVetSchedule(vetID uint, hours []models.WorkingHours) ([]models.WorkingHours, error)
+	CreateScheduleException(exception models.ScheduleException) (models.ScheduleException, error)
+	GetScheduleExceptions(vetID uint) ([]models.ScheduleException, error)
+	DeleteScheduleException(vetID, id uint) error
+	CreateTimeBlock(block models.TimeBlock) (models.TimeBlock, error)
+	GetTimeBlocks(vetID uint) ([]models.TimeBlock, error)
+	DeleteTimeBlock(vetID, id uint) error
+	GetAvailability(vetID uint, filter models.AvailabilityReqFilter) (models.AvailabilityDTO, error)
+}
+
+type Calendar interface {
+	CreateCalendarFeed(kind string, subjectID uint) (models.CalendarFeed, error)
+	GetCalendarFeeds(kind string, subjectID uint) ([]models.CalendarFeed, error)
+	RevokeCalendarFeed(kind string, subjectID, id uint) error
+	GetCalendar(token string) ([]byte, error)
+}
+
+type Notification interface {
+	GetNotificationPreferences(ownerID uint) (models.NotificationPreferences, error)
+	SaveNotificationPreferences(prefs models.NotificationPreferences) (models.NotificationPreferences, error)
+	CreateReminder(input models.CreatingReminderDTO) (models.Reminder, error)
+	GetReminder(id uint) (models.Reminder, error)
+	GetReminders(filter models.ReminderReqFilter) ([]models.Reminder, error)
+	CancelReminder(id uint) (models.Reminder, error)
+	GetNotifications(filter models.NotificationReqFilter) ([]models.Notification, error)
+}
+
+type Scheduler interface {
+	GetJobs() ([]models.ScheduledJob, error)
+	GetJobRuns(filter models.JobRunReqFilter) ([]models.JobRun, error)
+	TriggerJob(name string) (models.JobRun, error)
+	// Run runs registered jobs on schedule until ctx is done
+	Run(ctx context.Context)
+}
+
+type Export interface {
+	CreateExport(job models.ExportJob) (models.ExportJob, error)
+	GetExport(id uint) (models.ExportJob, error)
+	GetExportFile(id uint) (models.ExportJob, error)
+	WriteExportFile(id uint, w io.Writer) error
+}
+
+type Idempotency interface {
+	Start(scope, key, requestHash string) (models.IdempotencyRecord, bool, error)
+	Finish(rec models.IdempotencyRecord) error
+	Abort(scope, key string) error
 }
 
 type Service struct {
 	Info
 	MedInfo
+	Import
+	Report
+	FHIR
+	Lab
+	Vaccination
+	Prescription
+	Measurement
+	Species
+	Research
+	Appointment
+	Availability
+	Calendar
+	Notification
+	Export
+	Idempotency
+	Scheduler
 }
 
-func New(log *logging.Logger, stor storage.Info) *Service {
-	s := infoservice.New(log, stor)
+func New(log *logging.Logger, cfg *config.Config, stor *storage.Storage) *Service {
+	s := infoservice.New(log, stor.Info, stor.Transactor)
+	notifications := notificationservice.New(
+		log, stor.Info, stor.Notification, stor.Transactor, notificationservice.Notifiers(cfg.Notify), cfg.Notify,
+		cfg.Clinic,
+	)
+	idempotency := idempotencyservice.New(log, stor.Idempotency, cfg.Idempotency.TTL)
+	exports := exportservice.New(log, stor.Info, stor.Export, cfg.Export)
+
+	scheduler := schedulerservice.New(log, stor.Job)
+	for _, source := range []schedulerservice.JobSource{notifications, idempotency, exports} {
+		scheduler.MustRegister(source.Jobs()...)
+	}
+
 	return &Service{
-		Info:    s,
-		MedInfo: s,
+		Info:         s,
+		MedInfo:      s,
+		Import:       importservice.New(log, stor.Info, stor.Import, stor.Transactor, cfg.Import.Dir),
+		Report:       reportservice.New(log, s, cfg.Clinic, cfg.PDF),
+		FHIR:         fhirservice.New(log, s, stor.Info, cfg.FHIR),
+		Lab:          labservice.New(log, stor.Info, stor.Lab, stor.Transactor, cfg.Clinic),
+		Vaccination:  vaccinationservice.New(log, stor.Info, stor.Vaccination),
+		Prescription: prescriptionservice.New(log, stor.Info, stor.Prescription),
+		Measurement:  measurementservice.New(log, stor.Info, stor.Measurement),
+		Species:      speciesservice.New(log, stor.Info),
+		Research:     researchservice.New(log, stor.Info, stor.Research, stor.Study, stor.Transactor),
+		Appointment:  appointmentservice.New(log, stor.Info, stor.Appointment, stor.Transactor),
+		Availability: availabilityservice.New(log, stor.Info, stor.Availability, stor.Appointment, stor.Transactor),
+		Calendar:     calendarservice.New(log, stor.Info, stor.Calendar),
+		Notification: notifications,
+		Export:       exports,
+		Idempotency:  idempotency,
+		Scheduler:    scheduler,
 	}
 }
@@ -0,0 +1,114 @@
+package speciesservice
+
+import (
+	"errors"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+type SpeciesService struct {
+	log     *logging.Logger
+	species storage.Species
+}
+
+func New(log *logging.Logger, species storage.Species) *SpeciesService {
+	return &SpeciesService{log: log, species: species}
+}
+
+func (s *SpeciesService) CreateSpecies(species models.Species) (models.Species, error) {
+	if err := s.species.CreateSpecies(species); err != nil {
+		return models.Species{}, err
+	}
+	return s.species.FindSpecies(species.Code)
+}
+
+func (s *SpeciesService) UpdateSpecies(species models.Species) (models.Species, error) {
+	if err := s.species.UpdateSpecies(species); err != nil {
+		return models.Species{}, err
+	}
+	return s.species.FindSpecies(species.Code)
+}
+
+func (s *SpeciesService) GetSpecies() ([]models.Species, error) {
+	return s.species.GetSpecies()
+}
+
+func (s *SpeciesService) CreateBreed(breed models.Breed) (models.Breed, error) {
+	if err := s.requireSpecies(breed.SpeciesCode); err != nil {
+		return models.Breed{}, err
+	}
+	if err := s.species.CreateBreed(breed); err != nil {
+		return models.Breed{}, err
+	}
+	return breed, nil
+}
+
+func (s *SpeciesService) UpdateBreed(breed models.Breed) (models.Breed, error) {
+	if err := s.species.UpdateBreed(breed); err != nil {
+		return models.Breed{}, err
+	}
+	return breed, nil
+}
+
+func (s *SpeciesService) GetBreeds(speciesCode string) ([]models.Breed, error) {
+	if err := s.requireSpecies(speciesCode); err != nil {
+		return nil, err
+	}
+	return s.species.GetBreeds(speciesCode)
+}
+
+func (s *SpeciesService) GetUnmappedSpecies() ([]models.UnmappedSpecies, error) {
+	return s.species.GetUnmappedSpecies()
+}
+
+// requireSpecies checks that code is catalogue code, aliases are not accepted in paths
+func (s *SpeciesService) requireSpecies(code string) error {
+	species, err := s.species.FindSpecies(code)
+	if err != nil {
+		return err
+	}
+	if species.Code != code {
+		return errs.NotFound("species not found", nil)
+	}
+	return nil
+}
+
+// NormalizePet replaces animal_type & breed of pet with catalogue codes. Codes, aliases & localized names
+// are accepted, unknown values are rejected with invalid_enum
+func NormalizePet(stor storage.Species, pet models.Pet) (models.Pet, error) {
+	species, err := stor.FindSpecies(pet.AnimalType)
+	if errors.Is(err, errs.ErrNotFound) {
+		return models.Pet{}, validation.Errors{{
+			Field:   "animal_type",
+			Code:    validation.CodeInvalidEnum,
+			Message: "animal_type " + pet.AnimalType + " is not in species catalogue",
+		}}
+	}
+	if err != nil {
+		return models.Pet{}, err
+	}
+	pet.AnimalType = species.Code
+
+	if pet.Breed == "" {
+		return pet, nil
+	}
+
+	breed, err := stor.FindBreed(species.Code, pet.Breed)
+	if errors.Is(err, errs.ErrNotFound) {
+		return models.Pet{}, validation.Errors{{
+			Field:   "breed",
+			Code:    validation.CodeInvalidEnum,
+			Message: "breed " + pet.Breed + " is not in catalogue of " + species.Code,
+		}}
+	}
+	if err != nil {
+		return models.Pet{}, err
+	}
+	pet.Breed = breed.Code
+
+	return pet, nil
+}
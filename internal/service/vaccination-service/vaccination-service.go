@@ -0,0 +1,114 @@
+package vaccinationservice
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/vet-clinic-back/info-service/internal/logging"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+	"github.com/vet-clinic-back/info-service/internal/storage"
+	"github.com/vet-clinic-back/info-service/internal/validation"
+)
+
+// defaultUpcomingDays is window of upcoming vaccinations if within_days is not set
+const defaultUpcomingDays = 30
+
+type VaccinationService struct {
+	log          *logging.Logger
+	storage      storage.Info
+	vaccinations storage.Vaccination
+}
+
+func New(log *logging.Logger, storage storage.Info, vaccinations storage.Vaccination) *VaccinationService {
+	return &VaccinationService{log: log, storage: storage, vaccinations: vaccinations}
+}
+
+// CreateVaccine adds vaccine to catalogue. Species is replaced with species catalogue code
+func (s *VaccinationService) CreateVaccine(vaccine models.Vaccine) (uint, error) {
+	species, err := s.storage.FindSpecies(vaccine.Species)
+	if errors.Is(err, errs.ErrNotFound) {
+		return 0, validation.Errors{{
+			Field:   "species",
+			Code:    validation.CodeInvalidEnum,
+			Message: "species " + vaccine.Species + " is not in species catalogue",
+		}}
+	}
+	if err != nil {
+		return 0, err
+	}
+	vaccine.Species = species.Code
+
+	return s.vaccinations.CreateVaccine(vaccine)
+}
+
+func (s *VaccinationService) GetVaccines(species string) ([]models.Vaccine, error) {
+	return s.vaccinations.GetVaccines(species)
+}
+
+// CreateVaccination saves dose. Vaccine should be for pet species, entry should belong to pet card
+func (s *VaccinationService) CreateVaccination(vaccination models.Vaccination) (models.Vaccination, error) {
+	pet, err := s.storage.GetPet(models.Pet{ID: vaccination.PetID})
+	if err != nil {
+		return models.Vaccination{}, err
+	}
+
+	vaccine, err := s.vaccinations.GetVaccine(vaccination.VaccineID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return models.Vaccination{}, errs.ForeignKey("vaccine does not exist", err)
+	}
+	if err != nil {
+		return models.Vaccination{}, err
+	}
+	if !strings.EqualFold(vaccine.Species, pet.AnimalType) {
+		return models.Vaccination{}, validation.Errors{{
+			Field:   "vaccine_id",
+			Code:    validation.CodeInvalidEnum,
+			Message: "vaccine is for " + vaccine.Species + ", pet is " + pet.AnimalType,
+		}}
+	}
+
+	if vaccination.MedicalEntryID != 0 {
+		if err := s.checkEntryOfPet(vaccination.MedicalEntryID, pet.ID); err != nil {
+			return models.Vaccination{}, err
+		}
+	}
+
+	id, err := s.vaccinations.CreateVaccination(vaccination)
+	if err != nil {
+		return models.Vaccination{}, err
+	}
+
+	return s.vaccinations.GetVaccination(id)
+}
+
+func (s *VaccinationService) GetVaccinations(petID uint) ([]models.Vaccination, error) {
+	if _, err := s.storage.GetPet(models.Pet{ID: petID}); err != nil {
+		return nil, err
+	}
+	return s.vaccinations.GetVaccinations(petID)
+}
+
+// GetDueVaccinations returns overdue or upcoming revaccinations of clinic
+func (s *VaccinationService) GetDueVaccinations(filter models.VaccinationDueReqFilter) ([]models.DueVaccination, error) {
+	if filter.Status == models.VaccinationDueUpcoming && filter.WithinDays == nil {
+		days := uint(defaultUpcomingDays)
+		filter.WithinDays = &days
+	}
+	return s.vaccinations.GetDueVaccinations(filter)
+}
+
+func (s *VaccinationService) checkEntryOfPet(entryID, petID uint) error {
+	entries, err := s.storage.GetMedEntries(models.EntryReqFilter{EntryID: &entryID, PetID: &petID})
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return err
+	}
+	if len(entries) == 0 {
+		return validation.Errors{{
+			Field:   "medical_entry_id",
+			Code:    validation.CodeInvalidFormat,
+			Message: "medical entry does not belong to pet",
+		}}
+	}
+	return nil
+}
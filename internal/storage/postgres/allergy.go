@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const allergyTable = "allergy"
+const allergenSynonymTable = "allergen_synonym"
+const allergyOverrideTable = "allergy_override"
+
+const allergyColumns = "allergy.id, allergy.pet_id, allergy.kind, allergy.substance, allergy.reaction, " +
+	"allergy.severity, allergy.recorded_by, allergy.recorded_at"
+
+func (s *Storage) CreateAllergy(allergy models.Allergy) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (pet_id, kind, substance, reaction, severity, recorded_by) "+
+			"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		allergyTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, allergy.PetID, allergy.Kind, allergy.Substance, allergy.Reaction, allergy.Severity, allergy.RecordedBy,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create allergy")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetAllergy(id uint) (models.Allergy, error) {
+	allergies, err := s.queryAllergies(
+		fmt.Sprintf("SELECT %s FROM %s WHERE allergy.id = $1", allergyColumns, allergyTable), id,
+	)
+	if err != nil {
+		return models.Allergy{}, err
+	}
+	if len(allergies) == 0 {
+		return models.Allergy{}, errs.NotFound("allergy not found", nil)
+	}
+	return allergies[0], nil
+}
+
+// GetAllergies returns allergies & conditions of pet, latest first
+func (s *Storage) GetAllergies(petID uint) ([]models.Allergy, error) {
+	return s.queryAllergies(
+		fmt.Sprintf("SELECT %s FROM %s WHERE allergy.pet_id = $1 ORDER BY allergy.recorded_at DESC, allergy.id DESC",
+			allergyColumns, allergyTable),
+		petID,
+	)
+}
+
+// GetAllergiesByMedRecord returns allergies & conditions of pet whose card is medical record
+func (s *Storage) GetAllergiesByMedRecord(recordID uint) ([]models.Allergy, error) {
+	return s.queryAllergies(
+		fmt.Sprintf("SELECT %s FROM %s JOIN %s ON medical_record.pet_id = allergy.pet_id "+
+			"WHERE medical_record.id = $1 ORDER BY allergy.id",
+			allergyColumns, allergyTable, medRecordTable),
+		recordID,
+	)
+}
+
+func (s *Storage) queryAllergies(query string, arg interface{}) ([]models.Allergy, error) {
+	rows, err := s.conn().Query(query, arg)
+	if err != nil {
+		return nil, translateError(err, "failed to get allergies")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	allergies := []models.Allergy{}
+	for rows.Next() {
+		var allergy models.Allergy
+		err := rows.Scan(&allergy.ID, &allergy.PetID, &allergy.Kind, &allergy.Substance, &allergy.Reaction,
+			&allergy.Severity, &allergy.RecordedBy, &allergy.RecordedAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan allergy")
+		}
+		allergies = append(allergies, allergy)
+	}
+
+	return allergies, translateError(rows.Err(), "failed to iterate allergies")
+}
+
+func (s *Storage) DeleteAllergy(id uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", allergyTable)
+
+	res, err := s.conn().Exec(query, id)
+	if err != nil {
+		return translateError(err, "failed to delete allergy")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("allergy not found", nil)
+	}
+
+	return nil
+}
+
+// GetAllergenTerms returns substance with all terms of synonym groups it belongs to, lower cased
+func (s *Storage) GetAllergenTerms(substance string) ([]string, error) {
+	query := fmt.Sprintf(
+		"WITH grp AS (SELECT substance FROM %s WHERE lower(substance) = lower($1::text) OR lower(synonym) = lower($1::text)) "+
+			"SELECT lower($1::text) "+
+			"UNION SELECT lower(substance) FROM grp "+
+			"UNION SELECT lower(synonym) FROM %s WHERE substance IN (SELECT substance FROM grp)",
+		allergenSynonymTable, allergenSynonymTable,
+	)
+
+	rows, err := s.conn().Query(query, substance)
+	if err != nil {
+		return nil, translateError(err, "failed to get allergen terms")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	var terms []string
+	for rows.Next() {
+		var term string
+		if err := rows.Scan(&term); err != nil {
+			return nil, translateError(err, "failed to scan allergen term")
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, translateError(rows.Err(), "failed to iterate allergen terms")
+}
+
+// GetAllergenSynonyms returns synonym groups ordered by substance
+func (s *Storage) GetAllergenSynonyms() ([]models.AllergenSynonyms, error) {
+	query := fmt.Sprintf("SELECT substance, synonym FROM %s ORDER BY substance, synonym", allergenSynonymTable)
+
+	rows, err := s.conn().Query(query)
+	if err != nil {
+		return nil, translateError(err, "failed to get allergen synonyms")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	groups := []models.AllergenSynonyms{}
+	for rows.Next() {
+		var substance, synonym string
+		if err := rows.Scan(&substance, &synonym); err != nil {
+			return nil, translateError(err, "failed to scan allergen synonym")
+		}
+		if len(groups) == 0 || groups[len(groups)-1].Substance != substance {
+			groups = append(groups, models.AllergenSynonyms{Substance: substance, Synonyms: []string{}})
+		}
+		last := &groups[len(groups)-1]
+		last.Synonyms = append(last.Synonyms, synonym)
+	}
+
+	return groups, translateError(rows.Err(), "failed to iterate allergen synonyms")
+}
+
+// SaveAllergenSynonyms replaces synonyms of substance
+func (s *Storage) SaveAllergenSynonyms(group models.AllergenSynonyms) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE substance = $1", allergenSynonymTable)
+		if _, err := tx.Exec(query, group.Substance); err != nil {
+			return translateError(err, "failed to delete allergen synonyms")
+		}
+
+		query = fmt.Sprintf(
+			"INSERT INTO %s (substance, synonym) SELECT DISTINCT $1, s FROM unnest($2::text[]) s",
+			allergenSynonymTable,
+		)
+		_, err := tx.Exec(query, group.Substance, pq.Array(group.Synonyms))
+		return translateError(err, "failed to save allergen synonyms")
+	})
+}
+
+func (s *Storage) DeleteAllergenSynonyms(substance string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE substance = $1", allergenSynonymTable)
+
+	res, err := s.conn().Exec(query, substance)
+	if err != nil {
+		return translateError(err, "failed to delete allergen synonyms")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("allergen synonyms not found", nil)
+	}
+
+	return nil
+}
+
+func (s *Storage) CreateAllergyOverride(override models.AllergyOverride) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (medical_entry_id, allergy_id, veterinarian_id, matched_term, reason) "+
+			"VALUES ($1, $2, $3, $4, $5)",
+		allergyOverrideTable,
+	)
+
+	_, err := s.conn().Exec(
+		query, override.MedicalEntryID, override.AllergyID, nullableID(override.VetID),
+		override.MatchedTerm, override.Reason,
+	)
+	return translateError(err, "failed to log allergy override")
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const appointmentTable = "appointment"
+
+// vetScheduleLock is first key of advisory locks serializing bookings of one vet, second key is vet ID
+const vetScheduleLock = 1
+
+var appointmentColumns = []string{
+	"id", "pet_id", "veterinarian_id", "clinic_number", "starts_at", "ends_at", "reason", "status", "cancel_reason",
+	"COALESCE(medical_entry_id, 0)", "created_at", "updated_at",
+}
+
+func (s *Storage) CreateAppointment(appointment models.Appointment) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (pet_id, veterinarian_id, clinic_number, starts_at, ends_at, reason, status) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		appointmentTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, appointment.PetID, appointment.VetID, appointment.ClinicNumber, appointment.StartsAt,
+		appointment.EndsAt, appointment.Reason, appointment.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create appointment")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetAppointment(id uint) (models.Appointment, error) {
+	appointments, err := s.queryAppointments(s.psql.Select(appointmentColumns...).From(appointmentTable).
+		Where(squirrel.Eq{"id": id}))
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	if len(appointments) == 0 {
+		return models.Appointment{}, errs.NotFound("appointment not found", nil)
+	}
+	return appointments[0], nil
+}
+
+// GetAppointments returns appointments ordered by start
+func (s *Storage) GetAppointments(filter models.AppointmentReqFilter) ([]models.Appointment, error) {
+	stmt := s.psql.Select(appointmentColumns...).From(appointmentTable)
+	if filter.VetID != nil {
+		stmt = stmt.Where(squirrel.Eq{"veterinarian_id": *filter.VetID})
+	}
+	if filter.PetID != nil {
+		stmt = stmt.Where(squirrel.Eq{"pet_id": *filter.PetID})
+	}
+	if filter.Status != nil {
+		stmt = stmt.Where(squirrel.Eq{"status": *filter.Status})
+	}
+	if filter.From != nil {
+		stmt = stmt.Where(squirrel.Gt{"ends_at": *filter.From})
+	}
+	if filter.To != nil {
+		stmt = stmt.Where(squirrel.Lt{"starts_at": *filter.To})
+	}
+	stmt = stmt.OrderBy("starts_at", "id")
+	if filter.Limit != nil {
+		stmt = stmt.Limit(uint64(*filter.Limit))
+	}
+	if filter.Offset != nil {
+		stmt = stmt.Offset(uint64(*filter.Offset))
+	}
+
+	return s.queryAppointments(stmt)
+}
+
+// GetOverlappingAppointments returns active appointments of vet overlapping appointment, except itself
+func (s *Storage) GetOverlappingAppointments(appointment models.Appointment) ([]models.Appointment, error) {
+	return s.queryAppointments(s.psql.Select(appointmentColumns...).From(appointmentTable).
+		Where(squirrel.Eq{
+			"veterinarian_id": appointment.VetID,
+			"status":          []string{models.AppointmentStatusBooked, models.AppointmentStatusCheckedIn},
+		}).
+		Where(squirrel.NotEq{"id": appointment.ID}).
+		Where(squirrel.Lt{"starts_at": appointment.EndsAt}).
+		Where(squirrel.Gt{"ends_at": appointment.StartsAt}).
+		OrderBy("starts_at"))
+}
+
+// UpdateAppointment saves vet, time, status & links of appointment only if its status is still from
+func (s *Storage) UpdateAppointment(appointment models.Appointment, from string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET veterinarian_id = $1, clinic_number = $2, starts_at = $3, ends_at = $4, status = $5, "+
+			"cancel_reason = $6, medical_entry_id = $7, updated_at = CURRENT_TIMESTAMP WHERE id = $8 AND status = $9",
+		appointmentTable,
+	)
+
+	res, err := s.conn().Exec(
+		query, appointment.VetID, appointment.ClinicNumber, appointment.StartsAt, appointment.EndsAt,
+		appointment.Status, appointment.CancelReason, nullableID(appointment.MedicalEntryID), appointment.ID, from,
+	)
+	if err != nil {
+		return translateError(err, "failed to update appointment")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.Conflict("appointment was changed concurrently", nil)
+	}
+	return nil
+}
+
+// LockVetSchedule serializes bookings of vet until end of transaction. It should be called inside WithTx
+func (s *Storage) LockVetSchedule(vetID uint) error {
+	_, err := s.conn().Exec("SELECT pg_advisory_xact_lock($1, $2)", vetScheduleLock, vetID)
+	return translateError(err, "failed to lock vet schedule")
+}
+
+func (s *Storage) queryAppointments(stmt squirrel.SelectBuilder) ([]models.Appointment, error) {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get appointments")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	appointments := []models.Appointment{}
+	for rows.Next() {
+		var appointment models.Appointment
+		err := rows.Scan(&appointment.ID, &appointment.PetID, &appointment.VetID, &appointment.ClinicNumber,
+			&appointment.StartsAt, &appointment.EndsAt, &appointment.Reason, &appointment.Status,
+			&appointment.CancelReason, &appointment.MedicalEntryID, &appointment.CreatedAt, &appointment.UpdatedAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan appointment")
+		}
+		appointments = append(appointments, appointment)
+	}
+
+	return appointments, translateError(rows.Err(), "failed to iterate appointments")
+}
@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	clinicTable            = "clinic"
+	clinicHoursTable       = "clinic_hours"
+	vetScheduleTable       = "vet_schedule"
+	scheduleExceptionTable = "vet_schedule_exception"
+	timeBlockTable         = "time_block"
+)
+
+func (s *Storage) GetClinic(number string) (models.Clinic, error) {
+	query := fmt.Sprintf("SELECT number, name, time_zone FROM %s WHERE number = $1", clinicTable)
+
+	var clinic models.Clinic
+	err := s.conn().QueryRow(query, number).Scan(&clinic.Number, &clinic.Name, &clinic.TimeZone)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Clinic{}, errs.NotFound("clinic not found", err)
+		}
+		return models.Clinic{}, translateError(err, "failed to get clinic")
+	}
+
+	clinic.Hours, err = s.queryWorkingHours(
+		fmt.Sprintf("SELECT weekday, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI') FROM %s "+
+			"WHERE clinic_number = $1 ORDER BY weekday, start_time", clinicHoursTable),
+		number,
+	)
+	if err != nil {
+		return models.Clinic{}, err
+	}
+
+	return clinic, nil
+}
+
+// GetClinics returns clinics without opening hours
+func (s *Storage) GetClinics() ([]models.Clinic, error) {
+	query := fmt.Sprintf("SELECT number, name, time_zone FROM %s ORDER BY number", clinicTable)
+
+	rows, err := s.conn().Query(query)
+	if err != nil {
+		return nil, translateError(err, "failed to get clinics")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	clinics := []models.Clinic{}
+	for rows.Next() {
+		var clinic models.Clinic
+		if err := rows.Scan(&clinic.Number, &clinic.Name, &clinic.TimeZone); err != nil {
+			return nil, translateError(err, "failed to scan clinic")
+		}
+		clinics = append(clinics, clinic)
+	}
+
+	return clinics, translateError(rows.Err(), "failed to iterate clinics")
+}
+
+// SaveClinic creates or replaces clinic with its opening hours
+func (s *Storage) SaveClinic(clinic models.Clinic) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf(
+			"INSERT INTO %s (number, name, time_zone) VALUES ($1, $2, $3) "+
+				"ON CONFLICT (number) DO UPDATE SET name = EXCLUDED.name, time_zone = EXCLUDED.time_zone",
+			clinicTable,
+		)
+		if _, err := tx.Exec(query, clinic.Number, clinic.Name, clinic.TimeZone); err != nil {
+			return translateError(err, "failed to save clinic")
+		}
+
+		return replaceWorkingHours(tx, clinicHoursTable, "clinic_number", clinic.Number, clinic.Hours)
+	})
+}
+
+func (s *Storage) GetVetSchedule(vetID uint) ([]models.WorkingHours, error) {
+	return s.queryWorkingHours(
+		fmt.Sprintf("SELECT weekday, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI') FROM %s "+
+			"WHERE veterinarian_id = $1 ORDER BY weekday, start_time", vetScheduleTable),
+		vetID,
+	)
+}
+
+// SaveVetSchedule replaces weekly working hours of vet
+func (s *Storage) SaveVetSchedule(vetID uint, hours []models.WorkingHours) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		return replaceWorkingHours(tx, vetScheduleTable, "veterinarian_id", vetID, hours)
+	})
+}
+
+func replaceWorkingHours(tx *sql.Tx, table, ownerColumn string, owner interface{}, hours []models.WorkingHours) error {
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = $1", table, ownerColumn), owner); err != nil {
+		return translateError(err, "failed to delete working hours")
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s, weekday, start_time, end_time) VALUES ($1, $2, $3, $4)", table, ownerColumn,
+	)
+	for _, h := range hours {
+		if _, err := tx.Exec(query, owner, h.Weekday, h.Start, h.End); err != nil {
+			return translateError(err, "failed to save working hours")
+		}
+	}
+	return nil
+}
+
+func (s *Storage) queryWorkingHours(query string, args ...interface{}) ([]models.WorkingHours, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get working hours")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	hours := []models.WorkingHours{}
+	for rows.Next() {
+		var h models.WorkingHours
+		if err := rows.Scan(&h.Weekday, &h.Start, &h.End); err != nil {
+			return nil, translateError(err, "failed to scan working hours")
+		}
+		hours = append(hours, h)
+	}
+
+	return hours, translateError(rows.Err(), "failed to iterate working hours")
+}
+
+func (s *Storage) CreateScheduleException(exception models.ScheduleException) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (veterinarian_id, start_date, end_date, kind, note) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		scheduleExceptionTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, exception.VetID, exception.StartDate, exception.EndDate, exception.Kind, exception.Note,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create schedule exception")
+	}
+
+	return id, nil
+}
+
+// GetScheduleExceptions returns exceptions of vet overlapping from - to dates. Empty dates are not limited
+func (s *Storage) GetScheduleExceptions(vetID uint, from, to string) ([]models.ScheduleException, error) {
+	query := fmt.Sprintf(
+		"SELECT id, veterinarian_id, start_date, end_date, kind, note FROM %s WHERE veterinarian_id = $1 "+
+			"AND end_date >= COALESCE(NULLIF($2::text, '')::date, '-infinity') "+
+			"AND start_date <= COALESCE(NULLIF($3::text, '')::date, 'infinity') ORDER BY start_date, id",
+		scheduleExceptionTable,
+	)
+
+	rows, err := s.conn().Query(query, vetID, from, to)
+	if err != nil {
+		return nil, translateError(err, "failed to get schedule exceptions")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	exceptions := []models.ScheduleException{}
+	for rows.Next() {
+		var (
+			exception      models.ScheduleException
+			start, endDate time.Time
+		)
+		err := rows.Scan(&exception.ID, &exception.VetID, &start, &endDate, &exception.Kind, &exception.Note)
+		if err != nil {
+			return nil, translateError(err, "failed to scan schedule exception")
+		}
+		exception.StartDate = start.Format(models.DateLayout)
+		exception.EndDate = endDate.Format(models.DateLayout)
+		exceptions = append(exceptions, exception)
+	}
+
+	return exceptions, translateError(rows.Err(), "failed to iterate schedule exceptions")
+}
+
+func (s *Storage) DeleteScheduleException(vetID, id uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND veterinarian_id = $2", scheduleExceptionTable)
+	return s.deleteOne(query, "schedule exception", id, vetID)
+}
+
+func (s *Storage) CreateTimeBlock(block models.TimeBlock) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (veterinarian_id, pet_id, starts_at, ends_at, reason) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		timeBlockTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, block.VetID, nullableID(block.PetID), block.StartsAt, block.EndsAt, block.Reason,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create time block")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetTimeBlock(id uint) (models.TimeBlock, error) {
+	blocks, err := s.queryTimeBlocks(
+		fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", timeBlockColumns, timeBlockTable), id,
+	)
+	if err != nil {
+		return models.TimeBlock{}, err
+	}
+	if len(blocks) == 0 {
+		return models.TimeBlock{}, errs.NotFound("time block not found", nil)
+	}
+	return blocks[0], nil
+}
+
+// GetTimeBlocks returns blocks of vet overlapping from - to
+func (s *Storage) GetTimeBlocks(vetID uint, from, to time.Time) ([]models.TimeBlock, error) {
+	return s.queryTimeBlocks(
+		fmt.Sprintf("SELECT %s FROM %s WHERE veterinarian_id = $1 AND ends_at > $2 AND starts_at < $3 "+
+			"ORDER BY starts_at, id", timeBlockColumns, timeBlockTable),
+		vetID, from, to,
+	)
+}
+
+func (s *Storage) DeleteTimeBlock(vetID, id uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND veterinarian_id = $2", timeBlockTable)
+	return s.deleteOne(query, "time block", id, vetID)
+}
+
+const timeBlockColumns = "id, veterinarian_id, COALESCE(pet_id, 0), starts_at, ends_at, reason, created_at"
+
+func (s *Storage) queryTimeBlocks(query string, args ...interface{}) ([]models.TimeBlock, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get time blocks")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	blocks := []models.TimeBlock{}
+	for rows.Next() {
+		var block models.TimeBlock
+		err := rows.Scan(&block.ID, &block.VetID, &block.PetID, &block.StartsAt, &block.EndsAt, &block.Reason,
+			&block.CreatedAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan time block")
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, translateError(rows.Err(), "failed to iterate time blocks")
+}
+
+// deleteOne runs delete query and returns not found if nothing was deleted
+func (s *Storage) deleteOne(query, entity string, args ...interface{}) error {
+	res, err := s.conn().Exec(query, args...)
+	if err != nil {
+		return translateError(err, "failed to delete "+entity)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound(entity+" not found", nil)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const calendarFeedTable = "calendar_feed"
+
+func (s *Storage) CreateCalendarFeed(feed models.CalendarFeed, tokenHash string) (models.CalendarFeed, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (kind, subject_id, token_hash) VALUES ($1, $2, $3) RETURNING id, created_at",
+		calendarFeedTable,
+	)
+
+	err := s.conn().QueryRow(query, feed.Kind, feed.SubjectID, tokenHash).Scan(&feed.ID, &feed.CreatedAt)
+	if err != nil {
+		return models.CalendarFeed{}, translateError(err, "failed to create calendar feed")
+	}
+
+	return feed, nil
+}
+
+func (s *Storage) GetCalendarFeeds(kind string, subjectID uint) ([]models.CalendarFeed, error) {
+	query := fmt.Sprintf(
+		"SELECT id, kind, subject_id, created_at, revoked_at FROM %s WHERE kind = $1 AND subject_id = $2 ORDER BY id",
+		calendarFeedTable,
+	)
+
+	rows, err := s.conn().Query(query, kind, subjectID)
+	if err != nil {
+		return nil, translateError(err, "failed to get calendar feeds")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	feeds := []models.CalendarFeed{}
+	for rows.Next() {
+		feed, err := scanCalendarFeed(rows)
+		if err != nil {
+			return nil, translateError(err, "failed to scan calendar feed")
+		}
+		feeds = append(feeds, feed)
+	}
+
+	return feeds, translateError(rows.Err(), "failed to iterate calendar feeds")
+}
+
+// GetCalendarFeedByToken returns not revoked feed with token hash
+func (s *Storage) GetCalendarFeedByToken(tokenHash string) (models.CalendarFeed, error) {
+	query := fmt.Sprintf(
+		"SELECT id, kind, subject_id, created_at, revoked_at FROM %s WHERE token_hash = $1 AND revoked_at IS NULL",
+		calendarFeedTable,
+	)
+
+	feed, err := scanCalendarFeed(s.conn().QueryRow(query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.CalendarFeed{}, errs.NotFound("calendar feed not found", err)
+		}
+		return models.CalendarFeed{}, translateError(err, "failed to get calendar feed")
+	}
+
+	return feed, nil
+}
+
+func (s *Storage) RevokeCalendarFeed(kind string, subjectID, id uint) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET revoked_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $1 AND kind = $2 AND subject_id = $3 AND revoked_at IS NULL",
+		calendarFeedTable,
+	)
+
+	res, err := s.conn().Exec(query, id, kind, subjectID)
+	if err != nil {
+		return translateError(err, "failed to revoke calendar feed")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("calendar feed not found or already revoked", nil)
+	}
+
+	return nil
+}
+
+// GetFollowUps returns follow-ups of pets by medical record of vet or owner ordered by date
+func (s *Storage) GetFollowUps(filter models.FollowUpReqFilter) ([]models.FollowUp, error) {
+	stmt := s.psql.Select(
+		"medical_entry.id", "medical_entry.follow_up_at", "COALESCE(medical_entry.disease, '')",
+		"COALESCE(medical_entry.recommendation, '')", "pet.id", "pet.name", "COALESCE(owner.full_name, '')",
+		"COALESCE(veterinarian.full_name, '')",
+	).
+		From(medEntryTable).
+		Join(fmt.Sprintf("%s ON medical_record.id = medical_entry.medical_record_id", medRecordTable)).
+		Join(fmt.Sprintf("%s ON pet.id = medical_record.pet_id", petsTable)).
+		LeftJoin(fmt.Sprintf("%s ON owner.id = medical_record.owner_id", ownersTable)).
+		LeftJoin(fmt.Sprintf("%s ON veterinarian.id = medical_record.veterinarian_id", vetTable)).
+		Where("medical_entry.follow_up_at IS NOT NULL")
+
+	if filter.VetID != nil {
+		stmt = stmt.Where(squirrel.Eq{"medical_record.veterinarian_id": *filter.VetID})
+	}
+	if filter.OwnerID != nil {
+		stmt = stmt.Where(squirrel.Eq{"medical_record.owner_id": *filter.OwnerID})
+	}
+	if filter.From != "" {
+		stmt = stmt.Where("medical_entry.follow_up_at >= ?::date", filter.From)
+	}
+	stmt = stmt.OrderBy("medical_entry.follow_up_at", "medical_entry.id")
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get follow-ups")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	followUps := []models.FollowUp{}
+	for rows.Next() {
+		var (
+			followUp   models.FollowUp
+			followUpAt time.Time
+		)
+		err := rows.Scan(&followUp.EntryID, &followUpAt, &followUp.Disease, &followUp.Recommendation,
+			&followUp.PetID, &followUp.PetName, &followUp.OwnerName, &followUp.VetName)
+		if err != nil {
+			return nil, translateError(err, "failed to scan follow-up")
+		}
+		followUp.FollowUpAt = followUpAt.Format(models.DateLayout)
+		followUps = append(followUps, followUp)
+	}
+
+	return followUps, translateError(rows.Err(), "failed to iterate follow-ups")
+}
+
+func scanCalendarFeed(row rowScanner) (models.CalendarFeed, error) {
+	var (
+		feed      models.CalendarFeed
+		revokedAt sql.NullTime
+	)
+	if err := row.Scan(&feed.ID, &feed.Kind, &feed.SubjectID, &feed.CreatedAt, &revokedAt); err != nil {
+		return models.CalendarFeed{}, err
+	}
+	if revokedAt.Valid {
+		feed.RevokedAt = &revokedAt.Time
+	}
+	return feed, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// postgres error codes https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgInvalidDatetime     = "22007"
+	pgDatetimeOverflow    = "22008"
+)
+
+// translateError maps sql & postgres errors to domain errors. msg describes failed operation
+func translateError(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.NotFound("not found", err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pgForeignKeyViolation:
+			return errs.ForeignKey("referenced entity does not exist", err)
+		case pgUniqueViolation:
+			return errs.Conflict("entity already exists", err)
+		case pgNotNullViolation, pgCheckViolation, pgInvalidDatetime, pgDatetimeOverflow:
+			return errs.Validation("value violates constraint", err)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// rollback rolls transaction back and returns original error
+func (s *Storage) rollback(tx *sql.Tx, err error) error {
+	if rollbackErr := tx.Rollback(); rollbackErr != nil {
+		s.log.Errorf("failed to rollback transaction: %v", rollbackErr)
+	}
+	return err
+}
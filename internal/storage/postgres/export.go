@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	exportJobTable       = "export_job"
+	exportFileChunkTable = "export_file_chunk"
+	// exportChunkSize is size of stored file parts, file is never loaded into memory as a whole
+	exportChunkSize = 1024 * 1024
+)
+
+func (s *Storage) CreateExportJob(job models.ExportJob) (uint, error) {
+	criteria, err := json.Marshal(job.Criteria)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal criteria: %w", err)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (type, status, format, criteria) VALUES ($1, $2, $3, $4) RETURNING id", exportJobTable,
+	)
+
+	var id uint
+	if err := s.conn().QueryRow(query, job.Type, job.Status, job.Format, criteria).Scan(&id); err != nil {
+		return 0, translateError(err, "failed to create export job")
+	}
+
+	return id, nil
+}
+
+const exportJobColumns = "id, type, status, format, criteria, file_path, file_name, file_size, exported_pets, " +
+	"exported_entries, error, expires_at, created_at, updated_at"
+
+func scanExportJob(row rowScanner) (models.ExportJob, error) {
+	var (
+		job       models.ExportJob
+		criteria  []byte
+		expiresAt sql.NullTime
+	)
+	err := row.Scan(
+		&job.ID, &job.Type, &job.Status, &job.Format, &criteria, &job.FilePath, &job.FileName, &job.FileSize,
+		&job.ExportedPets, &job.ExportedEntries, &job.Error, &expiresAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return models.ExportJob{}, err
+	}
+	if expiresAt.Valid {
+		job.ExpiresAt = &expiresAt.Time
+	}
+
+	if err := json.Unmarshal(criteria, &job.Criteria); err != nil {
+		return models.ExportJob{}, fmt.Errorf("failed to unmarshal criteria: %w", err)
+	}
+
+	return job, nil
+}
+
+func (s *Storage) GetExportJob(id uint) (models.ExportJob, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", exportJobColumns, exportJobTable)
+
+	job, err := scanExportJob(s.conn().QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.ExportJob{}, errs.NotFound("export job not found", err)
+		}
+		return models.ExportJob{}, translateError(err, "failed to get export job")
+	}
+
+	return job, nil
+}
+
+// GetExpiredExportJobs returns completed jobs which files expired before given time
+func (s *Storage) GetExpiredExportJobs(before time.Time) ([]models.ExportJob, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE status = $1 AND expires_at <= $2 ORDER BY id",
+		exportJobColumns, exportJobTable,
+	)
+
+	rows, err := s.conn().Query(query, models.ExportStatusCompleted, before)
+	if err != nil {
+		return nil, translateError(err, "failed to get expired export jobs")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	var jobs []models.ExportJob
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, translateError(err, "failed to scan export job")
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, translateError(rows.Err(), "failed to iterate export jobs")
+}
+
+// UpdateExportJob saves status, progress, file, expiry & error of job. Failed & expired jobs are final,
+// they are not updated
+func (s *Storage) UpdateExportJob(job models.ExportJob) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, file_path = $2, file_name = $3, file_size = $4, exported_pets = $5, "+
+			"exported_entries = $6, error = $7, expires_at = $8, updated_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $9 AND status NOT IN ($10, $11)",
+		exportJobTable,
+	)
+
+	res, err := s.conn().Exec(
+		query, job.Status, job.FilePath, job.FileName, job.FileSize, job.ExportedPets, job.ExportedEntries,
+		job.Error, job.ExpiresAt, job.ID, models.ExportStatusFailed, models.ExportStatusExpired,
+	)
+	if err != nil {
+		return translateError(err, "failed to update export job")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.Conflict("export job not found or already finished", nil)
+	}
+
+	return nil
+}
+
+// FailStaleExportJobs fails pending & running jobs without progress for staleAfter, their worker is gone
+func (s *Storage) FailStaleExportJobs(staleAfter time.Duration, reason string) (int64, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, error = $2, updated_at = CURRENT_TIMESTAMP "+
+			"WHERE status IN ($3, $4) AND updated_at < CURRENT_TIMESTAMP - $5 * interval '1 second'",
+		exportJobTable,
+	)
+
+	res, err := s.conn().Exec(query, models.ExportStatusFailed, reason, models.ExportStatusPending,
+		models.ExportStatusRunning, int64(staleAfter/time.Second))
+	if err != nil {
+		return 0, translateError(err, "failed to fail stale export jobs")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected, nil
+}
+
+// SaveExportFile replaces file of job with content of r, returns its size
+func (s *Storage) SaveExportFile(jobID uint, r io.Reader) (int64, error) {
+	var size int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE job_id = $1", exportFileChunkTable)
+		if _, err := tx.Exec(query, jobID); err != nil {
+			return translateError(err, "failed to delete export file")
+		}
+
+		query = fmt.Sprintf("INSERT INTO %s (job_id, seq, data) VALUES ($1, $2, $3)", exportFileChunkTable)
+		chunk := make([]byte, exportChunkSize)
+		for seq := 0; ; seq++ {
+			n, err := io.ReadFull(r, chunk)
+			if n > 0 {
+				if _, err := tx.Exec(query, jobID, seq, chunk[:n]); err != nil {
+					return translateError(err, "failed to save export file")
+				}
+				size += int64(n)
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read export file: %w", err)
+			}
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// IterateExportFile calls fn with parts of job file in order, only one part is in memory at once
+func (s *Storage) IterateExportFile(jobID uint, fn func([]byte) error) error {
+	query := fmt.Sprintf("SELECT data FROM %s WHERE job_id = $1 ORDER BY seq", exportFileChunkTable)
+
+	rows, err := s.conn().Query(query, jobID)
+	if err != nil {
+		return translateError(err, "failed to get export file")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return translateError(err, "failed to scan export file")
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+
+	return translateError(rows.Err(), "failed to iterate export file")
+}
+
+func (s *Storage) DeleteExportFile(jobID uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE job_id = $1", exportFileChunkTable)
+
+	_, err := s.conn().Exec(query, jobID)
+	return translateError(err, "failed to delete export file")
+}
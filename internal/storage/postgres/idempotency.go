@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const idempotencyTable = "idempotency_key"
+
+// ReserveIdempotencyKey inserts in progress record. Expired record with same key is replaced, as is in progress
+// record whose lease ended. Returns false if key is already used
+func (s *Storage) ReserveIdempotencyKey(rec models.IdempotencyRecord) (bool, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (scope, key, request_hash, locked_until, expires_at) VALUES ($1, $2, $3, $4, $5) "+
+			"ON CONFLICT (scope, key) DO UPDATE SET "+
+			"request_hash = EXCLUDED.request_hash, "+
+			"response_status = NULL, "+
+			"response_content_type = NULL, "+
+			"response_body = NULL, "+
+			"created_at = CURRENT_TIMESTAMP, "+
+			"locked_until = EXCLUDED.locked_until, "+
+			"expires_at = EXCLUDED.expires_at "+
+			"WHERE %s.expires_at < CURRENT_TIMESTAMP "+
+			"OR (%s.response_status IS NULL AND %s.locked_until < CURRENT_TIMESTAMP) "+
+			"RETURNING key",
+		idempotencyTable, idempotencyTable, idempotencyTable, idempotencyTable,
+	)
+
+	var key string
+	err := s.conn().QueryRow(query, rec.Scope, rec.Key, rec.RequestHash, rec.LockedUntil, rec.ExpiresAt).Scan(&key)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, translateError(err, "failed to reserve idempotency key")
+	}
+
+	return true, nil
+}
+
+func (s *Storage) GetIdempotencyKey(scope, key string) (models.IdempotencyRecord, error) {
+	query := fmt.Sprintf(
+		"SELECT scope, key, request_hash, response_status, response_content_type, response_body, expires_at "+
+			"FROM %s WHERE scope = $1 AND key = $2",
+		idempotencyTable,
+	)
+
+	var (
+		rec         models.IdempotencyRecord
+		status      sql.NullInt64
+		contentType sql.NullString
+	)
+	err := s.conn().QueryRow(query, scope, key).Scan(
+		&rec.Scope, &rec.Key, &rec.RequestHash, &status, &contentType, &rec.ResponseBody, &rec.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.IdempotencyRecord{}, errs.NotFound("idempotency key not found", err)
+		}
+		return models.IdempotencyRecord{}, translateError(err, "failed to get idempotency key")
+	}
+
+	rec.ResponseStatus = int(status.Int64)
+	rec.ResponseContentType = contentType.String
+
+	return rec, nil
+}
+
+// SaveIdempotencyResponse saves response of in progress request
+func (s *Storage) SaveIdempotencyResponse(rec models.IdempotencyRecord) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET response_status = $1, response_content_type = $2, response_body = $3, locked_until = NULL "+
+			"WHERE scope = $4 AND key = $5 AND response_status IS NULL",
+		idempotencyTable,
+	)
+
+	_, err := s.conn().Exec(
+		query, rec.ResponseStatus, rec.ResponseContentType, rec.ResponseBody, rec.Scope, rec.Key,
+	)
+	return translateError(err, "failed to save idempotency response")
+}
+
+// DeleteIdempotencyKey releases in progress key. Saved responses are kept until they expire
+func (s *Storage) DeleteIdempotencyKey(scope, key string) error {
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE scope = $1 AND key = $2 AND response_status IS NULL", idempotencyTable,
+	)
+
+	_, err := s.conn().Exec(query, scope, key)
+	return translateError(err, "failed to delete idempotency key")
+}
+
+// DeleteExpiredIdempotencyKeys purges keys that can not be replayed anymore
+func (s *Storage) DeleteExpiredIdempotencyKeys() (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at < CURRENT_TIMESTAMP", idempotencyTable)
+
+	res, err := s.conn().Exec(query)
+	if err != nil {
+		return 0, translateError(err, "failed to delete expired idempotency keys")
+	}
+	return res.RowsAffected()
+}
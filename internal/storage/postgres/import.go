@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const importJobTable = "import_job"
+const importPetRefTable = "import_pet_ref"
+
+// errImportJobLost is not domain error, so importer stops instead of skipping row
+var errImportJobLost = errors.New("import job not found or claimed by another worker")
+
+func (s *Storage) CreateImportJob(job models.ImportJob) (uint, error) {
+	mapping, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal mapping: %w", err)
+	}
+	report, err := json.Marshal(job.Report)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (status, format, dry_run, file_path, mapping, default_vet_id, report) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		importJobTable,
+	)
+
+	var id uint
+	err = s.conn().QueryRow(
+		query, job.Status, job.Format, job.DryRun, job.FilePath, mapping, nullableID(job.DefaultVetID), report,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create import job")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetImportJob(id uint) (models.ImportJob, error) {
+	query := fmt.Sprintf(
+		"SELECT id, status, format, dry_run, file_path, mapping, default_vet_id, processed_rows, report, error, "+
+			"attempt, created_at, updated_at FROM %s WHERE id = $1",
+		importJobTable,
+	)
+
+	var (
+		job             models.ImportJob
+		mapping, report []byte
+		defaultVetID    sql.NullInt64
+	)
+	err := s.conn().QueryRow(query, id).Scan(
+		&job.ID, &job.Status, &job.Format, &job.DryRun, &job.FilePath, &mapping, &defaultVetID,
+		&job.ProcessedRows, &report, &job.Error, &job.Attempt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.ImportJob{}, errs.NotFound("import job not found", err)
+		}
+		return models.ImportJob{}, translateError(err, "failed to get import job")
+	}
+
+	if err := json.Unmarshal(mapping, &job.Mapping); err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to unmarshal mapping: %w", err)
+	}
+	if err := json.Unmarshal(report, &job.Report); err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+	job.DefaultVetID = uint(defaultVetID.Int64)
+
+	return job, nil
+}
+
+// ClaimImportJob atomically moves job in one of statuses to running & increments its attempt. Running job
+// without checkpoints for staleAfter is claimed too, its worker is considered dead
+func (s *Storage) ClaimImportJob(id uint, statuses []string, staleAfter time.Duration) (models.ImportJob, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $2, error = '', attempt = attempt + 1, updated_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $1 AND (status = ANY($3) OR "+
+			"(status = $2 AND updated_at < CURRENT_TIMESTAMP - $4 * interval '1 second')) RETURNING id",
+		importJobTable,
+	)
+
+	var claimed uint
+	err := s.conn().QueryRow(
+		query, id, models.ImportStatusRunning, pq.Array(statuses), int64(staleAfter/time.Second),
+	).Scan(&claimed)
+	if err == sql.ErrNoRows {
+		job, err := s.GetImportJob(id)
+		if err != nil {
+			return models.ImportJob{}, err
+		}
+		return models.ImportJob{}, errs.Conflict("import job is "+job.Status, nil)
+	}
+	if err != nil {
+		return models.ImportJob{}, translateError(err, "failed to claim import job")
+	}
+
+	return s.GetImportJob(id)
+}
+
+// UpdateImportJob saves status, checkpoint, report & error of job. Job claimed again by another worker
+// is not updated
+func (s *Storage) UpdateImportJob(job models.ImportJob) error {
+	report, err := json.Marshal(job.Report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, processed_rows = $2, report = $3, error = $4, updated_at = CURRENT_TIMESTAMP "+
+			"WHERE id = $5 AND attempt = $6",
+		importJobTable,
+	)
+
+	res, err := s.conn().Exec(query, job.Status, job.ProcessedRows, report, job.Error, job.ID, job.Attempt)
+	if err != nil {
+		return translateError(err, "failed to update import job")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errImportJobLost
+	}
+
+	return nil
+}
+
+// GetImportPetRef returns id of pet created by job for source reference
+func (s *Storage) GetImportPetRef(jobID uint, ref string) (uint, error) {
+	query := fmt.Sprintf("SELECT pet_id FROM %s WHERE job_id = $1 AND ref = $2", importPetRefTable)
+
+	var petID uint
+	err := s.conn().QueryRow(query, jobID, ref).Scan(&petID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errs.NotFound("pet reference not found", err)
+		}
+		return 0, translateError(err, "failed to get pet reference")
+	}
+
+	return petID, nil
+}
+
+func (s *Storage) SaveImportPetRef(jobID uint, ref string, petID uint) error {
+	query := fmt.Sprintf("INSERT INTO %s (job_id, ref, pet_id) VALUES ($1, $2, $3)", importPetRefTable)
+
+	_, err := s.conn().Exec(query, jobID, ref, petID)
+	return translateError(err, "failed to save pet reference")
+}
+
+// nullableID stores zero id as NULL
+func nullableID(id uint) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
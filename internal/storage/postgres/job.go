@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	scheduledJobTable = "scheduled_job"
+	jobRunTable       = "job_run"
+)
+
+// jobLock is first key of advisory locks of jobs, second key is hash of job name
+const jobLock = 2
+
+var jobRunColumns = []string{"id", "job_name", "trigger", "status", "host", "started_at", "finished_at", "error"}
+
+// LockJob takes session advisory lock of job on dedicated connection, so it is held while job runs
+// on other connections. ok is false if other replica holds lock. unlock releases lock & connection
+func (s *Storage) LockJob(ctx context.Context, name string) (unlock func() error, ok bool, err error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, false, translateError(err, "failed to get connection")
+	}
+
+	if err := conn.QueryRowContext(
+		ctx, "SELECT pg_try_advisory_lock($1, hashtext($2))", jobLock, name,
+	).Scan(&ok); err != nil || !ok {
+		if closeErr := conn.Close(); closeErr != nil {
+			s.log.Error("failed to close connection: ", closeErr)
+		}
+		return nil, false, translateError(err, "failed to lock job")
+	}
+
+	unlock = func() error {
+		defer func() {
+			if err := conn.Close(); err != nil {
+				s.log.Error("failed to close connection: ", err)
+			}
+		}()
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1, hashtext($2))", jobLock, name)
+		return translateError(err, "failed to unlock job")
+	}
+	return unlock, true, nil
+}
+
+// SaveJobSchedule registers job. next_run_at is reset only if schedule changed
+func (s *Storage) SaveJobSchedule(name, schedule string, nextRunAt time.Time) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (name, schedule, next_run_at) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (name) DO UPDATE SET schedule = EXCLUDED.schedule, next_run_at = EXCLUDED.next_run_at, "+
+			"updated_at = CURRENT_TIMESTAMP WHERE %s.schedule <> EXCLUDED.schedule",
+		scheduledJobTable, scheduledJobTable,
+	)
+
+	_, err := s.conn().Exec(query, name, schedule, nextRunAt)
+	return translateError(err, "failed to save job schedule")
+}
+
+func (s *Storage) GetJobNextRun(name string) (time.Time, error) {
+	query := fmt.Sprintf("SELECT next_run_at FROM %s WHERE name = $1", scheduledJobTable)
+
+	var next time.Time
+	if err := s.conn().QueryRow(query, name).Scan(&next); err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, errs.NotFound("job not found", err)
+		}
+		return time.Time{}, translateError(err, "failed to get job")
+	}
+	return next, nil
+}
+
+func (s *Storage) SetJobNextRun(name string, next time.Time) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET next_run_at = $1, updated_at = CURRENT_TIMESTAMP WHERE name = $2", scheduledJobTable,
+	)
+
+	_, err := s.conn().Exec(query, next, name)
+	return translateError(err, "failed to update job")
+}
+
+// GetScheduledJobs returns state of jobs with their last run
+func (s *Storage) GetScheduledJobs() ([]models.ScheduledJob, error) {
+	query := fmt.Sprintf(
+		"SELECT j.name, j.schedule, j.next_run_at, r.id, r.trigger, r.status, r.host, r.started_at, r.finished_at, "+
+			"r.error FROM %s j LEFT JOIN LATERAL ("+
+			"SELECT * FROM %s WHERE job_name = j.name ORDER BY started_at DESC, id DESC LIMIT 1"+
+			") r ON TRUE ORDER BY j.name",
+		scheduledJobTable, jobRunTable,
+	)
+
+	rows, err := s.conn().Query(query)
+	if err != nil {
+		return nil, translateError(err, "failed to get jobs")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	jobs := []models.ScheduledJob{}
+	for rows.Next() {
+		var (
+			job        models.ScheduledJob
+			nextRunAt  time.Time
+			runID      sql.NullInt64
+			trigger    sql.NullString
+			status     sql.NullString
+			host       sql.NullString
+			startedAt  sql.NullTime
+			finishedAt sql.NullTime
+			runErr     sql.NullString
+		)
+		err := rows.Scan(&job.Name, &job.Schedule, &nextRunAt, &runID, &trigger, &status, &host, &startedAt,
+			&finishedAt, &runErr)
+		if err != nil {
+			return nil, translateError(err, "failed to scan job")
+		}
+		job.NextRunAt = &nextRunAt
+		if runID.Valid {
+			job.LastRun = &models.JobRun{
+				ID: uint(runID.Int64), JobName: job.Name, Trigger: trigger.String, Status: status.String,
+				Host: host.String, StartedAt: startedAt.Time, Error: runErr.String,
+			}
+			if finishedAt.Valid {
+				job.LastRun.FinishedAt = &finishedAt.Time
+			}
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, translateError(rows.Err(), "failed to iterate jobs")
+}
+
+func (s *Storage) CreateJobRun(run models.JobRun) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (job_name, trigger, status, host, started_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		jobRunTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(query, run.JobName, run.Trigger, run.Status, run.Host, run.StartedAt).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create job run")
+	}
+	return id, nil
+}
+
+// FinishJobRun saves status, end & error of run
+func (s *Storage) FinishJobRun(run models.JobRun) error {
+	query := fmt.Sprintf("UPDATE %s SET status = $1, finished_at = $2, error = $3 WHERE id = $4", jobRunTable)
+
+	_, err := s.conn().Exec(query, run.Status, run.FinishedAt, run.Error, run.ID)
+	return translateError(err, "failed to finish job run")
+}
+
+// FailRunningJobRuns fails runs of job left running, e.g. by replica that died during run
+func (s *Storage) FailRunningJobRuns(name, reason string, finishedAt time.Time) (int64, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, finished_at = $2, error = $3 WHERE job_name = $4 AND status = $5", jobRunTable,
+	)
+
+	res, err := s.conn().Exec(query, models.JobRunStatusFailed, finishedAt, reason, name, models.JobRunStatusRunning)
+	if err != nil {
+		return 0, translateError(err, "failed to fail job runs")
+	}
+	return res.RowsAffected()
+}
+
+func (s *Storage) DeleteJobRun(id uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", jobRunTable)
+
+	_, err := s.conn().Exec(query, id)
+	return translateError(err, "failed to delete job run")
+}
+
+func (s *Storage) GetJobRuns(filter models.JobRunReqFilter) ([]models.JobRun, error) {
+	stmt := s.psql.Select(jobRunColumns...).From(jobRunTable)
+	if filter.JobName != nil {
+		stmt = stmt.Where(squirrel.Eq{"job_name": *filter.JobName})
+	}
+	stmt = stmt.OrderBy("started_at DESC", "id DESC")
+	if filter.Limit != nil {
+		stmt = stmt.Limit(uint64(*filter.Limit))
+	}
+	if filter.Offset != nil {
+		stmt = stmt.Offset(uint64(*filter.Offset))
+	}
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get job runs")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	runs := []models.JobRun{}
+	for rows.Next() {
+		var (
+			run        models.JobRun
+			finishedAt sql.NullTime
+		)
+		err := rows.Scan(&run.ID, &run.JobName, &run.Trigger, &run.Status, &run.Host, &run.StartedAt, &finishedAt,
+			&run.Error)
+		if err != nil {
+			return nil, translateError(err, "failed to scan job run")
+		}
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, translateError(rows.Err(), "failed to iterate job runs")
+}
+
+// DeleteJobRuns deletes runs started before
+func (s *Storage) DeleteJobRuns(before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE started_at < $1", jobRunTable)
+
+	res, err := s.conn().Exec(query, before)
+	if err != nil {
+		return 0, translateError(err, "failed to delete job runs")
+	}
+	return res.RowsAffected()
+}
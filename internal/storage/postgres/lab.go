@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const labMessageTable = "lab_message"
+const labResultTable = "lab_result"
+
+const labMessageColumns = "id, sender, control_id, status, COALESCE(pet_id, 0), patient_ids, review_reason, raw, " +
+	"received_at, resolved_at"
+
+// CreateLabMessage saves received message. Message with same sender & control id is Conflict
+func (s *Storage) CreateLabMessage(msg models.LabMessage) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (sender, control_id, status, pet_id, patient_ids, review_reason, raw) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		labMessageTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, msg.Sender, msg.ControlID, msg.Status, nullableID(msg.PetID), msg.PatientIDs, msg.ReviewReason, msg.Raw,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create lab message")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetLabMessage(id uint) (models.LabMessage, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", labMessageColumns, labMessageTable)
+
+	msg, err := scanLabMessage(s.conn().QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.LabMessage{}, errs.NotFound("lab message not found", err)
+		}
+		return models.LabMessage{}, translateError(err, "failed to get lab message")
+	}
+
+	return msg, nil
+}
+
+// GetLabMessages returns messages with status, oldest first
+func (s *Storage) GetLabMessages(status string) ([]models.LabMessage, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE status = $1 ORDER BY received_at, id",
+		labMessageColumns, labMessageTable)
+
+	rows, err := s.conn().Query(query, status)
+	if err != nil {
+		return nil, translateError(err, "failed to get lab messages")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	messages := []models.LabMessage{}
+	for rows.Next() {
+		msg, err := scanLabMessage(rows)
+		if err != nil {
+			return nil, translateError(err, "failed to scan lab message")
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, translateError(rows.Err(), "failed to iterate lab messages")
+}
+
+// UpdateLabMessage saves status, pet & review reason of message
+func (s *Storage) UpdateLabMessage(msg models.LabMessage) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, pet_id = $2, review_reason = $3, resolved_at = $4 WHERE id = $5",
+		labMessageTable,
+	)
+
+	res, err := s.conn().Exec(query, msg.Status, nullableID(msg.PetID), msg.ReviewReason, msg.ResolvedAt, msg.ID)
+	if err != nil {
+		return translateError(err, "failed to update lab message")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("lab message not found", nil)
+	}
+
+	return nil
+}
+
+func (s *Storage) CreateLabResult(result models.LabResult) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (message_id, medical_record_id, panel_code, panel_name, code, name, value_type, value, "+
+			"units, reference_range, abnormal_flag, result_status, observed_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id",
+		labResultTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, result.MessageID, result.MedicalRecordID, result.PanelCode, result.PanelName, result.Code,
+		result.Name, result.ValueType, result.Value, result.Units, result.ReferenceRange, result.AbnormalFlag,
+		result.ResultStatus, result.ObservedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create lab result")
+	}
+
+	return id, nil
+}
+
+// GetLabResults returns results ordered by observation time
+func (s *Storage) GetLabResults(filter models.LabResultReqFilter) ([]models.LabResult, error) {
+	query := s.psql.Select(
+		"lab_result.id", "lab_result.message_id", "lab_result.medical_record_id", "lab_result.panel_code",
+		"lab_result.panel_name", "lab_result.code", "lab_result.name", "lab_result.value_type", "lab_result.value",
+		"lab_result.units", "lab_result.reference_range", "lab_result.abnormal_flag", "lab_result.result_status",
+		"lab_result.observed_at",
+	).From(labResultTable)
+
+	if filter.PetID != nil {
+		query = query.Join(fmt.Sprintf("%s ON %s.id = %s.medical_record_id",
+			medRecordTable, medRecordTable, labResultTable)).
+			Where(squirrel.Eq{fmt.Sprintf("%s.pet_id", medRecordTable): *filter.PetID})
+	}
+	query = query.OrderBy("lab_result.observed_at", "lab_result.id")
+	if filter.Limit != nil {
+		query = query.Limit(uint64(*filter.Limit))
+	}
+	if filter.Offset != nil {
+		query = query.Offset(uint64(*filter.Offset))
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(sqlQuery, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get lab results")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", sqlQuery).Error(err)
+		}
+	}(rows)
+
+	results := []models.LabResult{}
+	for rows.Next() {
+		var result models.LabResult
+		err := rows.Scan(&result.ID, &result.MessageID, &result.MedicalRecordID, &result.PanelCode,
+			&result.PanelName, &result.Code, &result.Name, &result.ValueType, &result.Value, &result.Units,
+			&result.ReferenceRange, &result.AbnormalFlag, &result.ResultStatus, &result.ObservedAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan lab result")
+		}
+		results = append(results, result)
+	}
+
+	return results, translateError(rows.Err(), "failed to iterate lab results")
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLabMessage(row rowScanner) (models.LabMessage, error) {
+	var (
+		msg        models.LabMessage
+		resolvedAt sql.NullTime
+	)
+	err := row.Scan(&msg.ID, &msg.Sender, &msg.ControlID, &msg.Status, &msg.PetID, &msg.PatientIDs,
+		&msg.ReviewReason, &msg.Raw, &msg.ReceivedAt, &resolvedAt)
+	if err != nil {
+		return models.LabMessage{}, err
+	}
+	if resolvedAt.Valid {
+		msg.ResolvedAt = &resolvedAt.Time
+	}
+	return msg, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const measurementTable = "measurement"
+
+// syncPetWeightQuery copies weight of latest measurement to pet
+const syncPetWeightQuery = "UPDATE pet SET weight = m.weight FROM (" +
+	"SELECT weight FROM measurement WHERE pet_id = $1 ORDER BY measured_at DESC, id DESC LIMIT 1" +
+	") m WHERE pet.id = $1"
+
+// firstPetWeightQuery saves weight of created pet as its first measurement
+const firstPetWeightQuery = "INSERT INTO measurement (pet_id, measured_at, weight) VALUES ($1, CURRENT_DATE, $2)"
+
+// recordPetWeightQuery saves weight set on pet card as today's measurement if it changed. It should run before
+// pet is updated, weight of pet is still the old one then
+const recordPetWeightQuery = "INSERT INTO measurement (pet_id, measured_at, weight) " +
+	"SELECT id, CURRENT_DATE, $2::double precision FROM pet " +
+	"WHERE id = $1 AND $2::double precision > 0 AND weight IS DISTINCT FROM $2::double precision"
+
+// CreateMeasurement saves weigh-in and updates pet weight to latest measurement
+func (s *Storage) CreateMeasurement(measurement models.Measurement) (uint, error) {
+	var id uint
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf(
+			"INSERT INTO %s (pet_id, measured_at, weight, body_condition_score) "+
+				"VALUES ($1, $2, $3, NULLIF($4, 0)) RETURNING id",
+			measurementTable,
+		)
+
+		err := tx.QueryRow(
+			query, measurement.PetID, measurement.MeasuredAt, measurement.Weight, measurement.BodyConditionScore,
+		).Scan(&id)
+		if err != nil {
+			return translateError(err, "failed to create measurement")
+		}
+
+		_, err = tx.Exec(syncPetWeightQuery, measurement.PetID)
+		return translateError(err, "failed to update pet weight")
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetMeasurement(id uint) (models.Measurement, error) {
+	measurements, err := s.queryMeasurements("id = $1", id)
+	if err != nil {
+		return models.Measurement{}, err
+	}
+	if len(measurements) == 0 {
+		return models.Measurement{}, errs.NotFound("measurement not found", nil)
+	}
+	return measurements[0], nil
+}
+
+// GetMeasurements returns weigh-ins of pet, oldest first
+func (s *Storage) GetMeasurements(petID uint) ([]models.Measurement, error) {
+	return s.queryMeasurements("pet_id = $1", petID)
+}
+
+func (s *Storage) queryMeasurements(where string, arg interface{}) ([]models.Measurement, error) {
+	query := fmt.Sprintf(
+		"SELECT id, pet_id, measured_at, weight, COALESCE(body_condition_score, 0) FROM %s "+
+			"WHERE %s ORDER BY measured_at, id",
+		measurementTable, where,
+	)
+
+	rows, err := s.conn().Query(query, arg)
+	if err != nil {
+		return nil, translateError(err, "failed to get measurements")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	measurements := []models.Measurement{}
+	for rows.Next() {
+		var (
+			measurement models.Measurement
+			measuredAt  time.Time
+		)
+		err := rows.Scan(&measurement.ID, &measurement.PetID, &measuredAt, &measurement.Weight,
+			&measurement.BodyConditionScore)
+		if err != nil {
+			return nil, translateError(err, "failed to scan measurement")
+		}
+		measurement.MeasuredAt = measuredAt.Format(models.DateLayout)
+		measurements = append(measurements, measurement)
+	}
+
+	return measurements, translateError(rows.Err(), "failed to iterate measurements")
+}
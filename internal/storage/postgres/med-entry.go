@@ -5,53 +5,101 @@ import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
 )
 
 const medEntryTable = "medical_entry"
 
 // CreateMedEntry ДА, В ХЕНДЛЕРЕ УКАЗЫВАЕТСЯ PET_ID, но МНЕ ВПАДЛУ ПРОВЕРЯТЬ КАРТУ ЖИВОТНОГО )))
 func (s *Storage) CreateMedEntry(entry models.MedicalEntry) (uint, error) {
-	tx, err := s.db.Begin()
+	var entryID uint
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf(
+			"INSERT INTO %s ("+
+				"description, "+
+				"disease, "+
+				"vaccinations, "+
+				"recommendation, "+
+				"medical_record_id, "+
+				"device_number, "+
+				"veterinarian_id, "+
+				"entry_date, "+
+				"follow_up_at"+
+				") VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($8::text, '')::timestamp, CURRENT_TIMESTAMP), $9) "+
+				"RETURNING id",
+			medEntryTable,
+		)
+
+		// entry_date is set for historical entries, new ones get current time
+		err := tx.QueryRow(
+			query, entry.Description, entry.Disease, entry.Vaccinations, entry.Recommendation,
+			entry.MedicalRecordID, nullableID(entry.DeviceNumber), entry.VetID, entry.EntryDate,
+			nullableDate(entry.FollowUpAt),
+		).Scan(&entryID)
+		return translateError(err, "failed to create med entry")
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	// Create pet
+	return entryID, nil
+}
+
+// UpdateMedEntry replaces entry fields except med record. Empty entry date keeps saved one
+func (s *Storage) UpdateMedEntry(entry models.MedicalEntry) error {
 	query := fmt.Sprintf(
-		"INSERT INTO %s ("+
-			"description, "+
-			"disease, "+
-			"vaccinations, "+
-			"recommendation, "+
-			"medical_record_id, "+
-			"device_number, "+
-			"veterinarian_id"+
-			") VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		"UPDATE %s SET "+
+			"description = $1, "+
+			"disease = $2, "+
+			"vaccinations = $3, "+
+			"recommendation = $4, "+
+			"device_number = $5, "+
+			"veterinarian_id = $6, "+
+			"entry_date = COALESCE(NULLIF($7::text, '')::timestamp, entry_date), "+
+			"follow_up_at = $8 "+
+			"WHERE id = $9",
 		medEntryTable,
 	)
 
-	var entryID uint
-
-	err = tx.QueryRow(
+	res, err := s.conn().Exec(
 		query, entry.Description, entry.Disease, entry.Vaccinations, entry.Recommendation,
-		entry.MedicalRecordID, entry.DeviceNumber, entry.VetID,
-	).Scan(&entryID)
+		nullableID(entry.DeviceNumber), entry.VetID, entry.EntryDate, nullableDate(entry.FollowUpAt), entry.ID,
+	)
 	if err != nil {
-		if err := tx.Rollback(); err != nil {
-			return 0, err
-		}
-		return 0, err
+		return translateError(err, "failed to update med entry")
 	}
 
-	return entryID, tx.Commit()
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("med entry not found", nil)
+	}
+	return nil
 }
 
 func (s *Storage) GetMedEntries(filter models.EntryReqFilter) ([]models.MedicalEntry, error) {
+	var entries []models.MedicalEntry
+	err := s.IterateMedEntries(filter, func(entry models.MedicalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// IterateMedEntries calls fn for each entry while rows are read. Iteration stops at first error of fn,
+// it is returned as is
+func (s *Storage) IterateMedEntries(filter models.EntryReqFilter, fn func(models.MedicalEntry) error) error {
 	query := squirrel.Select(
 		fmt.Sprintf("%s.id, %s.entry_date, %s.description, %s.disease, %s.vaccinations, %s.recommendation, "+
-			"%s.medical_record_id, %s.device_number, %s.veterinarian_id",
+			"%s.medical_record_id, %s.device_number, %s.veterinarian_id, %s.follow_up_at",
 			medEntryTable, medEntryTable, medEntryTable, medEntryTable, medEntryTable,
-			medEntryTable, medEntryTable, medEntryTable, medEntryTable, // ha ha ha ha LOL
+			medEntryTable, medEntryTable, medEntryTable, medEntryTable, medEntryTable, // ha ha ha ha LOL
 		),
 	).
 		From(medEntryTable)
@@ -64,6 +112,16 @@ func (s *Storage) GetMedEntries(filter models.EntryReqFilter) ([]models.MedicalE
 			medRecordTable, medRecordTable, medEntryTable)).
 			Where(squirrel.Eq{fmt.Sprintf("%s.pet_id", medRecordTable): *filter.PetID})
 	}
+	if filter.VetID != nil {
+		query = query.Where(squirrel.Eq{fmt.Sprintf("%s.veterinarian_id", medEntryTable): *filter.VetID})
+	}
+	if filter.DateFrom != nil {
+		query = query.Where(fmt.Sprintf("%s.entry_date >= ?::date", medEntryTable), *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where(fmt.Sprintf("%s.entry_date < ?::date + 1", medEntryTable), *filter.DateTo)
+	}
+	query = query.OrderBy(fmt.Sprintf("%s.entry_date", medEntryTable), fmt.Sprintf("%s.id", medEntryTable))
 	if filter.Limit != nil {
 		query = query.Limit(uint64(*filter.Limit))
 	}
@@ -73,12 +131,12 @@ func (s *Storage) GetMedEntries(filter models.EntryReqFilter) ([]models.MedicalE
 
 	sqlQuery, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	rows, err := s.db.Query(sqlQuery, args...)
+	rows, err := s.conn().Query(sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return translateError(err, "failed to get med entries")
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -87,35 +145,47 @@ func (s *Storage) GetMedEntries(filter models.EntryReqFilter) ([]models.MedicalE
 		}
 	}(rows)
 
-	var entries []models.MedicalEntry
 	for rows.Next() {
-		var entry models.MedicalEntry
+		var (
+			entry        models.MedicalEntry
+			deviceNumber sql.NullInt64
+			followUpAt   sql.NullTime
+		)
 		err := rows.Scan(&entry.ID, &entry.EntryDate, &entry.Description, &entry.Disease, &entry.Vaccinations,
-			&entry.Recommendation, &entry.MedicalRecordID, &entry.DeviceNumber, &entry.VetID)
+			&entry.Recommendation, &entry.MedicalRecordID, &deviceNumber, &entry.VetID, &followUpAt)
 		if err != nil {
-			return []models.MedicalEntry{}, err
+			return translateError(err, "failed to scan med entry")
+		}
+		// entries without device have NULL device_number
+		entry.DeviceNumber = uint(deviceNumber.Int64)
+		if followUpAt.Valid {
+			entry.FollowUpAt = followUpAt.Time.Format(models.DateLayout)
+		}
+		if err := fn(entry); err != nil {
+			return err
 		}
-		entries = append(entries, entry)
 	}
 
-	return entries, nil
+	return translateError(rows.Err(), "failed to iterate med entries")
 }
 
 func (s *Storage) DeleteMedEntry(medRecordID uint, entryID uint) error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return err
-	}
+	return s.inTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", medEntryTable)
 
-	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", medEntryTable)
+		res, err := tx.Exec(query, entryID)
+		if err != nil {
+			return translateError(err, "failed to delete med entry")
+		}
 
-	_, err = tx.Exec(query, entryID)
-	if err != nil {
-		if err := tx.Rollback(); err != nil {
-			return err
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return errs.NotFound("med entry not found", nil)
 		}
-		return err
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
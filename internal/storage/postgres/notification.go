@@ -0,0 +1,367 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	notificationPreferenceTable = "notification_preference"
+	reminderTable               = "reminder"
+	notificationTable           = "notification"
+)
+
+var reminderColumns = []string{
+	"id", "owner_id", "pet_id", "COALESCE(medical_entry_id, 0)", "kind", "message", "due_at", "status",
+	"created_at", "processed_at",
+}
+
+var notificationColumns = []string{
+	"id", "reminder_id", "owner_id", "channel", "recipient", "language", "subject", "body", "status", "attempts",
+	"next_attempt_at", "last_error", "created_at", "sent_at",
+}
+
+func (s *Storage) GetNotificationPreferences(ownerID uint) (models.NotificationPreferences, error) {
+	query := fmt.Sprintf(
+		"SELECT owner_id, language, email_enabled, sms_enabled, opted_out, updated_at FROM %s WHERE owner_id = $1",
+		notificationPreferenceTable,
+	)
+
+	var prefs models.NotificationPreferences
+	err := s.conn().QueryRow(query, ownerID).Scan(
+		&prefs.OwnerID, &prefs.Language, &prefs.Email, &prefs.SMS, &prefs.OptedOut, &prefs.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.NotificationPreferences{}, errs.NotFound("notification preferences not found", err)
+		}
+		return models.NotificationPreferences{}, translateError(err, "failed to get notification preferences")
+	}
+
+	return prefs, nil
+}
+
+func (s *Storage) SaveNotificationPreferences(prefs models.NotificationPreferences) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (owner_id, language, email_enabled, sms_enabled, opted_out) VALUES ($1, $2, $3, $4, $5) "+
+			"ON CONFLICT (owner_id) DO UPDATE SET language = EXCLUDED.language, "+
+			"email_enabled = EXCLUDED.email_enabled, sms_enabled = EXCLUDED.sms_enabled, "+
+			"opted_out = EXCLUDED.opted_out, updated_at = CURRENT_TIMESTAMP",
+		notificationPreferenceTable,
+	)
+
+	_, err := s.conn().Exec(query, prefs.OwnerID, prefs.Language, prefs.Email, prefs.SMS, prefs.OptedOut)
+	return translateError(err, "failed to save notification preferences")
+}
+
+func (s *Storage) CreateReminder(reminder models.Reminder) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (owner_id, pet_id, medical_entry_id, kind, message, due_at, status) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+		reminderTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, reminder.OwnerID, reminder.PetID, nullableID(reminder.MedicalEntryID), reminder.Kind,
+		reminder.Message, reminder.DueAt, reminder.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create reminder")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetReminder(id uint) (models.Reminder, error) {
+	reminders, err := s.queryReminders(s.psql.Select(reminderColumns...).From(reminderTable).
+		Where(squirrel.Eq{"id": id}))
+	if err != nil {
+		return models.Reminder{}, err
+	}
+	if len(reminders) == 0 {
+		return models.Reminder{}, errs.NotFound("reminder not found", nil)
+	}
+	return reminders[0], nil
+}
+
+func (s *Storage) GetReminders(filter models.ReminderReqFilter) ([]models.Reminder, error) {
+	stmt := s.psql.Select(reminderColumns...).From(reminderTable)
+	if filter.OwnerID != nil {
+		stmt = stmt.Where(squirrel.Eq{"owner_id": *filter.OwnerID})
+	}
+	if filter.PetID != nil {
+		stmt = stmt.Where(squirrel.Eq{"pet_id": *filter.PetID})
+	}
+	if filter.Status != nil {
+		stmt = stmt.Where(squirrel.Eq{"status": *filter.Status})
+	}
+	stmt = stmt.OrderBy("due_at DESC", "id DESC")
+	if filter.Limit != nil {
+		stmt = stmt.Limit(uint64(*filter.Limit))
+	}
+	if filter.Offset != nil {
+		stmt = stmt.Offset(uint64(*filter.Offset))
+	}
+
+	return s.queryReminders(stmt)
+}
+
+// ScheduleEntryReminders creates reminders of med entries added since newSince and of upcoming follow-ups,
+// followUpLead before follow-up day starts. Entry dates are local times of clinic in timeZone (IANA name).
+// Entry gets each kind of reminder once
+func (s *Storage) ScheduleEntryReminders(newSince time.Time, followUpLead time.Duration, timeZone string) (int64, error) {
+	insert := fmt.Sprintf(
+		"INSERT INTO %s (owner_id, pet_id, medical_entry_id, kind, due_at) "+
+			"SELECT medical_record.owner_id, medical_record.pet_id, medical_entry.id, $1, %%s "+
+			"FROM %s JOIN %s ON medical_record.id = medical_entry.medical_record_id "+
+			"WHERE medical_record.owner_id IS NOT NULL AND %%s "+
+			"ON CONFLICT (medical_entry_id, kind) DO NOTHING",
+		reminderTable, medEntryTable, medRecordTable,
+	)
+
+	var scheduled int64
+	err := s.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			fmt.Sprintf(insert, "CURRENT_TIMESTAMP", "medical_entry.entry_date >= ($2::timestamptz AT TIME ZONE $3)"),
+			models.ReminderKindNewEntry, newSince, timeZone,
+		)
+		if err != nil {
+			return translateError(err, "failed to schedule new entry reminders")
+		}
+		newEntries, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		res, err = tx.Exec(
+			fmt.Sprintf(insert,
+				"(medical_entry.follow_up_at::timestamp AT TIME ZONE $3) - make_interval(secs => $2)",
+				"medical_entry.follow_up_at >= (CURRENT_TIMESTAMP AT TIME ZONE $3)::date",
+			),
+			models.ReminderKindFollowUp, followUpLead.Seconds(), timeZone,
+		)
+		if err != nil {
+			return translateError(err, "failed to schedule follow-up reminders")
+		}
+		followUps, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		scheduled = newEntries + followUps
+		return nil
+	})
+
+	return scheduled, err
+}
+
+// GetDueReminders returns pending reminders due at now, oldest first
+func (s *Storage) GetDueReminders(now time.Time, limit uint) ([]models.Reminder, error) {
+	return s.queryReminders(s.psql.Select(reminderColumns...).From(reminderTable).
+		Where(squirrel.Eq{"status": models.ReminderStatusPending}).
+		Where(squirrel.LtOrEq{"due_at": now}).
+		OrderBy("due_at", "id").
+		Limit(uint64(limit)))
+}
+
+// UpdateReminderStatus moves reminder to status only if it is still in from
+func (s *Storage) UpdateReminderStatus(id uint, from, to string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, processed_at = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3", reminderTable,
+	)
+
+	res, err := s.conn().Exec(query, to, id, from)
+	if err != nil {
+		return translateError(err, "failed to update reminder")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.Conflict("reminder is not "+from, nil)
+	}
+	return nil
+}
+
+// SettleReminder moves queued reminder to sent once any of its deliveries is sent, or to failed once all of them
+// failed. Reminder with pending deliveries & none sent stays queued
+func (s *Storage) SettleReminder(id uint) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = CASE WHEN sent THEN $2 ELSE $3 END, processed_at = CURRENT_TIMESTAMP "+
+			"FROM (SELECT bool_or(status = $4) AS sent, bool_or(status = $5) AS pending "+
+			"FROM %s WHERE reminder_id = $1) deliveries "+
+			"WHERE id = $1 AND %s.status = $6 AND (deliveries.sent OR NOT deliveries.pending)",
+		reminderTable, notificationTable, reminderTable,
+	)
+
+	_, err := s.conn().Exec(
+		query, id, models.ReminderStatusSent, models.ReminderStatusFailed, models.NotificationStatusSent,
+		models.NotificationStatusPending, models.ReminderStatusQueued,
+	)
+	return translateError(err, "failed to settle reminder")
+}
+
+func (s *Storage) CreateNotification(notification models.Notification) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (reminder_id, owner_id, channel, recipient, language, subject, body, status) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		notificationTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, notification.ReminderID, notification.OwnerID, notification.Channel, notification.Recipient,
+		notification.Language, notification.Subject, notification.Body, notification.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create notification")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetNotifications(filter models.NotificationReqFilter) ([]models.Notification, error) {
+	stmt := s.psql.Select(notificationColumns...).From(notificationTable)
+	if filter.OwnerID != nil {
+		stmt = stmt.Where(squirrel.Eq{"owner_id": *filter.OwnerID})
+	}
+	if filter.ReminderID != nil {
+		stmt = stmt.Where(squirrel.Eq{"reminder_id": *filter.ReminderID})
+	}
+	if filter.Status != nil {
+		stmt = stmt.Where(squirrel.Eq{"status": *filter.Status})
+	}
+	stmt = stmt.OrderBy("created_at DESC", "id DESC")
+	if filter.Limit != nil {
+		stmt = stmt.Limit(uint64(*filter.Limit))
+	}
+	if filter.Offset != nil {
+		stmt = stmt.Offset(uint64(*filter.Offset))
+	}
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return s.queryNotifications(query, args...)
+}
+
+// ClaimNotifications returns pending notifications due at now and postpones them till leaseUntil,
+// so other replicas skip them while they are sent
+func (s *Storage) ClaimNotifications(now, leaseUntil time.Time, limit uint) ([]models.Notification, error) {
+	query := fmt.Sprintf(
+		"UPDATE %s SET next_attempt_at = $1 WHERE id IN ("+
+			"SELECT id FROM %s WHERE status = $2 AND next_attempt_at <= $3 "+
+			"ORDER BY next_attempt_at, id LIMIT $4 FOR UPDATE SKIP LOCKED"+
+			") RETURNING %s",
+		notificationTable, notificationTable, strings.Join(notificationColumns, ", "),
+	)
+
+	return s.queryNotifications(query, leaseUntil, models.NotificationStatusPending, now, limit)
+}
+
+// UpdateNotification saves status & attempts of delivery
+func (s *Storage) UpdateNotification(notification models.Notification) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, sent_at = $5 "+
+			"WHERE id = $6",
+		notificationTable,
+	)
+
+	res, err := s.conn().Exec(
+		query, notification.Status, notification.Attempts, notification.NextAttemptAt, notification.LastError,
+		notification.SentAt, notification.ID,
+	)
+	if err != nil {
+		return translateError(err, "failed to update notification")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("notification not found", nil)
+	}
+	return nil
+}
+
+func (s *Storage) queryReminders(stmt squirrel.SelectBuilder) ([]models.Reminder, error) {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get reminders")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	reminders := []models.Reminder{}
+	for rows.Next() {
+		var (
+			reminder    models.Reminder
+			processedAt sql.NullTime
+		)
+		err := rows.Scan(&reminder.ID, &reminder.OwnerID, &reminder.PetID, &reminder.MedicalEntryID,
+			&reminder.Kind, &reminder.Message, &reminder.DueAt, &reminder.Status, &reminder.CreatedAt, &processedAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan reminder")
+		}
+		if processedAt.Valid {
+			reminder.ProcessedAt = &processedAt.Time
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, translateError(rows.Err(), "failed to iterate reminders")
+}
+
+func (s *Storage) queryNotifications(query string, args ...interface{}) ([]models.Notification, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get notifications")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var (
+			notification models.Notification
+			sentAt       sql.NullTime
+		)
+		err := rows.Scan(&notification.ID, &notification.ReminderID, &notification.OwnerID, &notification.Channel,
+			&notification.Recipient, &notification.Language, &notification.Subject, &notification.Body,
+			&notification.Status, &notification.Attempts, &notification.NextAttemptAt, &notification.LastError,
+			&notification.CreatedAt, &sentAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan notification")
+		}
+		if sentAt.Valid {
+			notification.SentAt = &sentAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, translateError(rows.Err(), "failed to iterate notifications")
+}
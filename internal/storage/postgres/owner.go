@@ -1,10 +1,13 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
 )
 
 const ownersTable = "owner"
@@ -13,9 +16,9 @@ func (s *Storage) CreateOwner(owner models.Owner) (uint, error) {
 	query := fmt.Sprintf("INSERT INTO %s (full_name, email, phone, password_hash) VALUES ($1, $2, $3, $4) RETURNING id", ownersTable)
 
 	var id uint
-	err := s.db.QueryRow(query, owner.FullName, owner.Email, owner.Phone, owner.PasswordHash).Scan(&id)
+	err := s.conn().QueryRow(query, owner.FullName, owner.Email, owner.Phone, owner.PasswordHash).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create owner: %w", err)
+		return 0, translateError(err, "failed to create owner")
 	}
 
 	return id, nil
@@ -29,8 +32,9 @@ func (s *Storage) GetOwner(owner models.Owner) (models.Owner, error) {
 	if owner.ID != 0 {
 		stmt = stmt.Where(squirrel.Eq{"id": owner.ID})
 	}
+	// emails are matched case insensitively, imported ones are lowercased
 	if owner.Email != "" {
-		stmt = stmt.Where(squirrel.Eq{"email": owner.Email})
+		stmt = stmt.Where("lower(email) = lower(?)", strings.TrimSpace(owner.Email))
 	}
 	if owner.Phone != "" {
 		stmt = stmt.Where(squirrel.Eq{"phone": owner.Phone})
@@ -46,9 +50,12 @@ func (s *Storage) GetOwner(owner models.Owner) (models.Owner, error) {
 
 	log.Debug("query: ", query, " args: ", args)
 
-	err = s.db.QueryRow(query, args...).Scan(&owner.ID, &owner.FullName, &owner.Email, &owner.Phone)
+	err = s.conn().QueryRow(query, args...).Scan(&owner.ID, &owner.FullName, &owner.Email, &owner.Phone)
 	if err != nil {
-		return models.Owner{}, err
+		if err == sql.ErrNoRows {
+			return models.Owner{}, errs.NotFound("owner not found", err)
+		}
+		return models.Owner{}, translateError(err, "failed to get owner")
 	}
 	return owner, nil
 }
@@ -65,9 +72,9 @@ func (s *Storage) GetAllOwners() ([]models.Owner, error) {
 
 	log.Debug("query: ", query, " args: ", args)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.conn().Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute select query: %w", err)
+		return nil, translateError(err, "failed to execute select query")
 	}
 	defer rows.Close()
 
@@ -113,12 +120,20 @@ func (s *Storage) UpdateOwner(owner models.Owner) (models.Owner, error) {
 
 	log.Debug("query: ", query, " args: ", args)
 
-	_, err = s.db.Exec(query, args...)
+	res, err := s.conn().Exec(query, args...)
+	if err != nil {
+		return models.Owner{}, translateError(err, "failed to update owner")
+	}
+
+	affected, err := res.RowsAffected()
 	if err != nil {
-		return models.Owner{}, fmt.Errorf("failed to update owner: %w", err)
+		return models.Owner{}, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return models.Owner{}, errs.NotFound("owner not found", nil)
 	}
 
-	return s.GetOwner(owner)
+	return s.GetOwner(models.Owner{ID: owner.ID})
 }
 
 func (s *Storage) DeleteOwner(id uint) error {
@@ -133,9 +148,17 @@ func (s *Storage) DeleteOwner(id uint) error {
 
 	log.Debug("query: ", query, " args: ", args)
 
-	_, err = s.db.Exec(query, args...)
+	res, err := s.conn().Exec(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete owner: %w", err)
+		return translateError(err, "failed to delete owner")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("owner not found", nil)
 	}
 
 	return nil
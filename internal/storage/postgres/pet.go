@@ -3,54 +3,77 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
 )
 
 const petsTable = "pet"
 const vetTable = "veterinarian"
 const medRecordTable = "medical_record"
 
+// age of pet is computed from birth date, unknown birth date gives zero age
+const (
+	petAgeExpr       = "COALESCE(date_part('year', age(pet.birth_date))::integer, 0)"
+	petAgeMonthsExpr = "COALESCE(date_part('month', age(pet.birth_date))::integer, 0)"
+	petBirthDateExpr = "COALESCE(to_char(pet.birth_date, 'YYYY-MM-DD'), '')"
+)
+
+// petBirthDate returns birth date to save. Unknown birth date is estimated from age
+func petBirthDate(pet models.Pet) (sql.NullString, bool) {
+	if pet.BirthDate != "" {
+		return sql.NullString{String: pet.BirthDate, Valid: true}, pet.BirthDateEstimated
+	}
+	if pet.Age > 0 {
+		estimated := time.Now().AddDate(-int(pet.Age), 0, 0).Format(models.DateLayout)
+		return sql.NullString{String: estimated, Valid: true}, true
+	}
+	return sql.NullString{}, false
+}
+
 // CreatePetWithCard creates pet -> creates card. on fail do not create each.
 func (s *Storage) CreatePetWithCard(pet models.Pet, ownderID uint, vetID uint) (uint, error) {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return 0, err
-	}
+	var petID uint
 
-	// Create pet
-	query := fmt.Sprintf(
-		"INSERT INTO %s (animal_type, name, gender, age, weight, condition, behavior, research_status) "+
-			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
-		petsTable,
-	)
+	err := s.inTx(func(tx *sql.Tx) error {
+		// Create pet
+		query := fmt.Sprintf(
+			"INSERT INTO %s (animal_type, name, gender, birth_date, birth_date_estimated, weight, condition, "+
+				"behavior, research_status, microchip, breed) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), '%s'), NULLIF($10, ''), NULLIF($11, '')) "+
+				"RETURNING id",
+			petsTable, models.ResearchStatusCandidate,
+		)
 
-	var petID uint
-	if err = tx.QueryRow(
-		query, pet.AnimalType, pet.Name, pet.Gender, pet.Age, pet.Weight, pet.Condition, pet.Behavior, pet.ResearchStatus,
-	).Scan(&petID); err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			s.log.Errorf("failed to rollback transaction: %v", rollbackErr)
+		birthDate, estimated := petBirthDate(pet)
+		if err := tx.QueryRow(
+			query, pet.AnimalType, pet.Name, pet.Gender, birthDate, estimated, pet.Weight, pet.Condition,
+			pet.Behavior, pet.ResearchStatus, pet.Microchip, pet.Breed,
+		).Scan(&petID); err != nil {
+			return translateError(err, "failed to create pet")
 		}
-		return 0, fmt.Errorf("failed to create pet: %w", err)
-	}
 
-	// Create medical record
-	query = fmt.Sprintf("INSERT INTO %s "+
-		"(veterinarian_id, owner_id, pet_id) "+
-		"VALUES ($1, $2, $3)", medRecordTable)
+		// weight of new pet is its first measurement
+		if pet.Weight > 0 {
+			if _, err := tx.Exec(firstPetWeightQuery, petID, pet.Weight); err != nil {
+				return translateError(err, "failed to create measurement")
+			}
+		}
 
-	_, err = tx.Exec(query, vetID, ownderID, petID)
-	if err != nil {
-		err := tx.Rollback()
-		if err != nil {
-			return 0, err
+		// Create medical record
+		query = fmt.Sprintf("INSERT INTO %s "+
+			"(veterinarian_id, owner_id, pet_id) "+
+			"VALUES ($1, $2, $3)", medRecordTable)
+
+		if _, err := tx.Exec(query, vetID, ownderID, petID); err != nil {
+			return translateError(err, "failed to create medical record")
 		}
-		return 0, fmt.Errorf("failed to insert pet: %w", err)
-	}
 
-	if err = tx.Commit(); err != nil {
+		return nil
+	})
+	if err != nil {
 		return 0, err
 	}
 
@@ -61,7 +84,8 @@ func (s *Storage) GetPet(pet models.Pet) (models.Pet, error) {
 	log := s.log.WithField("op", "Storage.GetPet")
 
 	stmt := s.psql.Select(
-		"id", "animal_type", "name", "gender", "age", "weight", "condition", "behavior", "research_status",
+		"id", "animal_type", "name", "gender", petAgeExpr, petAgeMonthsExpr, petBirthDateExpr, "birth_date_estimated",
+		"weight", "condition", "behavior", "research_status", "COALESCE(microchip, '')", "COALESCE(breed, '')",
 	).From(petsTable)
 
 	if pet.ID != 0 {
@@ -77,7 +101,7 @@ func (s *Storage) GetPet(pet models.Pet) (models.Pet, error) {
 		stmt = stmt.Where(squirrel.Eq{"gender": pet.Gender})
 	}
 	if pet.Age != 0 {
-		stmt = stmt.Where(squirrel.Eq{"age": pet.Age})
+		stmt = stmt.Where(squirrel.Eq{petAgeExpr: pet.Age})
 	}
 	if pet.Weight != 0 {
 		stmt = stmt.Where(squirrel.Eq{"weight": pet.Weight})
@@ -91,6 +115,9 @@ func (s *Storage) GetPet(pet models.Pet) (models.Pet, error) {
 	if pet.ResearchStatus != "" {
 		stmt = stmt.Where(squirrel.Eq{"research_status": pet.ResearchStatus})
 	}
+	if pet.Microchip != "" {
+		stmt = stmt.Where(squirrel.Eq{"microchip": pet.Microchip})
+	}
 
 	query, args, err := stmt.ToSql()
 	if err != nil {
@@ -99,27 +126,50 @@ func (s *Storage) GetPet(pet models.Pet) (models.Pet, error) {
 
 	log.Debug("query: ", query, " args: ", args)
 
-	err = s.db.QueryRow(query, args...).Scan(
+	err = s.conn().QueryRow(query, args...).Scan(
 		&pet.ID,
 		&pet.AnimalType,
 		&pet.Name,
 		&pet.Gender,
 		&pet.Age,
+		&pet.AgeMonths,
+		&pet.BirthDate,
+		&pet.BirthDateEstimated,
 		&pet.Weight,
 		&pet.Condition,
 		&pet.Behavior,
 		&pet.ResearchStatus,
+		&pet.Microchip,
+		&pet.Breed,
 	)
 	if err != nil {
-		return models.Pet{}, err
+		if err == sql.ErrNoRows {
+			return models.Pet{}, errs.NotFound("pet not found", err)
+		}
+		return models.Pet{}, translateError(err, "failed to get pet")
 	}
 	return pet, nil
 }
 
 func (s *Storage) GetPetsWithOwnerAndVet(filter models.PetReqFilter) ([]models.OutputPetDTO, error) {
+	var pets []models.OutputPetDTO
+	err := s.IteratePetsWithOwnerAndVet(filter, func(pet models.OutputPetDTO) error {
+		pets = append(pets, pet)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pets, nil
+}
+
+// IteratePetsWithOwnerAndVet calls fn for each pet while rows are read, so memory does not grow with result.
+// Iteration stops at first error of fn, it is returned as is
+func (s *Storage) IteratePetsWithOwnerAndVet(filter models.PetReqFilter, fn func(models.OutputPetDTO) error) error {
 	query := squirrel.Select(
-		"pet.id", "pet.animal_type", "pet.name", "pet.gender", "pet.age", "pet.weight",
-		"pet.condition", "pet.behavior", "pet.research_status",
+		"pet.id", "pet.animal_type", "pet.name", "pet.gender", petAgeExpr, petAgeMonthsExpr, petBirthDateExpr,
+		"pet.birth_date_estimated", "pet.weight",
+		"pet.condition", "pet.behavior", "pet.research_status", "COALESCE(pet.microchip, '')", "COALESCE(pet.breed, '')",
 		"medical_record.owner_id",
 		"medical_record.veterinarian_id",
 	).
@@ -138,6 +188,21 @@ func (s *Storage) GetPetsWithOwnerAndVet(filter models.PetReqFilter) ([]models.O
 	if filter.VetID != nil {
 		query = query.Where(squirrel.Eq{fmt.Sprintf("%s.veterinarian_id", medRecordTable): *filter.VetID})
 	}
+	if filter.ResearchStatus != nil {
+		query = query.Where(squirrel.Eq{"pet.research_status": *filter.ResearchStatus})
+	}
+	if len(filter.ResearchStatuses) > 0 {
+		query = query.Where(squirrel.Eq{"pet.research_status": filter.ResearchStatuses})
+	}
+	// pets of age N were born between N+1 years ago (exclusive) & N years ago
+	if filter.AgeFrom != nil {
+		query = query.Where("pet.birth_date <= CURRENT_DATE - make_interval(years => ?::integer)", *filter.AgeFrom)
+	}
+	if filter.AgeTo != nil {
+		query = query.Where("pet.birth_date > CURRENT_DATE - make_interval(years => ?::integer + 1)", *filter.AgeTo)
+	}
+	// stable order keeps pages consistent
+	query = query.OrderBy(fmt.Sprintf("%s.id", petsTable))
 	if filter.Limit != nil {
 		query = query.Limit(uint64(*filter.Limit))
 	}
@@ -147,14 +212,14 @@ func (s *Storage) GetPetsWithOwnerAndVet(filter models.PetReqFilter) ([]models.O
 
 	sqlQuery, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	s.log.WithField("op", "Storage.GetPetsWithOwnerAndVet").WithField("sql", sqlQuery).Info("sql")
+	s.log.WithField("op", "Storage.IteratePetsWithOwnerAndVet").WithField("sql", sqlQuery).Info("sql")
 
-	rows, err := s.db.Query(sqlQuery, args...)
+	rows, err := s.conn().Query(sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return translateError(err, "failed to get pets")
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -163,54 +228,43 @@ func (s *Storage) GetPetsWithOwnerAndVet(filter models.PetReqFilter) ([]models.O
 		}
 	}(rows)
 
-	var pets []models.OutputPetDTO
 	for rows.Next() {
 		var pet models.OutputPetDTO
 		err := rows.Scan(
 			&pet.Pet.ID, &pet.Pet.AnimalType, &pet.Pet.Name, &pet.Pet.Gender, &pet.Pet.Age,
-			&pet.Pet.Weight, &pet.Pet.Condition, &pet.Pet.Behavior, &pet.Pet.ResearchStatus,
-			&pet.OwnerID, &pet.VetID,
+			&pet.Pet.AgeMonths, &pet.Pet.BirthDate, &pet.Pet.BirthDateEstimated, &pet.Pet.Weight, &pet.Pet.Condition, &pet.Pet.Behavior, &pet.Pet.ResearchStatus,
+			&pet.Pet.Microchip, &pet.Pet.Breed, &pet.OwnerID, &pet.VetID,
 		)
 		if err != nil {
-			return []models.OutputPetDTO{}, err
+			return translateError(err, "failed to scan pet")
+		}
+		if err := fn(pet); err != nil {
+			return err
 		}
-		pets = append(pets, pet)
 	}
 
-	return pets, nil
+	return translateError(rows.Err(), "failed to iterate pets")
 }
 
+// UpdatePet replaces all pet fields except research status. Zero values are written as is
 func (s *Storage) UpdatePet(pet models.Pet) (models.Pet, error) {
 	log := s.log.WithField("op", "Storage.UpdatePet")
 
-	stmt := s.psql.Update(petsTable).Where(squirrel.Eq{"id": pet.ID})
+	birthDate, estimated := petBirthDate(pet)
+	stmt := s.psql.Update(petsTable).
+		Set("animal_type", pet.AnimalType).
+		Set("name", pet.Name).
+		Set("gender", pet.Gender).
+		Set("birth_date", birthDate).
+		Set("birth_date_estimated", estimated).
+		// weight is not cleared, it stays equal to latest measurement
+		Set("weight", squirrel.Expr("COALESCE(NULLIF(?::double precision, 0), weight)", pet.Weight)).
+		Set("condition", pet.Condition).
+		Set("behavior", pet.Behavior).
+		Set("microchip", squirrel.Expr("NULLIF(?, '')", pet.Microchip)).
+		Set("breed", squirrel.Expr("NULLIF(?, '')", pet.Breed)).
+		Where(squirrel.Eq{"id": pet.ID})
 
-	if pet.AnimalType != "" {
-		stmt = stmt.Set("animal_type", pet.AnimalType)
-	}
-	if pet.Name != "" {
-		stmt = stmt.Set("name", pet.Name)
-	}
-	if pet.Gender != "" {
-		stmt = stmt.Set("gender", pet.Gender)
-	}
-	if pet.Age != 0 {
-		stmt = stmt.Set("age", pet.Age)
-	}
-	if pet.Weight != 0 {
-		stmt = stmt.Set("weight", pet.Weight)
-	}
-	if pet.Condition != "" {
-		stmt = stmt.Set("condition", pet.Condition)
-	}
-	if pet.Behavior != "" {
-		stmt = stmt.Set("behavior", pet.Behavior)
-	}
-	if pet.ResearchStatus != "" {
-		stmt = stmt.Set("research_status", pet.ResearchStatus)
-	}
-
-	stmt = stmt.Where(squirrel.Eq{"id": pet.ID})
 	query, args, err := stmt.ToSql()
 	if err != nil {
 		return models.Pet{}, fmt.Errorf("failed to build update query: %w", err)
@@ -218,66 +272,89 @@ func (s *Storage) UpdatePet(pet models.Pet) (models.Pet, error) {
 
 	log.Debug("query: ", query, " args: ", args)
 
-	_, err = s.db.Exec(query, args...)
+	err = s.inTx(func(tx *sql.Tx) error {
+		// changed weight is kept as today's measurement before it is overwritten
+		if _, err := tx.Exec(recordPetWeightQuery, pet.ID, pet.Weight); err != nil {
+			return translateError(err, "failed to create measurement")
+		}
+
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			return translateError(err, "failed to update pet")
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return errs.NotFound("pet not found", nil)
+		}
+		return nil
+	})
 	if err != nil {
-		return models.Pet{}, fmt.Errorf("failed to update pet: %w", err)
+		return models.Pet{}, err
 	}
 
-	return s.GetPet(pet)
+	return s.GetPet(models.Pet{ID: pet.ID})
 }
 
-// DelPetWithCard deletes med records -> deletes pet info
-func (s *Storage) DelPetWithCard(id uint) error {
-	log := s.log.WithField("op", "Storage.DelPetWithCard")
+// GetMedRecordByPet returns medical card of pet
+func (s *Storage) GetMedRecordByPet(petID uint) (models.MedicalRecord, error) {
+	query := fmt.Sprintf("SELECT id, veterinarian_id, owner_id, pet_id FROM %s WHERE pet_id = $1", medRecordTable)
 
-	tx, err := s.db.Begin()
+	var record models.MedicalRecord
+	err := s.conn().QueryRow(query, petID).Scan(&record.ID, &record.VetID, &record.OwnerID, &record.PetID)
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return models.MedicalRecord{}, errs.NotFound("medical record not found", err)
+		}
+		return models.MedicalRecord{}, translateError(err, "failed to get medical record")
 	}
 
-	// delete med record entries
+	return record, nil
+}
 
-	// delete med record
-	stmt := s.psql.Delete(medRecordTable).Where(squirrel.Eq{"pet_id": id})
-	query, args, err := stmt.ToSql()
-	if err != nil {
-		err := tx.Rollback()
+// DelPetWithCard deletes med records -> deletes pet info
+func (s *Storage) DelPetWithCard(id uint) error {
+	log := s.log.WithField("op", "Storage.DelPetWithCard")
+
+	return s.inTx(func(tx *sql.Tx) error {
+		// delete med record entries
+
+		// delete med record
+		stmt := s.psql.Delete(medRecordTable).Where(squirrel.Eq{"pet_id": id})
+		query, args, err := stmt.ToSql()
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to build delete med record query: %w", err)
 		}
-		return fmt.Errorf("failed to build delete med record query: %w", err)
-	}
 
-	_, err = tx.Exec(query, args...)
-	if err != nil {
-		err := tx.Rollback()
-		if err != nil {
-			return err
+		if _, err = tx.Exec(query, args...); err != nil {
+			return translateError(err, "failed to delete med record")
 		}
-		return fmt.Errorf("failed to delete record query: %w", err)
-	}
 
-	// delete pet
-	stmt = s.psql.Delete(petsTable).Where(squirrel.Eq{"id": id})
-	query, args, err = stmt.ToSql()
-	if err != nil {
-		err := tx.Rollback()
+		// delete pet
+		stmt = s.psql.Delete(petsTable).Where(squirrel.Eq{"id": id})
+		query, args, err = stmt.ToSql()
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to build delete pet query: %w", err)
 		}
-		return fmt.Errorf("failed to build delete pet query: %w", err)
-	}
 
-	log.Debug("query: ", query, " args: ", args)
+		log.Debug("query: ", query, " args: ", args)
 
-	_, err = tx.Exec(query, args...)
-	if err != nil {
-		err := tx.Rollback()
+		res, err := tx.Exec(query, args...)
 		if err != nil {
-			return err
+			return translateError(err, "failed to delete pet")
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return errs.NotFound("pet not found", nil)
 		}
-		return fmt.Errorf("failed to delete pet: %w", err)
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/Masterminds/squirrel"
 	_ "github.com/lib/pq"
@@ -13,9 +14,19 @@ import (
 type Storage struct {
 	log  *logging.Logger
 	db   *sql.DB
+	tx   *sql.Tx // not nil inside WithTx
 	psql squirrel.StatementBuilderType
 }
 
+// querier is common part of *sql.DB & *sql.Tx
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+var savepointSeq uint64
+
 func New(log *logging.Logger, cfg *config.DbConfig) *Storage {
 	psqlInfo := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
@@ -42,3 +53,60 @@ func New(log *logging.Logger, cfg *config.DbConfig) *Storage {
 func (s *Storage) Shutdown() error {
 	return s.db.Close()
 }
+
+// conn returns outer transaction if storage is used inside WithTx
+func (s *Storage) conn() querier {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// WithTx runs fn with storage bound to one transaction. Commits if fn returns nil
+func (s *Storage) WithTx(fn func(tx *Storage) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return translateError(err, "failed to begin transaction")
+	}
+
+	txStorage := *s
+	txStorage.tx = tx
+
+	if err := fn(&txStorage); err != nil {
+		return s.rollback(tx, err)
+	}
+
+	return translateError(tx.Commit(), "failed to commit transaction")
+}
+
+// inTx runs fn in new transaction. Inside WithTx it uses savepoint so failed fn
+// does not abort outer transaction
+func (s *Storage) inTx(fn func(tx *sql.Tx) error) error {
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return translateError(err, "failed to begin transaction")
+		}
+		if err := fn(tx); err != nil {
+			return s.rollback(tx, err)
+		}
+		return translateError(tx.Commit(), "failed to commit transaction")
+	}
+
+	savepoint := fmt.Sprintf("sp_%d", atomic.AddUint64(&savepointSeq, 1))
+	if _, err := s.tx.Exec("SAVEPOINT " + savepoint); err != nil {
+		return translateError(err, "failed to create savepoint")
+	}
+	if err := fn(s.tx); err != nil {
+		if _, rollbackErr := s.tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); rollbackErr != nil {
+			s.log.Errorf("failed to rollback to savepoint: %v", rollbackErr)
+		}
+		return err
+	}
+	_, err := s.tx.Exec("RELEASE SAVEPOINT " + savepoint)
+	return translateError(err, "failed to release savepoint")
+}
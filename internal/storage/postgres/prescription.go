@@ -0,0 +1,310 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const prescriptionTable = "prescription"
+const drugDoseRangeTable = "drug_dose_range"
+
+const prescriptionColumns = "prescription.id, prescription.medical_entry_id, prescription.drug, prescription.dose, " +
+	"prescription.unit, prescription.route, prescription.frequency, COALESCE(prescription.duration_days, 0), " +
+	"prescription.start_date, prescription.end_date, prescription.dose_override"
+
+func (s *Storage) CreatePrescription(prescription models.Prescription) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (medical_entry_id, drug, dose, unit, route, frequency, duration_days, start_date, "+
+			"end_date, dose_override) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id",
+		prescriptionTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, prescription.MedicalEntryID, prescription.Drug, prescription.Dose, prescription.Unit,
+		prescription.Route, prescription.Frequency, nullableDays(prescription.DurationDays), prescription.StartDate,
+		nullableDate(prescription.EndDate), prescription.DoseOverride,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create prescription")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetPrescription(id uint) (models.Prescription, error) {
+	prescriptions, err := s.queryPrescriptions(
+		fmt.Sprintf("SELECT %s FROM %s WHERE prescription.id = $1", prescriptionColumns, prescriptionTable), id,
+	)
+	if err != nil {
+		return models.Prescription{}, err
+	}
+	if len(prescriptions) == 0 {
+		return models.Prescription{}, errs.NotFound("prescription not found", nil)
+	}
+	return prescriptions[0], nil
+}
+
+// GetPrescriptions returns prescriptions of medical entry
+func (s *Storage) GetPrescriptions(entryID uint) ([]models.Prescription, error) {
+	return s.queryPrescriptions(
+		fmt.Sprintf("SELECT %s FROM %s WHERE prescription.medical_entry_id = $1 ORDER BY prescription.id",
+			prescriptionColumns, prescriptionTable),
+		entryID,
+	)
+}
+
+// GetActivePrescriptions returns prescriptions of pet active on date
+func (s *Storage) GetActivePrescriptions(petID uint, date string) ([]models.Prescription, error) {
+	return s.queryPrescriptions(
+		fmt.Sprintf(
+			"SELECT %s FROM %s "+
+				"JOIN %s ON %s.id = prescription.medical_entry_id "+
+				"JOIN %s ON %s.id = %s.medical_record_id "+
+				"WHERE %s.pet_id = $1 AND prescription.start_date <= $2 "+
+				"AND (prescription.end_date IS NULL OR prescription.end_date >= $2) "+
+				"ORDER BY prescription.start_date, prescription.id",
+			prescriptionColumns, prescriptionTable,
+			medEntryTable, medEntryTable,
+			medRecordTable, medRecordTable, medEntryTable,
+			medRecordTable,
+		),
+		petID, date,
+	)
+}
+
+// UpdatePrescription replaces all prescription fields except medical entry
+func (s *Storage) UpdatePrescription(prescription models.Prescription) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET drug = $1, dose = $2, unit = $3, route = $4, frequency = $5, duration_days = $6, "+
+			"start_date = $7, end_date = $8, dose_override = $9 WHERE id = $10",
+		prescriptionTable,
+	)
+
+	res, err := s.conn().Exec(
+		query, prescription.Drug, prescription.Dose, prescription.Unit, prescription.Route, prescription.Frequency,
+		nullableDays(prescription.DurationDays), prescription.StartDate, nullableDate(prescription.EndDate),
+		prescription.DoseOverride, prescription.ID,
+	)
+	if err != nil {
+		return translateError(err, "failed to update prescription")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("prescription not found", nil)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeletePrescription(id uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", prescriptionTable)
+
+	res, err := s.conn().Exec(query, id)
+	if err != nil {
+		return translateError(err, "failed to delete prescription")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("prescription not found", nil)
+	}
+
+	return nil
+}
+
+// GetDoseRange returns reference range of drug. Range of species wins over range for any species
+func (s *Storage) GetDoseRange(drug, species string) (models.DoseRange, error) {
+	query := fmt.Sprintf(
+		"SELECT id, drug, species, min_mg_per_kg, max_mg_per_kg FROM %s "+
+			"WHERE lower(drug) = lower($1) AND (species = '' OR lower(species) = lower($2)) "+
+			"ORDER BY species DESC LIMIT 1",
+		drugDoseRangeTable,
+	)
+
+	var doseRange models.DoseRange
+	err := s.conn().QueryRow(query, drug, species).Scan(
+		&doseRange.ID, &doseRange.Drug, &doseRange.Species, &doseRange.MinMgPerKg, &doseRange.MaxMgPerKg,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.DoseRange{}, errs.NotFound("dose range not found", err)
+		}
+		return models.DoseRange{}, translateError(err, "failed to get dose range")
+	}
+
+	return doseRange, nil
+}
+
+func (s *Storage) CreateDoseRange(doseRange models.DoseRange) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (drug, species, min_mg_per_kg, max_mg_per_kg) VALUES ($1, $2, $3, $4) RETURNING id",
+		drugDoseRangeTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, doseRange.Drug, doseRange.Species, doseRange.MinMgPerKg, doseRange.MaxMgPerKg,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create dose range")
+	}
+
+	return id, nil
+}
+
+// GetDoseRanges returns reference table. Empty drug returns ranges of all drugs
+func (s *Storage) GetDoseRanges(drug string) ([]models.DoseRange, error) {
+	stmt := s.psql.Select("id", "drug", "species", "min_mg_per_kg", "max_mg_per_kg").From(drugDoseRangeTable)
+	if drug != "" {
+		stmt = stmt.Where("lower(drug) = lower(?)", drug)
+	}
+	stmt = stmt.OrderBy("lower(drug)", "species")
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get dose ranges")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	doseRanges := []models.DoseRange{}
+	for rows.Next() {
+		var doseRange models.DoseRange
+		err := rows.Scan(
+			&doseRange.ID, &doseRange.Drug, &doseRange.Species, &doseRange.MinMgPerKg, &doseRange.MaxMgPerKg,
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan dose range")
+		}
+		doseRanges = append(doseRanges, doseRange)
+	}
+
+	return doseRanges, translateError(rows.Err(), "failed to iterate dose ranges")
+}
+
+func (s *Storage) UpdateDoseRange(doseRange models.DoseRange) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET drug = $1, species = $2, min_mg_per_kg = $3, max_mg_per_kg = $4 WHERE id = $5",
+		drugDoseRangeTable,
+	)
+
+	res, err := s.conn().Exec(
+		query, doseRange.Drug, doseRange.Species, doseRange.MinMgPerKg, doseRange.MaxMgPerKg, doseRange.ID,
+	)
+	if err != nil {
+		return translateError(err, "failed to update dose range")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("dose range not found", nil)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteDoseRange(id uint) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", drugDoseRangeTable)
+
+	res, err := s.conn().Exec(query, id)
+	if err != nil {
+		return translateError(err, "failed to delete dose range")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("dose range not found", nil)
+	}
+
+	return nil
+}
+
+// GetPetByMedEntry returns pet whose card has medical entry
+func (s *Storage) GetPetByMedEntry(entryID uint) (models.Pet, error) {
+	query := fmt.Sprintf(
+		"SELECT %s.pet_id FROM %s JOIN %s ON %s.id = %s.medical_record_id WHERE %s.id = $1",
+		medRecordTable, medEntryTable, medRecordTable, medRecordTable, medEntryTable, medEntryTable,
+	)
+
+	var petID uint
+	if err := s.conn().QueryRow(query, entryID).Scan(&petID); err != nil {
+		if err == sql.ErrNoRows {
+			return models.Pet{}, errs.NotFound("med entry not found", err)
+		}
+		return models.Pet{}, translateError(err, "failed to get med entry")
+	}
+
+	return s.GetPet(models.Pet{ID: petID})
+}
+
+func (s *Storage) queryPrescriptions(query string, args ...interface{}) ([]models.Prescription, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get prescriptions")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	prescriptions := []models.Prescription{}
+	for rows.Next() {
+		var (
+			prescription models.Prescription
+			startDate    time.Time
+			endDate      sql.NullTime
+		)
+		err := rows.Scan(&prescription.ID, &prescription.MedicalEntryID, &prescription.Drug, &prescription.Dose,
+			&prescription.Unit, &prescription.Route, &prescription.Frequency, &prescription.DurationDays,
+			&startDate, &endDate, &prescription.DoseOverride)
+		if err != nil {
+			return nil, translateError(err, "failed to scan prescription")
+		}
+		prescription.StartDate = startDate.Format(models.DateLayout)
+		if endDate.Valid {
+			prescription.EndDate = endDate.Time.Format(models.DateLayout)
+		}
+		prescriptions = append(prescriptions, prescription)
+	}
+
+	return prescriptions, translateError(rows.Err(), "failed to iterate prescriptions")
+}
+
+// nullableDate stores empty date as NULL
+func nullableDate(date string) sql.NullString {
+	return sql.NullString{String: date, Valid: date != ""}
+}
+
+// nullableDays stores unknown duration as NULL
+func nullableDays(days uint) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(days), Valid: days != 0}
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const researchTransitionTable = "research_status_transition"
+
+// UpdateResearchStatus sets status of pet only if it is still from, so concurrent transitions do not both pass
+func (s *Storage) UpdateResearchStatus(petID uint, from, to string) error {
+	query := fmt.Sprintf("UPDATE %s SET research_status = $3 WHERE id = $1 AND research_status = $2", petsTable)
+
+	res, err := s.conn().Exec(query, petID, from, to)
+	if err != nil {
+		return translateError(err, "failed to update research status")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.Conflict("research status was changed concurrently", nil)
+	}
+
+	return nil
+}
+
+func (s *Storage) CreateResearchTransition(transition models.ResearchTransition) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (pet_id, from_status, to_status, reason, actor_id) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		researchTransitionTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, transition.PetID, transition.FromStatus, transition.ToStatus, transition.Reason,
+		nullableID(transition.ActorID),
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create research transition")
+	}
+
+	return id, nil
+}
+
+// GetResearchTransitions returns research status history of pet, oldest first
+func (s *Storage) GetResearchTransitions(petID uint) ([]models.ResearchTransition, error) {
+	query := fmt.Sprintf(
+		"SELECT id, pet_id, from_status, to_status, reason, COALESCE(actor_id, 0), created_at FROM %s "+
+			"WHERE pet_id = $1 ORDER BY created_at, id",
+		researchTransitionTable,
+	)
+
+	rows, err := s.conn().Query(query, petID)
+	if err != nil {
+		return nil, translateError(err, "failed to get research transitions")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	transitions := []models.ResearchTransition{}
+	for rows.Next() {
+		var transition models.ResearchTransition
+		err := rows.Scan(&transition.ID, &transition.PetID, &transition.FromStatus, &transition.ToStatus,
+			&transition.Reason, &transition.ActorID, &transition.CreatedAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan research transition")
+		}
+		transitions = append(transitions, transition)
+	}
+
+	return transitions, translateError(rows.Err(), "failed to iterate research transitions")
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const speciesTable = "species"
+const speciesAliasTable = "species_alias"
+const breedTable = "breed"
+
+// speciesColumns selects species with its aliases
+const speciesColumns = "species.code, species.names, " +
+	"COALESCE((SELECT array_agg(alias ORDER BY alias) FROM species_alias WHERE species_code = species.code), '{}')"
+
+// CreateSpecies saves species with aliases
+func (s *Storage) CreateSpecies(species models.Species) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		names, err := json.Marshal(species.Names)
+		if err != nil {
+			return fmt.Errorf("failed to marshal names: %w", err)
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (code, names) VALUES ($1, $2)", speciesTable)
+		if _, err := tx.Exec(query, species.Code, names); err != nil {
+			return translateError(err, "failed to create species")
+		}
+
+		return saveSpeciesAliases(tx, species)
+	})
+}
+
+// UpdateSpecies replaces names & aliases of species
+func (s *Storage) UpdateSpecies(species models.Species) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		names, err := json.Marshal(species.Names)
+		if err != nil {
+			return fmt.Errorf("failed to marshal names: %w", err)
+		}
+
+		query := fmt.Sprintf("UPDATE %s SET names = $2 WHERE code = $1", speciesTable)
+		res, err := tx.Exec(query, species.Code, names)
+		if err != nil {
+			return translateError(err, "failed to update species")
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if affected == 0 {
+			return errs.NotFound("species not found", nil)
+		}
+
+		query = fmt.Sprintf("DELETE FROM %s WHERE species_code = $1", speciesAliasTable)
+		if _, err := tx.Exec(query, species.Code); err != nil {
+			return translateError(err, "failed to delete species aliases")
+		}
+
+		return saveSpeciesAliases(tx, species)
+	})
+}
+
+func saveSpeciesAliases(tx *sql.Tx, species models.Species) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (alias, species_code) SELECT DISTINCT lower(trim(a)), $1 FROM unnest($2::text[]) a",
+		speciesAliasTable,
+	)
+	_, err := tx.Exec(query, species.Code, pq.Array(species.Aliases))
+	return translateError(err, "failed to save species aliases")
+}
+
+func (s *Storage) GetSpecies() ([]models.Species, error) {
+	return s.querySpecies(fmt.Sprintf("SELECT %s FROM %s ORDER BY code", speciesColumns, speciesTable))
+}
+
+// FindSpecies returns species by code, alias or localized name, case-insensitive
+func (s *Storage) FindSpecies(name string) (models.Species, error) {
+	species, err := s.querySpecies(
+		fmt.Sprintf("SELECT %s FROM %s WHERE code = species_code_of($1::text)", speciesColumns, speciesTable), name,
+	)
+	if err != nil {
+		return models.Species{}, err
+	}
+	if len(species) == 0 {
+		return models.Species{}, errs.NotFound("species not found", nil)
+	}
+	return species[0], nil
+}
+
+func (s *Storage) querySpecies(query string, args ...interface{}) ([]models.Species, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get species")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	list := []models.Species{}
+	for rows.Next() {
+		var (
+			species models.Species
+			names   []byte
+			aliases pq.StringArray
+		)
+		if err := rows.Scan(&species.Code, &names, &aliases); err != nil {
+			return nil, translateError(err, "failed to scan species")
+		}
+		if err := json.Unmarshal(names, &species.Names); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal species names: %w", err)
+		}
+		species.Aliases = aliases
+		list = append(list, species)
+	}
+
+	return list, translateError(rows.Err(), "failed to iterate species")
+}
+
+func (s *Storage) CreateBreed(breed models.Breed) error {
+	names, err := json.Marshal(breed.Names)
+	if err != nil {
+		return fmt.Errorf("failed to marshal names: %w", err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (species_code, code, names) VALUES ($1, $2, $3)", breedTable)
+	_, err = s.conn().Exec(query, breed.SpeciesCode, breed.Code, names)
+	return translateError(err, "failed to create breed")
+}
+
+// UpdateBreed replaces names of breed
+func (s *Storage) UpdateBreed(breed models.Breed) error {
+	names, err := json.Marshal(breed.Names)
+	if err != nil {
+		return fmt.Errorf("failed to marshal names: %w", err)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET names = $3 WHERE species_code = $1 AND code = $2", breedTable)
+	res, err := s.conn().Exec(query, breed.SpeciesCode, breed.Code, names)
+	if err != nil {
+		return translateError(err, "failed to update breed")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("breed not found", nil)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetBreeds(speciesCode string) ([]models.Breed, error) {
+	return s.queryBreeds(
+		fmt.Sprintf("SELECT species_code, code, names FROM %s WHERE species_code = $1 ORDER BY code", breedTable),
+		speciesCode,
+	)
+}
+
+// FindBreed returns breed of species by code or localized name, case-insensitive
+func (s *Storage) FindBreed(speciesCode, name string) (models.Breed, error) {
+	breeds, err := s.queryBreeds(
+		fmt.Sprintf("SELECT species_code, code, names FROM %s WHERE species_code = $1 AND ("+
+			"lower(code) = lower(trim($2::text)) OR "+
+			"EXISTS (SELECT 1 FROM jsonb_each_text(names) n WHERE lower(n.value) = lower(trim($2::text)))"+
+			") ORDER BY code LIMIT 1", breedTable),
+		speciesCode, name,
+	)
+	if err != nil {
+		return models.Breed{}, err
+	}
+	if len(breeds) == 0 {
+		return models.Breed{}, errs.NotFound("breed not found", nil)
+	}
+	return breeds[0], nil
+}
+
+func (s *Storage) queryBreeds(query string, args ...interface{}) ([]models.Breed, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get breeds")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	breeds := []models.Breed{}
+	for rows.Next() {
+		var (
+			breed models.Breed
+			names []byte
+		)
+		if err := rows.Scan(&breed.SpeciesCode, &breed.Code, &names); err != nil {
+			return nil, translateError(err, "failed to scan breed")
+		}
+		if err := json.Unmarshal(names, &breed.Names); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal breed names: %w", err)
+		}
+		breeds = append(breeds, breed)
+	}
+
+	return breeds, translateError(rows.Err(), "failed to iterate breeds")
+}
+
+// GetUnmappedSpecies returns animal types of pets that are not catalogue codes
+func (s *Storage) GetUnmappedSpecies() ([]models.UnmappedSpecies, error) {
+	query := fmt.Sprintf(
+		"SELECT animal_type, count(*) FROM %s WHERE animal_type NOT IN (SELECT code FROM %s) "+
+			"GROUP BY animal_type ORDER BY animal_type",
+		petsTable, speciesTable,
+	)
+
+	rows, err := s.conn().Query(query)
+	if err != nil {
+		return nil, translateError(err, "failed to get unmapped species")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	unmapped := []models.UnmappedSpecies{}
+	for rows.Next() {
+		var item models.UnmappedSpecies
+		if err := rows.Scan(&item.AnimalType, &item.Pets); err != nil {
+			return nil, translateError(err, "failed to scan unmapped species")
+		}
+		unmapped = append(unmapped, item)
+	}
+
+	return unmapped, translateError(rows.Err(), "failed to iterate unmapped species")
+}
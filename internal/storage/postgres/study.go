@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const (
+	studyTable      = "study"
+	enrollmentTable = "study_enrollment"
+)
+
+const studyColumns = "id, title, protocol_id, principal_vet_id, start_date, end_date, inclusion_criteria"
+
+const enrollmentColumns = "id, study_id, pet_id, arm, consent_date, enrolled_at, withdrawn_at, withdrawal_reason"
+
+func (s *Storage) CreateStudy(study models.Study) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (title, protocol_id, principal_vet_id, start_date, end_date, inclusion_criteria) "+
+			"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		studyTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, study.Title, study.ProtocolID, study.PrincipalVetID, study.StartDate, nullableDate(study.EndDate),
+		study.InclusionCriteria,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create study")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetStudy(id uint) (models.Study, error) {
+	studies, err := s.queryStudies(fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", studyColumns, studyTable), id)
+	if err != nil {
+		return models.Study{}, err
+	}
+	if len(studies) == 0 {
+		return models.Study{}, errs.NotFound("study not found", nil)
+	}
+	return studies[0], nil
+}
+
+// GetStudies returns studies, latest started first
+func (s *Storage) GetStudies() ([]models.Study, error) {
+	return s.queryStudies(fmt.Sprintf("SELECT %s FROM %s ORDER BY start_date DESC, id", studyColumns, studyTable))
+}
+
+func (s *Storage) UpdateStudy(study models.Study) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET title = $1, protocol_id = $2, principal_vet_id = $3, start_date = $4, end_date = $5, "+
+			"inclusion_criteria = $6 WHERE id = $7",
+		studyTable,
+	)
+
+	res, err := s.conn().Exec(
+		query, study.Title, study.ProtocolID, study.PrincipalVetID, study.StartDate, nullableDate(study.EndDate),
+		study.InclusionCriteria, study.ID,
+	)
+	if err != nil {
+		return translateError(err, "failed to update study")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("study not found", nil)
+	}
+	return nil
+}
+
+func (s *Storage) queryStudies(query string, args ...interface{}) ([]models.Study, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get studies")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	studies := []models.Study{}
+	for rows.Next() {
+		var (
+			study     models.Study
+			startDate time.Time
+			endDate   sql.NullTime
+		)
+		err := rows.Scan(&study.ID, &study.Title, &study.ProtocolID, &study.PrincipalVetID, &startDate, &endDate,
+			&study.InclusionCriteria)
+		if err != nil {
+			return nil, translateError(err, "failed to scan study")
+		}
+		study.StartDate = startDate.Format(models.DateLayout)
+		if endDate.Valid {
+			study.EndDate = endDate.Time.Format(models.DateLayout)
+		}
+		studies = append(studies, study)
+	}
+
+	return studies, translateError(rows.Err(), "failed to iterate studies")
+}
+
+func (s *Storage) CreateEnrollment(enrollment models.Enrollment) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (study_id, pet_id, arm, consent_date) VALUES ($1, $2, $3, $4) RETURNING id", enrollmentTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, enrollment.StudyID, enrollment.PetID, enrollment.Arm, enrollment.ConsentDate,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create enrollment")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetEnrollment(studyID, petID uint) (models.Enrollment, error) {
+	enrollments, err := s.queryEnrollments(
+		fmt.Sprintf("SELECT %s FROM %s WHERE study_id = $1 AND pet_id = $2", enrollmentColumns, enrollmentTable),
+		studyID, petID,
+	)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+	if len(enrollments) == 0 {
+		return models.Enrollment{}, errs.NotFound("enrollment not found", nil)
+	}
+	return enrollments[0], nil
+}
+
+// GetEnrollments returns enrollments of study including withdrawn ones, oldest first
+func (s *Storage) GetEnrollments(studyID uint) ([]models.Enrollment, error) {
+	return s.queryEnrollments(
+		fmt.Sprintf("SELECT %s FROM %s WHERE study_id = $1 ORDER BY enrolled_at, id", enrollmentColumns, enrollmentTable),
+		studyID,
+	)
+}
+
+// WithdrawEnrollment marks active enrollment of pet as withdrawn
+func (s *Storage) WithdrawEnrollment(studyID, petID uint, reason string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET withdrawn_at = CURRENT_TIMESTAMP, withdrawal_reason = $3 "+
+			"WHERE study_id = $1 AND pet_id = $2 AND withdrawn_at IS NULL",
+		enrollmentTable,
+	)
+
+	res, err := s.conn().Exec(query, studyID, petID, reason)
+	if err != nil {
+		return translateError(err, "failed to withdraw enrollment")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errs.NotFound("active enrollment not found", nil)
+	}
+	return nil
+}
+
+// WithdrawPetEnrollments marks all active enrollments of pet as withdrawn
+func (s *Storage) WithdrawPetEnrollments(petID uint, reason string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET withdrawn_at = CURRENT_TIMESTAMP, withdrawal_reason = $2 "+
+			"WHERE pet_id = $1 AND withdrawn_at IS NULL",
+		enrollmentTable,
+	)
+
+	if _, err := s.conn().Exec(query, petID, reason); err != nil {
+		return translateError(err, "failed to withdraw enrollments")
+	}
+	return nil
+}
+
+func (s *Storage) CountActiveEnrollments(petID uint) (uint, error) {
+	query := fmt.Sprintf("SELECT count(*) FROM %s WHERE pet_id = $1 AND withdrawn_at IS NULL", enrollmentTable)
+
+	var count uint
+	if err := s.conn().QueryRow(query, petID).Scan(&count); err != nil {
+		return 0, translateError(err, "failed to count enrollments")
+	}
+	return count, nil
+}
+
+func (s *Storage) queryEnrollments(query string, args ...interface{}) ([]models.Enrollment, error) {
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get enrollments")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	enrollments := []models.Enrollment{}
+	for rows.Next() {
+		var (
+			enrollment  models.Enrollment
+			consentDate time.Time
+			withdrawnAt sql.NullTime
+		)
+		err := rows.Scan(&enrollment.ID, &enrollment.StudyID, &enrollment.PetID, &enrollment.Arm, &consentDate,
+			&enrollment.EnrolledAt, &withdrawnAt, &enrollment.WithdrawalReason)
+		if err != nil {
+			return nil, translateError(err, "failed to scan enrollment")
+		}
+		enrollment.ConsentDate = consentDate.Format(models.DateLayout)
+		if withdrawnAt.Valid {
+			enrollment.WithdrawnAt = &withdrawnAt.Time
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, translateError(rows.Err(), "failed to iterate enrollments")
+}
@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+const vaccineTable = "vaccine"
+const vaccinationTable = "vaccination"
+
+// nextDueExpr is next due date of dose. It is NULL for single dose vaccines
+const nextDueExpr = "CASE WHEN vaccine.revaccination_months > 0 THEN " +
+	"(vaccination.administered_at + make_interval(months => vaccine.revaccination_months))::date END"
+
+func (s *Storage) CreateVaccine(vaccine models.Vaccine) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (name, species, revaccination_months) VALUES ($1, $2, $3) RETURNING id", vaccineTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(query, vaccine.Name, vaccine.Species, vaccine.RevaccinationMonths).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create vaccine")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetVaccine(id uint) (models.Vaccine, error) {
+	query := fmt.Sprintf("SELECT id, name, species, revaccination_months FROM %s WHERE id = $1", vaccineTable)
+
+	var vaccine models.Vaccine
+	err := s.conn().QueryRow(query, id).Scan(
+		&vaccine.ID, &vaccine.Name, &vaccine.Species, &vaccine.RevaccinationMonths,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Vaccine{}, errs.NotFound("vaccine not found", err)
+		}
+		return models.Vaccine{}, translateError(err, "failed to get vaccine")
+	}
+
+	return vaccine, nil
+}
+
+// GetVaccines returns catalogue. Empty species returns vaccines of all species
+func (s *Storage) GetVaccines(species string) ([]models.Vaccine, error) {
+	stmt := s.psql.Select("id", "name", "species", "revaccination_months").From(vaccineTable)
+	if species != "" {
+		stmt = stmt.Where("lower(species) = lower(?)", species)
+	}
+	stmt = stmt.OrderBy("species", "name")
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get vaccines")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	vaccines := []models.Vaccine{}
+	for rows.Next() {
+		var vaccine models.Vaccine
+		if err := rows.Scan(&vaccine.ID, &vaccine.Name, &vaccine.Species, &vaccine.RevaccinationMonths); err != nil {
+			return nil, translateError(err, "failed to scan vaccine")
+		}
+		vaccines = append(vaccines, vaccine)
+	}
+
+	return vaccines, translateError(rows.Err(), "failed to iterate vaccines")
+}
+
+func (s *Storage) CreateVaccination(vaccination models.Vaccination) (uint, error) {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (pet_id, vaccine_id, medical_entry_id, veterinarian_id, lot_number, administered_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		vaccinationTable,
+	)
+
+	var id uint
+	err := s.conn().QueryRow(
+		query, vaccination.PetID, vaccination.VaccineID, nullableID(vaccination.MedicalEntryID), vaccination.VetID,
+		vaccination.LotNumber, vaccination.AdministeredAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, translateError(err, "failed to create vaccination")
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetVaccination(id uint) (models.Vaccination, error) {
+	vaccinations, err := s.queryVaccinations("vaccination.id = $1", id)
+	if err != nil {
+		return models.Vaccination{}, err
+	}
+	if len(vaccinations) == 0 {
+		return models.Vaccination{}, errs.NotFound("vaccination not found", nil)
+	}
+	return vaccinations[0], nil
+}
+
+// GetVaccinations returns doses of pet, latest first
+func (s *Storage) GetVaccinations(petID uint) ([]models.Vaccination, error) {
+	return s.queryVaccinations("vaccination.pet_id = $1", petID)
+}
+
+func (s *Storage) queryVaccinations(where string, arg interface{}) ([]models.Vaccination, error) {
+	query := fmt.Sprintf(
+		"SELECT vaccination.id, vaccination.pet_id, vaccination.vaccine_id, vaccine.name, "+
+			"COALESCE(vaccination.medical_entry_id, 0), vaccination.veterinarian_id, vaccination.lot_number, "+
+			"vaccination.administered_at, %s "+
+			"FROM %s JOIN %s ON vaccine.id = vaccination.vaccine_id "+
+			"WHERE %s ORDER BY vaccination.administered_at DESC, vaccination.id DESC",
+		nextDueExpr, vaccinationTable, vaccineTable, where,
+	)
+
+	rows, err := s.conn().Query(query, arg)
+	if err != nil {
+		return nil, translateError(err, "failed to get vaccinations")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	vaccinations := []models.Vaccination{}
+	for rows.Next() {
+		var (
+			vaccination    models.Vaccination
+			administeredAt time.Time
+			nextDueAt      sql.NullTime
+		)
+		err := rows.Scan(&vaccination.ID, &vaccination.PetID, &vaccination.VaccineID, &vaccination.VaccineName,
+			&vaccination.MedicalEntryID, &vaccination.VetID, &vaccination.LotNumber, &administeredAt, &nextDueAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan vaccination")
+		}
+		vaccination.AdministeredAt = administeredAt.Format(models.DateLayout)
+		if nextDueAt.Valid {
+			vaccination.NextDueAt = nextDueAt.Time.Format(models.DateLayout)
+		}
+		vaccinations = append(vaccinations, vaccination)
+	}
+
+	return vaccinations, translateError(rows.Err(), "failed to iterate vaccinations")
+}
+
+// GetDueVaccinations returns revaccinations computed from latest dose of each vaccine of each pet
+func (s *Storage) GetDueVaccinations(filter models.VaccinationDueReqFilter) ([]models.DueVaccination, error) {
+	// latest dose of every pet & vaccine
+	latest := fmt.Sprintf(
+		"WITH latest AS (SELECT DISTINCT ON (pet_id, vaccine_id) pet_id, vaccine_id, administered_at FROM %s "+
+			"ORDER BY pet_id, vaccine_id, administered_at DESC, id DESC)",
+		vaccinationTable,
+	)
+	nextDue := "(latest.administered_at + make_interval(months => vaccine.revaccination_months))::date"
+
+	stmt := s.psql.Select(
+		"pet.id", "pet.name", "pet.animal_type", "medical_record.owner_id", "medical_record.veterinarian_id",
+		"vaccine.id", "vaccine.name", "latest.administered_at", nextDue,
+	).
+		Prefix(latest).
+		From("latest").
+		Join(fmt.Sprintf("%s ON vaccine.id = latest.vaccine_id", vaccineTable)).
+		Join(fmt.Sprintf("%s ON pet.id = latest.pet_id", petsTable)).
+		Join(fmt.Sprintf("%s ON medical_record.pet_id = pet.id", medRecordTable)).
+		Where("vaccine.revaccination_months > 0")
+
+	switch filter.Status {
+	case models.VaccinationDueOverdue:
+		stmt = stmt.Where(nextDue + " < CURRENT_DATE")
+	case models.VaccinationDueUpcoming:
+		stmt = stmt.Where(nextDue+" >= CURRENT_DATE").
+			Where(nextDue+" <= CURRENT_DATE + ?::integer", *filter.WithinDays)
+	}
+	if filter.VetID != nil {
+		stmt = stmt.Where(squirrel.Eq{"medical_record.veterinarian_id": *filter.VetID})
+	}
+	if filter.Species != nil {
+		stmt = stmt.Where("lower(pet.animal_type) = lower(?)", *filter.Species)
+	}
+	if filter.VaccineID != nil {
+		stmt = stmt.Where(squirrel.Eq{"vaccine.id": *filter.VaccineID})
+	}
+	stmt = stmt.OrderBy(nextDue, "pet.id", "vaccine.id")
+	if filter.Limit != nil {
+		stmt = stmt.Limit(uint64(*filter.Limit))
+	}
+	if filter.Offset != nil {
+		stmt = stmt.Offset(uint64(*filter.Offset))
+	}
+
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to get due vaccinations")
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			s.log.WithField("sql", query).Error(err)
+		}
+	}(rows)
+
+	today := time.Now().Format(models.DateLayout)
+	due := []models.DueVaccination{}
+	for rows.Next() {
+		var (
+			vaccination               models.DueVaccination
+			administeredAt, nextDueAt time.Time
+		)
+		err := rows.Scan(&vaccination.PetID, &vaccination.PetName, &vaccination.Species, &vaccination.OwnerID,
+			&vaccination.VetID, &vaccination.VaccineID, &vaccination.VaccineName, &administeredAt, &nextDueAt)
+		if err != nil {
+			return nil, translateError(err, "failed to scan due vaccination")
+		}
+		vaccination.LastAdministeredAt = administeredAt.Format(models.DateLayout)
+		vaccination.NextDueAt = nextDueAt.Format(models.DateLayout)
+		vaccination.Overdue = vaccination.NextDueAt < today
+		due = append(due, vaccination)
+	}
+
+	return due, translateError(rows.Err(), "failed to iterate due vaccinations")
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// GetVet returns vet info. Vets are managed by auth service
+func (s *Storage) GetVet(id uint) (models.Vet, error) {
+	query := fmt.Sprintf(
+		"SELECT id, full_name, email, phone, position, clinic_number FROM %s WHERE id = $1", vetTable,
+	)
+
+	var vet models.Vet
+	err := s.conn().QueryRow(query, id).Scan(
+		&vet.ID, &vet.FullName, &vet.Email, &vet.Phone, &vet.Position, &vet.ClinicNumber,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Vet{}, errs.NotFound("vet not found", err)
+		}
+		return models.Vet{}, translateError(err, "failed to get vet")
+	}
+
+	return vet, nil
+}
@@ -1,6 +1,10 @@
 package storage
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/vet-clinic-back/info-service/internal/config"
 	"github.com/vet-clinic-back/info-service/internal/logging"
 	"github.com/vet-clinic-back/info-service/internal/models"
@@ -12,8 +16,10 @@ type Pet interface {
 	CreatePetWithCard(pet models.Pet, ownderID uint, vetID uint) (uint, error)
 	GetPet(pet models.Pet) (models.Pet, error)
 	GetPetsWithOwnerAndVet(filter models.PetReqFilter) ([]models.OutputPetDTO, error)
+	IteratePetsWithOwnerAndVet(filter models.PetReqFilter, fn func(models.OutputPetDTO) error) error
 	UpdatePet(pet models.Pet) (models.Pet, error)
 	DelPetWithCard(id uint) error
+	GetMedRecordByPet(petID uint) (models.MedicalRecord, error)
 }
 
 type Owner interface {
@@ -24,16 +30,217 @@ type Owner interface {
 	DeleteOwner(id uint) error
 }
 
+type Vet interface {
+	GetVet(id uint) (models.Vet, error)
+}
+
 type MedEntry interface {
 	CreateMedEntry(entry models.MedicalEntry) (uint, error)
+	UpdateMedEntry(entry models.MedicalEntry) error
 	DeleteMedEntry(medRecordID uint, entryID uint) error
 	GetMedEntries(models.EntryReqFilter) ([]models.MedicalEntry, error)
+	IterateMedEntries(filter models.EntryReqFilter, fn func(models.MedicalEntry) error) error
+}
+
+type Allergy interface {
+	CreateAllergy(allergy models.Allergy) (uint, error)
+	GetAllergy(id uint) (models.Allergy, error)
+	GetAllergies(petID uint) ([]models.Allergy, error)
+	GetAllergiesByMedRecord(recordID uint) ([]models.Allergy, error)
+	DeleteAllergy(id uint) error
+	GetAllergenTerms(substance string) ([]string, error)
+	GetAllergenSynonyms() ([]models.AllergenSynonyms, error)
+	SaveAllergenSynonyms(group models.AllergenSynonyms) error
+	DeleteAllergenSynonyms(substance string) error
+	CreateAllergyOverride(override models.AllergyOverride) error
+}
+
+type Species interface {
+	CreateSpecies(species models.Species) error
+	UpdateSpecies(species models.Species) error
+	GetSpecies() ([]models.Species, error)
+	FindSpecies(name string) (models.Species, error)
+	CreateBreed(breed models.Breed) error
+	UpdateBreed(breed models.Breed) error
+	GetBreeds(speciesCode string) ([]models.Breed, error)
+	FindBreed(speciesCode, name string) (models.Breed, error)
+	GetUnmappedSpecies() ([]models.UnmappedSpecies, error)
 }
 
 type Info interface {
 	Owner
 	Pet
+	Vet
 	MedEntry
+	Allergy
+	Species
+}
+
+type Import interface {
+	CreateImportJob(job models.ImportJob) (uint, error)
+	GetImportJob(id uint) (models.ImportJob, error)
+	UpdateImportJob(job models.ImportJob) error
+	ClaimImportJob(id uint, statuses []string, staleAfter time.Duration) (models.ImportJob, error)
+	GetImportPetRef(jobID uint, ref string) (uint, error)
+	SaveImportPetRef(jobID uint, ref string, petID uint) error
+}
+
+type Lab interface {
+	CreateLabMessage(msg models.LabMessage) (uint, error)
+	GetLabMessage(id uint) (models.LabMessage, error)
+	GetLabMessages(status string) ([]models.LabMessage, error)
+	UpdateLabMessage(msg models.LabMessage) error
+	CreateLabResult(result models.LabResult) (uint, error)
+	GetLabResults(filter models.LabResultReqFilter) ([]models.LabResult, error)
+}
+
+type Vaccination interface {
+	CreateVaccine(vaccine models.Vaccine) (uint, error)
+	GetVaccine(id uint) (models.Vaccine, error)
+	GetVaccines(species string) ([]models.Vaccine, error)
+	CreateVaccination(vaccination models.Vaccination) (uint, error)
+	GetVaccination(id uint) (models.Vaccination, error)
+	GetVaccinations(petID uint) ([]models.Vaccination, error)
+	GetDueVaccinations(filter models.VaccinationDueReqFilter) ([]models.DueVaccination, error)
+}
+
+type Prescription interface {
+	CreatePrescription(prescription models.Prescription) (uint, error)
+	GetPrescription(id uint) (models.Prescription, error)
+	GetPrescriptions(entryID uint) ([]models.Prescription, error)
+	GetActivePrescriptions(petID uint, date string) ([]models.Prescription, error)
+	UpdatePrescription(prescription models.Prescription) error
+	DeletePrescription(id uint) error
+	GetDoseRange(drug, species string) (models.DoseRange, error)
+	CreateDoseRange(doseRange models.DoseRange) (uint, error)
+	GetDoseRanges(drug string) ([]models.DoseRange, error)
+	UpdateDoseRange(doseRange models.DoseRange) error
+	DeleteDoseRange(id uint) error
+	GetPetByMedEntry(entryID uint) (models.Pet, error)
+}
+
+type Measurement interface {
+	CreateMeasurement(measurement models.Measurement) (uint, error)
+	GetMeasurement(id uint) (models.Measurement, error)
+	GetMeasurements(petID uint) ([]models.Measurement, error)
+}
+
+type Research interface {
+	UpdateResearchStatus(petID uint, from, to string) error
+	CreateResearchTransition(transition models.ResearchTransition) (uint, error)
+	GetResearchTransitions(petID uint) ([]models.ResearchTransition, error)
+}
+
+type Study interface {
+	CreateStudy(study models.Study) (uint, error)
+	GetStudy(id uint) (models.Study, error)
+	GetStudies() ([]models.Study, error)
+	UpdateStudy(study models.Study) error
+	CreateEnrollment(enrollment models.Enrollment) (uint, error)
+	GetEnrollment(studyID, petID uint) (models.Enrollment, error)
+	GetEnrollments(studyID uint) ([]models.Enrollment, error)
+	WithdrawEnrollment(studyID, petID uint, reason string) error
+	WithdrawPetEnrollments(petID uint, reason string) error
+	CountActiveEnrollments(petID uint) (uint, error)
+}
+
+type Appointment interface {
+	CreateAppointment(appointment models.Appointment) (uint, error)
+	GetAppointment(id uint) (models.Appointment, error)
+	GetAppointments(filter models.AppointmentReqFilter) ([]models.Appointment, error)
+	GetOverlappingAppointments(appointment models.Appointment) ([]models.Appointment, error)
+	UpdateAppointment(appointment models.Appointment, from string) error
+	LockVetSchedule(vetID uint) error
+}
+
+type Availability interface {
+	GetClinic(number string) (models.Clinic, error)
+	GetClinics() ([]models.Clinic, error)
+	SaveClinic(clinic models.Clinic) error
+	GetVetSchedule(vetID uint) ([]models.WorkingHours, error)
+	SaveVetSchedule(vetID uint, hours []models.WorkingHours) error
+	CreateScheduleException(exception models.ScheduleException) (uint, error)
+	GetScheduleExceptions(vetID uint, from, to string) ([]models.ScheduleException, error)
+	DeleteScheduleException(vetID, id uint) error
+	CreateTimeBlock(block models.TimeBlock) (uint, error)
+	GetTimeBlock(id uint) (models.TimeBlock, error)
+	GetTimeBlocks(vetID uint, from, to time.Time) ([]models.TimeBlock, error)
+	DeleteTimeBlock(vetID, id uint) error
+}
+
+type Calendar interface {
+	CreateCalendarFeed(feed models.CalendarFeed, tokenHash string) (models.CalendarFeed, error)
+	GetCalendarFeeds(kind string, subjectID uint) ([]models.CalendarFeed, error)
+	GetCalendarFeedByToken(tokenHash string) (models.CalendarFeed, error)
+	RevokeCalendarFeed(kind string, subjectID, id uint) error
+	GetFollowUps(filter models.FollowUpReqFilter) ([]models.FollowUp, error)
+}
+
+type Notification interface {
+	GetNotificationPreferences(ownerID uint) (models.NotificationPreferences, error)
+	SaveNotificationPreferences(prefs models.NotificationPreferences) error
+	CreateReminder(reminder models.Reminder) (uint, error)
+	GetReminder(id uint) (models.Reminder, error)
+	GetReminders(filter models.ReminderReqFilter) ([]models.Reminder, error)
+	ScheduleEntryReminders(newSince time.Time, followUpLead time.Duration, timeZone string) (int64, error)
+	GetDueReminders(now time.Time, limit uint) ([]models.Reminder, error)
+	UpdateReminderStatus(id uint, from, to string) error
+	SettleReminder(id uint) error
+	CreateNotification(notification models.Notification) (uint, error)
+	GetNotifications(filter models.NotificationReqFilter) ([]models.Notification, error)
+	ClaimNotifications(now, leaseUntil time.Time, limit uint) ([]models.Notification, error)
+	UpdateNotification(notification models.Notification) error
+}
+
+type Export interface {
+	CreateExportJob(job models.ExportJob) (uint, error)
+	GetExportJob(id uint) (models.ExportJob, error)
+	UpdateExportJob(job models.ExportJob) error
+	GetExpiredExportJobs(before time.Time) ([]models.ExportJob, error)
+	FailStaleExportJobs(staleAfter time.Duration, reason string) (int64, error)
+	SaveExportFile(jobID uint, r io.Reader) (int64, error)
+	IterateExportFile(jobID uint, fn func([]byte) error) error
+	DeleteExportFile(jobID uint) error
+}
+
+type Idempotency interface {
+	ReserveIdempotencyKey(rec models.IdempotencyRecord) (bool, error)
+	GetIdempotencyKey(scope, key string) (models.IdempotencyRecord, error)
+	SaveIdempotencyResponse(rec models.IdempotencyRecord) error
+	DeleteIdempotencyKey(scope, key string) error
+	DeleteExpiredIdempotencyKeys() (int64, error)
+}
+
+type Job interface {
+	LockJob(ctx context.Context, name string) (unlock func() error, ok bool, err error)
+	SaveJobSchedule(name, schedule string, nextRunAt time.Time) error
+	GetJobNextRun(name string) (time.Time, error)
+	SetJobNextRun(name string, next time.Time) error
+	GetScheduledJobs() ([]models.ScheduledJob, error)
+	CreateJobRun(run models.JobRun) (uint, error)
+	FinishJobRun(run models.JobRun) error
+	FailRunningJobRuns(name, reason string, finishedAt time.Time) (int64, error)
+	DeleteJobRun(id uint) error
+	GetJobRuns(filter models.JobRunReqFilter) ([]models.JobRun, error)
+	DeleteJobRuns(before time.Time) (int64, error)
+}
+
+// Tx is storage bound to one transaction
+type Tx interface {
+	Info
+	Import
+	Lab
+	Research
+	Study
+	Appointment
+	Availability
+	Notification
+}
+
+// Transactor runs several storage calls in one transaction. Failed call inside fn
+// does not abort transaction, it is rolled back only if fn returns error
+type Transactor interface {
+	WithTx(fn func(tx Tx) error) error
 }
 
 type StorageProcess interface {
@@ -42,6 +249,21 @@ type StorageProcess interface {
 
 type Storage struct {
 	Info
+	Import
+	Lab
+	Vaccination
+	Prescription
+	Measurement
+	Research
+	Study
+	Appointment
+	Availability
+	Calendar
+	Notification
+	Export
+	Idempotency
+	Job
+	Transactor
 	StorageProcess
 }
 
@@ -49,6 +271,31 @@ func New(log *logging.Logger, cfg *config.DbConfig) *Storage {
 	pg := postgres.New(log, cfg)
 	return &Storage{
 		Info:           pg,
+		Import:         pg,
+		Lab:            pg,
+		Vaccination:    pg,
+		Prescription:   pg,
+		Measurement:    pg,
+		Research:       pg,
+		Study:          pg,
+		Appointment:    pg,
+		Availability:   pg,
+		Calendar:       pg,
+		Notification:   pg,
+		Export:         pg,
+		Idempotency:    pg,
+		Job:            pg,
+		Transactor:     pgTransactor{pg: pg},
 		StorageProcess: pg,
 	}
 }
+
+type pgTransactor struct {
+	pg *postgres.Storage
+}
+
+func (t pgTransactor) WithTx(fn func(tx Tx) error) error {
+	return t.pg.WithTx(func(tx *postgres.Storage) error {
+		return fn(tx)
+	})
+}
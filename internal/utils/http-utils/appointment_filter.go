@@ -0,0 +1,67 @@
+package http_utils
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func ParseAppointmentFilters(c *gin.Context) (models.AppointmentReqFilter, error) {
+	var filters models.AppointmentReqFilter
+
+	if status, ok := c.GetQuery("status"); ok {
+		filters.Status = &status
+	}
+
+	vetID, err := getUint64Param("vet_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.VetID = vetID
+
+	petID, err := getUint64Param("pet_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.PetID = petID
+
+	from, err := getTimeParam("from", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.From = from
+
+	to, err := getTimeParam("to", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.To = to
+
+	offset, err := getUint64Param("offset", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Offset = offset
+
+	limit, err := getUint64Param("limit", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Limit = limit
+
+	return filters, nil
+}
+
+// getTimeParam returns *time.Time param in RFC 3339. Nil if param not exists
+func getTimeParam(param string, c *gin.Context) (*time.Time, error) {
+	stringParam, ok := c.GetQuery(param)
+	if !ok {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, stringParam)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
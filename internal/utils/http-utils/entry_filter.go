@@ -7,6 +7,7 @@ import (
 
 // @Param entry_id query int false "Entry ID"
 // @Param pet_id query int false "Pet ID"
+// @Param vet_id query int false "Vet who made entry"
 
 func ParseEntryFilters(c *gin.Context) (models.EntryReqFilter, error) {
 	var filters models.EntryReqFilter
@@ -23,6 +24,12 @@ func ParseEntryFilters(c *gin.Context) (models.EntryReqFilter, error) {
 	}
 	filters.EntryID = entryID
 
+	vetID, err := getUint64Param("vet_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.VetID = vetID
+
 	offset, err := getUint64Param("offset", c)
 	if err != nil {
 		return filters, err
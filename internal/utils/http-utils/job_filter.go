@@ -0,0 +1,24 @@
+package http_utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func ParseJobRunFilters(c *gin.Context) (models.JobRunReqFilter, error) {
+	var filters models.JobRunReqFilter
+
+	offset, err := getUint64Param("offset", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Offset = offset
+
+	limit, err := getUint64Param("limit", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Limit = limit
+
+	return filters, nil
+}
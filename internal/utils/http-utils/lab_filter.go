@@ -0,0 +1,30 @@
+package http_utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func ParseLabResultFilters(c *gin.Context) (models.LabResultReqFilter, error) {
+	var filters models.LabResultReqFilter
+
+	petID, err := getUint64Param("pet_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.PetID = petID
+
+	offset, err := getUint64Param("offset", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Offset = offset
+
+	limit, err := getUint64Param("limit", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Limit = limit
+
+	return filters, nil
+}
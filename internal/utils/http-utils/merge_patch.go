@@ -0,0 +1,60 @@
+package http_utils
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+var ErrInvalidMergePatch = errors.New("invalid merge patch")
+
+// IsMergePatch reports whether content type header is application/merge-patch+json
+func IsMergePatch(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == MergePatchContentType
+}
+
+// ApplyMergePatch applies RFC 7386 merge patch to json document.
+// null in patch removes field, absent field stays unchanged.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, ErrInvalidMergePatch
+	}
+
+	var docValue interface{}
+	if len(doc) != 0 {
+		if err := json.Unmarshal(doc, &docValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(mergePatch(docValue, patchValue))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+
+	return targetObj
+}
@@ -0,0 +1,74 @@
+package http_utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func ParseReminderFilters(c *gin.Context) (models.ReminderReqFilter, error) {
+	var filters models.ReminderReqFilter
+
+	if status, ok := c.GetQuery("status"); ok {
+		filters.Status = &status
+	}
+
+	ownerID, err := getUint64Param("owner_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.OwnerID = ownerID
+
+	petID, err := getUint64Param("pet_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.PetID = petID
+
+	offset, err := getUint64Param("offset", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Offset = offset
+
+	limit, err := getUint64Param("limit", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Limit = limit
+
+	return filters, nil
+}
+
+func ParseNotificationFilters(c *gin.Context) (models.NotificationReqFilter, error) {
+	var filters models.NotificationReqFilter
+
+	if status, ok := c.GetQuery("status"); ok {
+		filters.Status = &status
+	}
+
+	ownerID, err := getUint64Param("owner_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.OwnerID = ownerID
+
+	reminderID, err := getUint64Param("reminder_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.ReminderID = reminderID
+
+	offset, err := getUint64Param("offset", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Offset = offset
+
+	limit, err := getUint64Param("limit", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Limit = limit
+
+	return filters, nil
+}
@@ -26,6 +26,22 @@ func ParsePetFilters(c *gin.Context) (models.PetReqFilter, error) {
 	}
 	filters.OwnerID = ownerID
 
+	ageFrom, err := getUint64Param("age_from", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.AgeFrom = ageFrom
+
+	ageTo, err := getUint64Param("age_to", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.AgeTo = ageTo
+
+	if researchStatus, ok := c.GetQuery("research_status"); ok {
+		filters.ResearchStatus = &researchStatus
+	}
+
 	offset, err := getUint64Param("offset", c)
 	if err != nil {
 		return filters, err
@@ -0,0 +1,22 @@
+package http_utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	NDJSONContentType = "application/x-ndjson"
+	CSVContentType    = "text/csv"
+)
+
+// StreamFormat returns streamed format of listing requested by Accept header. It is empty for JSON array,
+// which is default & is also returned for unsupported types
+func StreamFormat(c *gin.Context) string {
+	switch format := c.NegotiateFormat(binding.MIMEJSON, NDJSONContentType, CSVContentType); format {
+	case NDJSONContentType, CSVContentType:
+		return format
+	default:
+		return ""
+	}
+}
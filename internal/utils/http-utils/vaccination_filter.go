@@ -0,0 +1,46 @@
+package http_utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func ParseVaccinationDueFilters(c *gin.Context) (models.VaccinationDueReqFilter, error) {
+	filters := models.VaccinationDueReqFilter{Status: c.Query("status")}
+
+	if species, ok := c.GetQuery("species"); ok {
+		filters.Species = &species
+	}
+
+	vetID, err := getUint64Param("vet_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.VetID = vetID
+
+	vaccineID, err := getUint64Param("vaccine_id", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.VaccineID = vaccineID
+
+	withinDays, err := getUint64Param("within_days", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.WithinDays = withinDays
+
+	offset, err := getUint64Param("offset", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Offset = offset
+
+	limit, err := getUint64Param("limit", c)
+	if err != nil {
+		return filters, err
+	}
+	filters.Limit = limit
+
+	return filters, nil
+}
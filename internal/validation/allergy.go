@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+func ValidateCreatingAllergy(allergy models.Allergy) error {
+	v := &validator{}
+
+	if v.required("kind", allergy.Kind) {
+		v.oneOf("kind", allergy.Kind, models.AllergyKindAllergy, models.AllergyKindCondition)
+	}
+	if v.required("substance", allergy.Substance) {
+		v.maxLen("substance", allergy.Substance, maxShortText)
+	}
+	v.maxLen("reaction", allergy.Reaction, maxLongText)
+	if v.required("severity", allergy.Severity) {
+		v.oneOf("severity", allergy.Severity,
+			models.AllergySeverityLow, models.AllergySeverityModerate, models.AllergySeverityHigh)
+	}
+	v.positiveID("recorded_by", allergy.RecordedBy)
+
+	return v.result()
+}
+
+func ValidateAllergenSynonyms(group models.AllergenSynonyms) error {
+	v := &validator{}
+
+	if v.required("substance", group.Substance) {
+		v.maxLen("substance", group.Substance, maxShortText)
+	}
+	if len(group.Synonyms) == 0 {
+		v.add("synonyms", CodeRequired, "synonyms is required")
+	}
+	for i, synonym := range group.Synonyms {
+		field := fmt.Sprintf("synonyms[%d]", i)
+		if v.required(field, synonym) {
+			v.maxLen(field, synonym, maxShortText)
+		}
+	}
+
+	return v.result()
+}
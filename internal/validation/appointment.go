@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+var appointmentStatuses = []string{
+	models.AppointmentStatusBooked, models.AppointmentStatusCheckedIn, models.AppointmentStatusCompleted,
+	models.AppointmentStatusNoShow, models.AppointmentStatusCancelled,
+}
+
+func ValidateBookingAppointment(appointment models.Appointment) error {
+	v := &validator{}
+
+	v.positiveID("pet_id", appointment.PetID)
+	v.positiveID("vet_id", appointment.VetID)
+	v.maxLen("clinic_number", appointment.ClinicNumber, 64)
+	v.maxLen("reason", appointment.Reason, maxLongText)
+	v.period(appointment.StartsAt, appointment.EndsAt)
+
+	return v.result()
+}
+
+func ValidateReschedulingAppointment(input models.ReschedulingAppointmentDTO) error {
+	v := &validator{}
+
+	v.period(input.StartsAt, input.EndsAt)
+
+	return v.result()
+}
+
+func ValidateCancellingAppointment(input models.CancellingAppointmentDTO) error {
+	v := &validator{}
+
+	v.maxLen("reason", input.Reason, maxLongText)
+
+	return v.result()
+}
+
+func ValidateAppointmentFilter(filter models.AppointmentReqFilter) error {
+	v := &validator{}
+
+	if filter.Status != nil {
+		v.oneOf("status", *filter.Status, appointmentStatuses...)
+	}
+
+	return v.result()
+}
+
+// period checks appointment time. Appointments can not be booked in the past
+func (v *validator) period(startsAt, endsAt time.Time) {
+	if startsAt.IsZero() {
+		v.add("starts_at", CodeRequired, "starts_at is required")
+		return
+	}
+	if startsAt.Before(time.Now()) {
+		v.add("starts_at", CodeOutOfRange, "starts_at should not be in the past")
+	}
+	if !endsAt.After(startsAt) {
+		v.add("ends_at", CodeOutOfRange, "ends_at should be after starts_at")
+	}
+}
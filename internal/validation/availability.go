@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+const (
+	// maxAvailabilityDays limits period of availability query
+	maxAvailabilityDays = 31
+	minSlotMinutes      = 5
+	maxSlotMinutes      = 8 * 60
+)
+
+var scheduleExceptionKinds = []string{
+	models.ScheduleExceptionVacation, models.ScheduleExceptionSickLeave, models.ScheduleExceptionOther,
+}
+
+func ValidateClinic(clinic models.Clinic) error {
+	v := &validator{}
+
+	if v.required("number", clinic.Number) {
+		v.maxLen("number", clinic.Number, 64)
+	}
+	v.maxLen("name", clinic.Name, maxShortText)
+	if v.required("time_zone", clinic.TimeZone) {
+		if _, err := time.LoadLocation(clinic.TimeZone); err != nil {
+			v.add("time_zone", CodeInvalidEnum, "time_zone should be IANA time zone, e.g. Europe/Moscow")
+		}
+	}
+	v.workingHours("hours", clinic.Hours)
+
+	return v.result()
+}
+
+func ValidateVetSchedule(hours []models.WorkingHours) error {
+	v := &validator{}
+
+	v.workingHours("hours", hours)
+
+	return v.result()
+}
+
+func ValidateScheduleException(exception models.ScheduleException) error {
+	v := &validator{}
+
+	v.positiveID("vet_id", exception.VetID)
+	if v.required("kind", exception.Kind) {
+		v.oneOf("kind", exception.Kind, scheduleExceptionKinds...)
+	}
+	v.maxLen("note", exception.Note, maxLongText)
+
+	startOK := v.required("start_date", exception.StartDate) && v.date("start_date", exception.StartDate, true)
+	endOK := v.required("end_date", exception.EndDate) && v.date("end_date", exception.EndDate, true)
+	// YYYY-MM-DD dates compare as strings
+	if startOK && endOK && exception.EndDate < exception.StartDate {
+		v.add("end_date", CodeOutOfRange, "end_date should not be before start_date")
+	}
+
+	return v.result()
+}
+
+func ValidateTimeBlock(block models.TimeBlock) error {
+	v := &validator{}
+
+	v.positiveID("vet_id", block.VetID)
+	v.maxLen("reason", block.Reason, maxLongText)
+	v.period(block.StartsAt, block.EndsAt)
+
+	return v.result()
+}
+
+func ValidateAvailabilityFilter(filter models.AvailabilityReqFilter) error {
+	v := &validator{}
+
+	fromOK := v.required("from", filter.From) && v.date("from", filter.From, true)
+	toOK := v.required("to", filter.To) && v.date("to", filter.To, true)
+	if fromOK && toOK {
+		from, _ := time.Parse(models.DateLayout, filter.From)
+		to, _ := time.Parse(models.DateLayout, filter.To)
+		if to.Before(from) {
+			v.add("to", CodeOutOfRange, "to should not be before from")
+		} else if to.Sub(from) >= maxAvailabilityDays*24*time.Hour {
+			v.add("to", CodeOutOfRange, fmt.Sprintf("period should be at most %d days", maxAvailabilityDays))
+		}
+	}
+	if filter.Duration < minSlotMinutes || filter.Duration > maxSlotMinutes {
+		v.add("duration", CodeOutOfRange,
+			fmt.Sprintf("duration should be %d-%d minutes", minSlotMinutes, maxSlotMinutes))
+	}
+
+	return v.result()
+}
+
+// workingHours checks weekly intervals, times are HH:MM
+func (v *validator) workingHours(field string, hours []models.WorkingHours) {
+	for i, h := range hours {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+		if h.Weekday < 0 || h.Weekday > 6 {
+			v.add(prefix+".weekday", CodeOutOfRange, "weekday should be 0-6, 0 is Sunday")
+		}
+		start, startErr := time.Parse("15:04", h.Start)
+		if startErr != nil {
+			v.add(prefix+".start", CodeInvalidFormat, "start should be time HH:MM")
+		}
+		end, endErr := time.Parse("15:04", h.End)
+		if endErr != nil {
+			v.add(prefix+".end", CodeInvalidFormat, "end should be time HH:MM")
+		}
+		if startErr == nil && endErr == nil && !end.After(start) {
+			v.add(prefix+".end", CodeOutOfRange, "end should be after start")
+		}
+	}
+}
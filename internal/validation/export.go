@@ -0,0 +1,37 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+func ValidateCreatingExport(input models.CreatingExportDTO) error {
+	v := &validator{}
+
+	if v.required("type", input.Type) {
+		v.oneOf("type", input.Type, models.ExportTypeResearch, models.ExportTypePets, models.ExportTypeEntries)
+	}
+	if v.required("format", input.Format) {
+		v.oneOf("format", input.Format, models.ExportFormatCSV, models.ExportFormatParquet)
+	}
+
+	pets := input.Criteria.Pets
+	if pets.ResearchStatus != nil {
+		// research exports never include pets without consent
+		if input.Type == models.ExportTypeResearch {
+			v.oneOf("criteria.pets.research_status", *pets.ResearchStatus, models.ConsentedResearchStatuses...)
+		} else {
+			v.oneOf("criteria.pets.research_status", *pets.ResearchStatus, researchStatuses...)
+		}
+	}
+	if pets.AgeFrom != nil && pets.AgeTo != nil && *pets.AgeFrom > *pets.AgeTo {
+		v.add("criteria.pets.age_to", CodeOutOfRange, "age_to should not be less than age_from")
+	}
+
+	entries := input.Criteria.Entries
+	fromOK := entries.DateFrom == nil || v.date("criteria.entries.date_from", *entries.DateFrom, true)
+	toOK := entries.DateTo == nil || v.date("criteria.entries.date_to", *entries.DateTo, true)
+	// YYYY-MM-DD dates compare as strings
+	if fromOK && toOK && entries.DateFrom != nil && entries.DateTo != nil && *entries.DateTo < *entries.DateFrom {
+		v.add("criteria.entries.date_to", CodeOutOfRange, "date_to should not be before date_from")
+	}
+
+	return v.result()
+}
@@ -0,0 +1,10 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+// ValidateResolvingLabReview validates pet chosen for message from review queue
+func ValidateResolvingLabReview(input models.ResolveLabReviewDTO) error {
+	v := &validator{}
+	v.positiveID("pet_id", input.PetID)
+	return v.result()
+}
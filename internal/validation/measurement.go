@@ -0,0 +1,21 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+const maxBodyConditionScore = 9
+
+func ValidateCreatingMeasurement(measurement models.Measurement) error {
+	v := &validator{}
+
+	if v.required("measured_at", measurement.MeasuredAt) {
+		v.date("measured_at", measurement.MeasuredAt, false)
+	}
+	if measurement.Weight <= 0 {
+		v.add("weight", CodeMustBePositive, "weight should be > 0")
+	}
+	if measurement.BodyConditionScore > maxBodyConditionScore {
+		v.add("body_condition_score", CodeOutOfRange, "body_condition_score should be 1-9")
+	}
+
+	return v.result()
+}
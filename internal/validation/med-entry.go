@@ -0,0 +1,46 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+func ValidateCreatingMedEntry(entry models.CreatingMedEntry) error {
+	v := &validator{}
+
+	v.positiveID("medical_record_id", entry.MedicalRecordID)
+	v.positiveID("vet_id", entry.VetID)
+	validateMedEntryText(v, entry.MedicalEntry)
+	v.maxLen("allergy_override_reason", entry.AllergyOverrideReason, maxLongText)
+
+	return v.result()
+}
+
+// ValidateUpdatingMedEntry validates entry of batch update. Med record of entry can not be changed
+func ValidateUpdatingMedEntry(entry models.CreatingMedEntry) error {
+	v := &validator{}
+
+	v.positiveID("id", entry.ID)
+	v.positiveID("vet_id", entry.VetID)
+	validateMedEntryText(v, entry.MedicalEntry)
+	v.maxLen("allergy_override_reason", entry.AllergyOverrideReason, maxLongText)
+
+	return v.result()
+}
+
+// ValidateImportedMedEntry validates entry of import row. medical record is known only after pet is created
+func ValidateImportedMedEntry(entry models.MedicalEntry) error {
+	v := &validator{}
+
+	v.positiveID("vet_id", entry.VetID)
+	validateMedEntryText(v, entry)
+
+	return v.result()
+}
+
+func validateMedEntryText(v *validator, entry models.MedicalEntry) {
+	v.maxLen("description", entry.Description, maxLongText)
+	v.maxLen("disease", entry.Disease, maxLongText)
+	v.maxLen("vaccinations", entry.Vaccinations, maxLongText)
+	v.maxLen("recommendation", entry.Recommendation, maxLongText)
+	if entry.FollowUpAt != "" {
+		v.date("follow_up_at", entry.FollowUpAt, true)
+	}
+}
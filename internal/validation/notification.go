@@ -0,0 +1,56 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+var reminderStatuses = []string{
+	models.ReminderStatusPending, models.ReminderStatusQueued, models.ReminderStatusSent,
+	models.ReminderStatusFailed, models.ReminderStatusSkipped, models.ReminderStatusCancelled,
+}
+
+var notificationStatuses = []string{
+	models.NotificationStatusPending, models.NotificationStatusSent, models.NotificationStatusFailed,
+}
+
+func ValidateNotificationPreferences(prefs models.NotificationPreferences) error {
+	v := &validator{}
+
+	if v.required("language", prefs.Language) {
+		v.oneOf("language", prefs.Language, models.LanguageRU, models.LanguageEN)
+	}
+
+	return v.result()
+}
+
+func ValidateCreatingReminder(input models.CreatingReminderDTO) error {
+	v := &validator{}
+
+	v.positiveID("pet_id", input.PetID)
+	if input.DueAt.IsZero() {
+		v.add("due_at", CodeRequired, "due_at is required")
+	}
+	if v.required("message", input.Message) {
+		v.maxLen("message", input.Message, maxLongText)
+	}
+
+	return v.result()
+}
+
+func ValidateReminderFilter(filter models.ReminderReqFilter) error {
+	v := &validator{}
+
+	if filter.Status != nil {
+		v.oneOf("status", *filter.Status, reminderStatuses...)
+	}
+
+	return v.result()
+}
+
+func ValidateNotificationFilter(filter models.NotificationReqFilter) error {
+	v := &validator{}
+
+	if filter.Status != nil {
+		v.oneOf("status", *filter.Status, notificationStatuses...)
+	}
+
+	return v.result()
+}
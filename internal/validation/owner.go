@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"net/mail"
+	"regexp"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+// phoneRegexp accepts international format: optional '+' and 10-15 digits
+var phoneRegexp = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
+func ValidateCreatingOwner(owner models.Owner) error {
+	v := &validator{}
+
+	if v.required("fullname", owner.FullName) {
+		v.maxLen("fullname", owner.FullName, 255)
+	}
+	if v.required("email", owner.Email) {
+		validateEmail(v, owner.Email)
+	}
+	if v.required("phone", owner.Phone) {
+		validatePhone(v, owner.Phone)
+	}
+	v.required("password_hash", owner.PasswordHash)
+
+	return v.result()
+}
+
+// ValidateImportedOwner validates owner of import row. Imported owners have no password,
+// email or phone is required to find them later
+func ValidateImportedOwner(owner models.Owner) error {
+	v := &validator{}
+
+	if v.required("fullname", owner.FullName) {
+		v.maxLen("fullname", owner.FullName, 255)
+	}
+	if owner.Email == "" && owner.Phone == "" {
+		v.add("email", CodeRequired, "email or phone is required")
+	}
+	if owner.Email != "" {
+		validateEmail(v, owner.Email)
+	}
+	if owner.Phone != "" {
+		validatePhone(v, owner.Phone)
+	}
+
+	return v.result()
+}
+
+// ValidateUpdatingOwner validates only present fields, empty ones are not updated
+func ValidateUpdatingOwner(owner models.Owner) error {
+	v := &validator{}
+
+	v.maxLen("fullname", owner.FullName, 255)
+	if owner.Email != "" {
+		validateEmail(v, owner.Email)
+	}
+	if owner.Phone != "" {
+		validatePhone(v, owner.Phone)
+	}
+
+	return v.result()
+}
+
+func validateEmail(v *validator, email string) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		v.add("email", CodeInvalidFormat, "email should be valid address like user@example.com")
+		return
+	}
+	v.maxLen("email", email, 255)
+}
+
+func validatePhone(v *validator, phone string) {
+	if !phoneRegexp.MatchString(phone) {
+		v.add("phone", CodeInvalidFormat, "phone should contain 10-15 digits with optional leading '+'")
+	}
+}
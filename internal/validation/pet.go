@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+const (
+	GenderMale   = "Male"
+	GenderFemale = "Female"
+)
+
+// microchipPattern is ISO 11784/11785 15-digit code. Older 9-10 character chips are allowed too
+var microchipPattern = regexp.MustCompile(`^([0-9]{15}|[0-9A-Za-z]{9,10})$`)
+
+const (
+	maxShortText = 128
+	maxLongText  = 2048
+)
+
+// ValidateCreatingPet validates pet on creation. All fields are required, age can be given
+// instead of birth date
+func ValidateCreatingPet(pet models.Pet) error {
+	v := &validator{}
+	validatePetCommon(v, pet)
+
+	if pet.BirthDate == "" && pet.Age == 0 {
+		v.add("birth_date", CodeRequired, "birth_date or age is required")
+	}
+	// new pet is candidate, later stages are reached with research status transitions
+	if pet.ResearchStatus != "" {
+		v.oneOf("research_status", pet.ResearchStatus, models.ResearchStatusCandidate)
+	}
+	if pet.Weight <= 0 {
+		v.add("weight", CodeMustBePositive, "weight should be > 0")
+	}
+	if v.required("condition", pet.Condition) {
+		v.maxLen("condition", pet.Condition, maxLongText)
+	}
+	if v.required("behavior", pet.Behavior) {
+		v.maxLen("behavior", pet.Behavior, maxLongText)
+	}
+
+	return v.result()
+}
+
+// ValidateUpdatingPet validates full pet state before replacing. condition & behavior can be cleared,
+// birth date, age & weight can be empty if unknown
+func ValidateUpdatingPet(pet models.Pet) error {
+	v := &validator{}
+	validateUpdatingPet(v, pet)
+	return v.result()
+}
+
+// ValidateBatchUpdatingPet validates pet of batch update, its ID is in body
+func ValidateBatchUpdatingPet(pet models.Pet) error {
+	v := &validator{}
+	v.positiveID("id", pet.ID)
+	validateUpdatingPet(v, pet)
+	return v.result()
+}
+
+func validateUpdatingPet(v *validator, pet models.Pet) {
+	validatePetCommon(v, pet)
+
+	if pet.Weight < 0 {
+		v.add("weight", CodeMustBePositive, "weight should not be negative")
+	}
+	if pet.ResearchStatus != "" {
+		v.oneOf("research_status", pet.ResearchStatus, researchStatuses...)
+	}
+	v.maxLen("condition", pet.Condition, maxLongText)
+	v.maxLen("behavior", pet.Behavior, maxLongText)
+}
+
+// ValidatePetCard validates ids of pet medical card
+func ValidatePetCard(ownerID, vetID uint) error {
+	v := &validator{}
+	v.positiveID("owner_id", ownerID)
+	v.positiveID("vet_id", vetID)
+	return v.result()
+}
+
+func validatePetCommon(v *validator, pet models.Pet) {
+	if v.required("animal_type", pet.AnimalType) {
+		v.maxLen("animal_type", pet.AnimalType, maxShortText)
+	}
+	v.maxLen("breed", pet.Breed, maxShortText)
+	if v.required("name", pet.Name) {
+		v.maxLen("name", pet.Name, maxShortText)
+	}
+	if v.required("gender", pet.Gender) {
+		v.oneOf("gender", pet.Gender, GenderMale, GenderFemale)
+	}
+	if pet.BirthDate != "" {
+		v.date("birth_date", pet.BirthDate, false)
+	}
+	if pet.Microchip != "" && !microchipPattern.MatchString(pet.Microchip) {
+		v.add("microchip", CodeInvalidFormat, "microchip should be 15 digits")
+	}
+}
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+var (
+	doseUnits = []string{"mg", "mg/kg", "ml", "tablet", "capsule", "drop", "IU"}
+	routes    = []string{"oral", "iv", "im", "sc", "topical", "ophthalmic", "otic", "inhalation", "rectal"}
+)
+
+// ValidatePrescription validates full prescription state on create & replace
+func ValidatePrescription(prescription models.Prescription) error {
+	v := &validator{}
+
+	v.positiveID("medical_entry_id", prescription.MedicalEntryID)
+	if v.required("drug", prescription.Drug) {
+		v.maxLen("drug", prescription.Drug, maxShortText)
+	}
+	if prescription.Dose <= 0 {
+		v.add("dose", CodeMustBePositive, "dose should be > 0")
+	}
+	if v.required("unit", prescription.Unit) {
+		v.oneOf("unit", prescription.Unit, doseUnits...)
+	}
+	if v.required("route", prescription.Route) {
+		v.oneOf("route", prescription.Route, routes...)
+	}
+	if v.required("frequency", prescription.Frequency) {
+		v.maxLen("frequency", prescription.Frequency, 64)
+	}
+
+	startOK := v.required("start_date", prescription.StartDate) && v.date("start_date", prescription.StartDate, true)
+	endOK := prescription.EndDate == "" || v.date("end_date", prescription.EndDate, true)
+	if startOK && endOK && prescription.EndDate != "" {
+		start, _ := time.Parse(models.DateLayout, prescription.StartDate)
+		end, _ := time.Parse(models.DateLayout, prescription.EndDate)
+		if end.Before(start) {
+			v.add("end_date", CodeOutOfRange, "end_date should not be before start_date")
+		}
+	}
+
+	return v.result()
+}
+
+// ValidateDoseRange validates reference range of drug. Empty species is range for any species
+func ValidateDoseRange(doseRange models.DoseRange) error {
+	v := &validator{}
+
+	if v.required("drug", doseRange.Drug) {
+		v.maxLen("drug", doseRange.Drug, maxShortText)
+	}
+	v.maxLen("species", doseRange.Species, maxShortText)
+	if doseRange.MinMgPerKg < 0 {
+		v.add("min_mg_per_kg", CodeOutOfRange, "min_mg_per_kg should be >= 0")
+	}
+	if doseRange.MaxMgPerKg <= 0 {
+		v.add("max_mg_per_kg", CodeMustBePositive, "max_mg_per_kg should be > 0")
+	} else if doseRange.MaxMgPerKg < doseRange.MinMgPerKg {
+		v.add("max_mg_per_kg", CodeOutOfRange, "max_mg_per_kg should not be less than min_mg_per_kg")
+	}
+
+	return v.result()
+}
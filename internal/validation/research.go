@@ -0,0 +1,43 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+var researchStatuses = []string{
+	models.ResearchStatusCandidate, models.ResearchStatusConsented, models.ResearchStatusEnrolled,
+	models.ResearchStatusActive, models.ResearchStatusWithdrawn, models.ResearchStatusCompleted,
+}
+
+func ValidateResearchTransition(input models.ResearchTransitionDTO) error {
+	v := &validator{}
+
+	if v.required("status", input.Status) {
+		v.oneOf("status", input.Status, researchStatuses...)
+	}
+	if input.Status == models.ResearchStatusWithdrawn {
+		v.required("reason", input.Reason)
+	}
+	v.maxLen("reason", input.Reason, maxLongText)
+	v.positiveID("actor_id", input.ActorID)
+
+	return v.result()
+}
+
+// ValidateImportedResearchStatus validates historical research stage of imported pet, any stage is allowed
+func ValidateImportedResearchStatus(status string) error {
+	v := &validator{}
+	if status != "" {
+		v.oneOf("research_status", status, researchStatuses...)
+	}
+	return v.result()
+}
+
+// ValidatePetFilter validates research stage of pets query
+func ValidatePetFilter(filter models.PetReqFilter) error {
+	v := &validator{}
+
+	if filter.ResearchStatus != nil {
+		v.oneOf("research_status", *filter.ResearchStatus, researchStatuses...)
+	}
+
+	return v.result()
+}
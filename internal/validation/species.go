@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+)
+
+const (
+	maxSpeciesCode = 32
+	maxBreedCode   = 64
+)
+
+// catalogueCodePattern is lower snake case code like guinea_pig
+var catalogueCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func ValidateSpecies(species models.Species) error {
+	v := &validator{}
+
+	validateCatalogueCode(v, "code", species.Code, maxSpeciesCode)
+	validateLocalizedNames(v, species.Names)
+	for _, alias := range species.Aliases {
+		if v.required("aliases", alias) {
+			v.maxLen("aliases", alias, maxShortText)
+		}
+	}
+
+	return v.result()
+}
+
+func ValidateBreed(breed models.Breed) error {
+	v := &validator{}
+
+	validateCatalogueCode(v, "code", breed.Code, maxBreedCode)
+	validateLocalizedNames(v, breed.Names)
+
+	return v.result()
+}
+
+func validateCatalogueCode(v *validator, field, code string, max int) {
+	if !v.required(field, code) {
+		return
+	}
+	v.maxLen(field, code, max)
+	if !catalogueCodePattern.MatchString(code) {
+		v.add(field, CodeInvalidFormat, field+" should be lower snake case like guinea_pig")
+	}
+}
+
+func validateLocalizedNames(v *validator, names map[string]string) {
+	if len(names) == 0 {
+		v.add("names", CodeRequired, "names should have at least one language")
+		return
+	}
+	for lang, name := range names {
+		if v.required("names."+lang, name) {
+			v.maxLen("names."+lang, name, maxShortText)
+		}
+	}
+}
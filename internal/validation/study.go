@@ -0,0 +1,51 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+func ValidateStudy(study models.Study) error {
+	v := &validator{}
+
+	if v.required("title", study.Title) {
+		v.maxLen("title", study.Title, maxShortText)
+	}
+	if v.required("protocol_id", study.ProtocolID) {
+		v.maxLen("protocol_id", study.ProtocolID, 64)
+	}
+	v.positiveID("principal_vet_id", study.PrincipalVetID)
+	v.maxLen("inclusion_criteria", study.InclusionCriteria, maxLongText)
+
+	startOK := v.required("start_date", study.StartDate) && v.date("start_date", study.StartDate, true)
+	endOK := study.EndDate == "" || v.date("end_date", study.EndDate, true)
+	// YYYY-MM-DD dates compare as strings
+	if startOK && endOK && study.EndDate != "" && study.EndDate < study.StartDate {
+		v.add("end_date", CodeOutOfRange, "end_date should not be before start_date")
+	}
+
+	return v.result()
+}
+
+func ValidateEnrollingPet(input models.EnrollingPetDTO) error {
+	v := &validator{}
+
+	v.positiveID("pet_id", input.PetID)
+	if v.required("arm", input.Arm) {
+		v.maxLen("arm", input.Arm, 64)
+	}
+	if v.required("consent_date", input.ConsentDate) {
+		v.date("consent_date", input.ConsentDate, false)
+	}
+	v.positiveID("actor_id", input.ActorID)
+
+	return v.result()
+}
+
+func ValidateWithdrawingPet(input models.WithdrawingPetDTO) error {
+	v := &validator{}
+
+	if v.required("reason", input.Reason) {
+		v.maxLen("reason", input.Reason, maxLongText)
+	}
+	v.positiveID("actor_id", input.ActorID)
+
+	return v.result()
+}
@@ -0,0 +1,51 @@
+package validation
+
+import "github.com/vet-clinic-back/info-service/internal/models"
+
+const (
+	maxLotNumber = 64
+	// maxRevaccinationMonths is 10 years
+	maxRevaccinationMonths = 120
+)
+
+func ValidateCreatingVaccine(vaccine models.Vaccine) error {
+	v := &validator{}
+
+	if v.required("name", vaccine.Name) {
+		v.maxLen("name", vaccine.Name, maxShortText)
+	}
+	if v.required("species", vaccine.Species) {
+		v.maxLen("species", vaccine.Species, maxShortText)
+	}
+	if vaccine.RevaccinationMonths > maxRevaccinationMonths {
+		v.add("revaccination_months", CodeInvalidFormat, "revaccination_months should be at most 120")
+	}
+
+	return v.result()
+}
+
+func ValidateCreatingVaccination(vaccination models.Vaccination) error {
+	v := &validator{}
+
+	v.positiveID("vaccine_id", vaccination.VaccineID)
+	v.positiveID("vet_id", vaccination.VetID)
+	if v.required("lot_number", vaccination.LotNumber) {
+		v.maxLen("lot_number", vaccination.LotNumber, maxLotNumber)
+	}
+	if v.required("administered_at", vaccination.AdministeredAt) {
+		v.date("administered_at", vaccination.AdministeredAt, false)
+	}
+
+	return v.result()
+}
+
+// ValidateVaccinationDueFilter validates status of due vaccinations query
+func ValidateVaccinationDueFilter(filter models.VaccinationDueReqFilter) error {
+	v := &validator{}
+
+	if v.required("status", filter.Status) {
+		v.oneOf("status", filter.Status, models.VaccinationDueOverdue, models.VaccinationDueUpcoming)
+	}
+
+	return v.result()
+}
@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// Stable error codes. Clients rely on them, do not rename
+const (
+	CodeRequired        = "required"
+	CodeInvalidEnum     = "invalid_enum"
+	CodeMustBePositive  = "must_be_positive"
+	CodeInvalidFormat   = "invalid_format"
+	CodeTooLong         = "too_long"
+	CodeOutOfRange      = "out_of_range"
+	CodeAllergyConflict = "allergy_conflict"
+	CodeDoseUnchecked   = "dose_unchecked"
+)
+
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// Errors is list of field errors. nil Errors means input is valid
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message))
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// validator collects field errors of one dto
+type validator struct {
+	errs Errors
+}
+
+func (v *validator) add(field, code, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, CodeRequired, field+" is required")
+		return false
+	}
+	return true
+}
+
+func (v *validator) maxLen(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, CodeTooLong, fmt.Sprintf("%s should be at most %d characters", field, max))
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, CodeInvalidEnum, fmt.Sprintf("%s should be one of: %s", field, strings.Join(allowed, ", ")))
+}
+
+func (v *validator) positiveID(field string, value uint) {
+	if value == 0 {
+		v.add(field, CodeRequired, field+" is required")
+	}
+}
+
+// date checks YYYY-MM-DD date. Future dates are rejected unless allowFuture
+func (v *validator) date(field, value string, allowFuture bool) bool {
+	parsed, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		v.add(field, CodeInvalidFormat, field+" should be date YYYY-MM-DD")
+		return false
+	}
+	if !allowFuture && parsed.After(time.Now()) {
+		v.add(field, CodeInvalidFormat, field+" should not be in future")
+		return false
+	}
+	return true
+}
+
+// result returns nil interface on success so callers can check err != nil
+func (v *validator) result() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Is makes validation errors match errs.ErrValidation
+func (e Errors) Is(target error) bool {
+	return target == errs.ErrValidation
+}
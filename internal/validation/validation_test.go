@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vet-clinic-back/info-service/internal/models"
+	"github.com/vet-clinic-back/info-service/internal/service/errs"
+)
+
+// fieldCodes returns code of each invalid field, nil for valid input
+func fieldCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var fieldErrs Errors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("error %v is not validation.Errors", err)
+	}
+	if !errors.Is(err, errs.ErrValidation) {
+		t.Errorf("error %v does not match errs.ErrValidation", err)
+	}
+	codes := map[string]string{}
+	for _, fieldErr := range fieldErrs {
+		codes[fieldErr.Field] = fieldErr.Code
+	}
+	return codes
+}
+
+func checkCodes(t *testing.T, got, want map[string]string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("codes = %v, want %v", got, want)
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("code of %s = %q, want %q", field, got[field], code)
+		}
+	}
+}
+
+func TestValidateCreatingPet(t *testing.T) {
+	valid := models.Pet{
+		AnimalType: "cat", Name: "Tom", Gender: GenderMale, BirthDate: "2020-01-02", Weight: 4.5,
+		Condition: "good", Behavior: "calm",
+	}
+
+	tests := []struct {
+		name string
+		edit func(pet *models.Pet)
+		want map[string]string
+	}{
+		{"valid", func(pet *models.Pet) {}, nil},
+		{"age instead of birth date", func(pet *models.Pet) { pet.BirthDate, pet.Age = "", 3 }, nil},
+		{"15 digit microchip", func(pet *models.Pet) { pet.Microchip = "643094100123456" }, nil},
+		{"candidate", func(pet *models.Pet) { pet.ResearchStatus = models.ResearchStatusCandidate }, nil},
+		{"no name", func(pet *models.Pet) { pet.Name = "  " }, map[string]string{"name": CodeRequired}},
+		{"long name", func(pet *models.Pet) { pet.Name = strings.Repeat("я", maxShortText+1) },
+			map[string]string{"name": CodeTooLong}},
+		{"name at limit", func(pet *models.Pet) { pet.Name = strings.Repeat("я", maxShortText) }, nil},
+		{"unknown gender", func(pet *models.Pet) { pet.Gender = "male" }, map[string]string{"gender": CodeInvalidEnum}},
+		{"no birth date or age", func(pet *models.Pet) { pet.BirthDate = "" },
+			map[string]string{"birth_date": CodeRequired}},
+		{"invalid birth date", func(pet *models.Pet) { pet.BirthDate = "02.01.2020" },
+			map[string]string{"birth_date": CodeInvalidFormat}},
+		{"future birth date", func(pet *models.Pet) { pet.BirthDate = "2999-01-01" },
+			map[string]string{"birth_date": CodeInvalidFormat}},
+		{"invalid microchip", func(pet *models.Pet) { pet.Microchip = "12-34" },
+			map[string]string{"microchip": CodeInvalidFormat}},
+		{"no weight", func(pet *models.Pet) { pet.Weight = 0 }, map[string]string{"weight": CodeMustBePositive}},
+		{"later research status", func(pet *models.Pet) { pet.ResearchStatus = models.ResearchStatusActive },
+			map[string]string{"research_status": CodeInvalidEnum}},
+		{"all errors are collected", func(pet *models.Pet) { *pet = models.Pet{} }, map[string]string{
+			"animal_type": CodeRequired, "name": CodeRequired, "gender": CodeRequired, "birth_date": CodeRequired,
+			"weight": CodeMustBePositive, "condition": CodeRequired, "behavior": CodeRequired,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := valid
+			tt.edit(&pet)
+			checkCodes(t, fieldCodes(t, ValidateCreatingPet(pet)), tt.want)
+		})
+	}
+}
+
+func TestValidateUpdatingPet(t *testing.T) {
+	valid := models.Pet{AnimalType: "cat", Name: "Tom", Gender: GenderFemale}
+
+	tests := []struct {
+		name string
+		edit func(pet *models.Pet)
+		want map[string]string
+	}{
+		{"unknown birth date & weight", func(pet *models.Pet) {}, nil},
+		{"any research status", func(pet *models.Pet) { pet.ResearchStatus = models.ResearchStatusActive }, nil},
+		{"negative weight", func(pet *models.Pet) { pet.Weight = -1 }, map[string]string{"weight": CodeMustBePositive}},
+		{"unknown research status", func(pet *models.Pet) { pet.ResearchStatus = "done" },
+			map[string]string{"research_status": CodeInvalidEnum}},
+		{"long condition", func(pet *models.Pet) { pet.Condition = strings.Repeat("a", maxLongText+1) },
+			map[string]string{"condition": CodeTooLong}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := valid
+			tt.edit(&pet)
+			checkCodes(t, fieldCodes(t, ValidateUpdatingPet(pet)), tt.want)
+		})
+	}
+
+	checkCodes(t, fieldCodes(t, ValidateBatchUpdatingPet(valid)), map[string]string{"id": CodeRequired})
+}
+
+func TestValidateCreatingMedEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry models.CreatingMedEntry
+		want  map[string]string
+	}{
+		{"valid", models.CreatingMedEntry{MedicalEntry: models.MedicalEntry{
+			MedicalRecordID: 1, VetID: 2, FollowUpAt: "2999-01-01",
+		}}, nil},
+		{"no ids", models.CreatingMedEntry{}, map[string]string{
+			"medical_record_id": CodeRequired, "vet_id": CodeRequired,
+		}},
+		{"invalid follow up", models.CreatingMedEntry{MedicalEntry: models.MedicalEntry{
+			MedicalRecordID: 1, VetID: 2, FollowUpAt: "tomorrow",
+		}}, map[string]string{"follow_up_at": CodeInvalidFormat}},
+		{"long override reason", models.CreatingMedEntry{
+			MedicalEntry:          models.MedicalEntry{MedicalRecordID: 1, VetID: 2},
+			AllergyOverrideReason: strings.Repeat("a", maxLongText+1),
+		}, map[string]string{"allergy_override_reason": CodeTooLong}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkCodes(t, fieldCodes(t, ValidateCreatingMedEntry(tt.entry)), tt.want)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	v := &validator{}
+	if err := v.result(); err != nil {
+		t.Fatalf("result of valid input = %v, want nil", err)
+	}
+
+	v.add("name", CodeRequired, "name is required")
+	v.add("weight", CodeMustBePositive, "weight should be > 0")
+	want := "validation failed: name: name is required; weight: weight should be > 0"
+	if got := v.result().Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
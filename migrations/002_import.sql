@@ -0,0 +1,23 @@
+-- bulk import jobs. processed_rows is checkpoint to resume failed job
+CREATE TABLE IF NOT EXISTS import_job (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL,
+    format VARCHAR(16) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    file_path TEXT NOT NULL,
+    mapping JSONB NOT NULL DEFAULT '{}',
+    default_vet_id INTEGER REFERENCES veterinarian(id),
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    report JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- pets created by import job, key is pet reference from source system
+CREATE TABLE IF NOT EXISTS import_pet_ref (
+    job_id INTEGER NOT NULL REFERENCES import_job(id) ON DELETE CASCADE,
+    ref VARCHAR(512) NOT NULL,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    PRIMARY KEY (job_id, ref)
+);
@@ -0,0 +1,38 @@
+-- microchip number, lab analyzers identify patients by it
+ALTER TABLE pet ADD COLUMN IF NOT EXISTS microchip VARCHAR(32) UNIQUE;
+
+-- HL7 messages received over MLLP. Messages with unmatched patient wait in review queue
+CREATE TABLE IF NOT EXISTS lab_message (
+    id SERIAL PRIMARY KEY,
+    sender VARCHAR(255) NOT NULL,
+    control_id VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    pet_id INTEGER REFERENCES pet(id) ON DELETE SET NULL,
+    patient_ids TEXT NOT NULL DEFAULT '',
+    review_reason TEXT NOT NULL DEFAULT '',
+    raw TEXT NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    UNIQUE (sender, control_id)
+);
+
+CREATE INDEX IF NOT EXISTS lab_message_status_idx ON lab_message (status);
+
+CREATE TABLE IF NOT EXISTS lab_result (
+    id SERIAL PRIMARY KEY,
+    message_id INTEGER NOT NULL REFERENCES lab_message(id) ON DELETE CASCADE,
+    medical_record_id INTEGER NOT NULL REFERENCES medical_record(id) ON DELETE CASCADE,
+    panel_code VARCHAR(64) NOT NULL DEFAULT '',
+    panel_name TEXT NOT NULL DEFAULT '',
+    code VARCHAR(64) NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    value_type VARCHAR(8) NOT NULL DEFAULT '',
+    value TEXT NOT NULL DEFAULT '',
+    units VARCHAR(64) NOT NULL DEFAULT '',
+    reference_range VARCHAR(64) NOT NULL DEFAULT '',
+    abnormal_flag VARCHAR(8) NOT NULL DEFAULT '',
+    result_status VARCHAR(4) NOT NULL DEFAULT '',
+    observed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS lab_result_medical_record_idx ON lab_result (medical_record_id, observed_at);
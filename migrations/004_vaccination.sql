@@ -0,0 +1,21 @@
+-- vaccine catalogue. revaccination_months = 0 means vaccine is given once
+CREATE TABLE IF NOT EXISTS vaccine (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    species VARCHAR(128) NOT NULL,
+    revaccination_months INTEGER NOT NULL DEFAULT 0 CHECK (revaccination_months >= 0),
+    UNIQUE (name, species)
+);
+
+-- administered doses. Next due date is computed from latest dose & catalogue interval
+CREATE TABLE IF NOT EXISTS vaccination (
+    id SERIAL PRIMARY KEY,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    vaccine_id INTEGER NOT NULL REFERENCES vaccine(id),
+    medical_entry_id INTEGER REFERENCES medical_entry(id) ON DELETE SET NULL,
+    veterinarian_id INTEGER NOT NULL REFERENCES veterinarian(id),
+    lot_number VARCHAR(64) NOT NULL,
+    administered_at DATE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS vaccination_pet_vaccine_idx ON vaccination (pet_id, vaccine_id, administered_at DESC);
@@ -0,0 +1,29 @@
+-- reference dose ranges in mg/kg. Empty species is range for any species, species specific range wins
+CREATE TABLE IF NOT EXISTS drug_dose_range (
+    id SERIAL PRIMARY KEY,
+    drug VARCHAR(128) NOT NULL,
+    species VARCHAR(128) NOT NULL DEFAULT '',
+    min_mg_per_kg NUMERIC(10, 4) NOT NULL CHECK (min_mg_per_kg >= 0),
+    max_mg_per_kg NUMERIC(10, 4) NOT NULL,
+    CHECK (min_mg_per_kg <= max_mg_per_kg)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS drug_dose_range_lower_idx ON drug_dose_range (lower(drug), lower(species));
+
+CREATE TABLE IF NOT EXISTS prescription (
+    id SERIAL PRIMARY KEY,
+    medical_entry_id INTEGER NOT NULL REFERENCES medical_entry(id) ON DELETE CASCADE,
+    drug VARCHAR(128) NOT NULL,
+    dose NUMERIC(10, 4) NOT NULL CHECK (dose > 0),
+    unit VARCHAR(16) NOT NULL,
+    route VARCHAR(32) NOT NULL,
+    frequency VARCHAR(64) NOT NULL,
+    duration_days INTEGER,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    -- vet confirmed dose outside of reference range
+    dose_override BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS prescription_medical_entry_idx ON prescription (medical_entry_id);
@@ -0,0 +1,32 @@
+-- known allergies & chronic conditions of pet
+CREATE TABLE IF NOT EXISTS allergy (
+    id SERIAL PRIMARY KEY,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    substance VARCHAR(128) NOT NULL,
+    reaction TEXT NOT NULL DEFAULT '',
+    severity VARCHAR(16) NOT NULL,
+    recorded_by INTEGER NOT NULL REFERENCES veterinarian(id),
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS allergy_pet_idx ON allergy (pet_id);
+
+-- synonym groups of allergens, e.g. substance penicillin has synonyms amoxicillin & ampicillin.
+-- All terms of group match each other. Maintained by clinic
+CREATE TABLE IF NOT EXISTS allergen_synonym (
+    substance VARCHAR(128) NOT NULL,
+    synonym VARCHAR(128) NOT NULL,
+    PRIMARY KEY (substance, synonym)
+);
+
+-- med entries saved despite high severity allergy match
+CREATE TABLE IF NOT EXISTS allergy_override (
+    id SERIAL PRIMARY KEY,
+    medical_entry_id INTEGER NOT NULL REFERENCES medical_entry(id) ON DELETE CASCADE,
+    allergy_id INTEGER NOT NULL REFERENCES allergy(id) ON DELETE CASCADE,
+    veterinarian_id INTEGER REFERENCES veterinarian(id),
+    matched_term VARCHAR(128) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
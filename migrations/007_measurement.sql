@@ -0,0 +1,17 @@
+-- weigh-ins of pet. pet.weight is kept equal to weight of latest measurement
+CREATE TABLE IF NOT EXISTS measurement (
+    id SERIAL PRIMARY KEY,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    measured_at DATE NOT NULL,
+    weight DOUBLE PRECISION NOT NULL CHECK (weight > 0),
+    -- 9-point body condition score, NULL if not assessed
+    body_condition_score SMALLINT CHECK (body_condition_score BETWEEN 1 AND 9),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS measurement_pet_idx ON measurement (pet_id, measured_at);
+
+-- current weights become first measurements, date of weigh-in is unknown
+INSERT INTO measurement (pet_id, measured_at, weight)
+SELECT id, CURRENT_DATE, weight FROM pet
+WHERE weight > 0 AND NOT EXISTS (SELECT 1 FROM measurement WHERE measurement.pet_id = pet.id);
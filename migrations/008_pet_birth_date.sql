@@ -0,0 +1,15 @@
+-- age is computed from birth date on read, age column is not written anymore
+ALTER TABLE pet ADD COLUMN IF NOT EXISTS birth_date DATE;
+ALTER TABLE pet ADD COLUMN IF NOT EXISTS birth_date_estimated BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE pet ALTER COLUMN age DROP NOT NULL;
+
+-- pet has no creation time, its first medical entry is the closest one. Pets without entries count from today
+UPDATE pet SET
+    birth_date = (COALESCE(
+        (SELECT min(medical_entry.entry_date) FROM medical_entry
+            JOIN medical_record ON medical_record.id = medical_entry.medical_record_id
+            WHERE medical_record.pet_id = pet.id),
+        CURRENT_TIMESTAMP
+    ) - make_interval(years => pet.age::integer))::date,
+    birth_date_estimated = true
+WHERE birth_date IS NULL AND age > 0;
@@ -0,0 +1,99 @@
+-- species & breed catalogue. pet.animal_type is species code, pet.breed is breed code of the species
+CREATE TABLE IF NOT EXISTS species (
+    code VARCHAR(32) PRIMARY KEY,
+    -- localized names, e.g. {"en": "Cat", "ru": "Кошка"}
+    names JSONB NOT NULL DEFAULT '{}'
+);
+
+-- lower cased free text names mapped to species
+CREATE TABLE IF NOT EXISTS species_alias (
+    alias VARCHAR(128) PRIMARY KEY,
+    species_code VARCHAR(32) NOT NULL REFERENCES species(code) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS breed (
+    species_code VARCHAR(32) NOT NULL REFERENCES species(code) ON DELETE CASCADE,
+    code VARCHAR(64) NOT NULL,
+    names JSONB NOT NULL DEFAULT '{}',
+    PRIMARY KEY (species_code, code)
+);
+
+INSERT INTO species (code, names) VALUES
+    ('cat', '{"en": "Cat", "ru": "Кошка"}'),
+    ('dog', '{"en": "Dog", "ru": "Собака"}'),
+    ('rabbit', '{"en": "Rabbit", "ru": "Кролик"}'),
+    ('ferret', '{"en": "Ferret", "ru": "Хорёк"}'),
+    ('guinea_pig', '{"en": "Guinea pig", "ru": "Морская свинка"}'),
+    ('hamster', '{"en": "Hamster", "ru": "Хомяк"}'),
+    ('rat', '{"en": "Rat", "ru": "Крыса"}'),
+    ('parrot', '{"en": "Parrot", "ru": "Попугай"}'),
+    ('horse', '{"en": "Horse", "ru": "Лошадь"}')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO species_alias (alias, species_code) VALUES
+    ('cats', 'cat'), ('kitten', 'cat'), ('feline', 'cat'), ('кот', 'cat'), ('котёнок', 'cat'), ('котенок', 'cat'),
+    ('dogs', 'dog'), ('puppy', 'dog'), ('canine', 'dog'), ('пёс', 'dog'), ('пес', 'dog'), ('щенок', 'dog'),
+    ('хорек', 'ferret'), ('морская свинка', 'guinea_pig'), ('guinea-pig', 'guinea_pig'),
+    ('конь', 'horse'), ('крольчиха', 'rabbit')
+ON CONFLICT (alias) DO NOTHING;
+
+INSERT INTO breed (species_code, code, names) VALUES
+    ('cat', 'mixed', '{"en": "Mixed", "ru": "Беспородная"}'),
+    ('cat', 'british_shorthair', '{"en": "British Shorthair", "ru": "Британская короткошёрстная"}'),
+    ('cat', 'maine_coon', '{"en": "Maine Coon", "ru": "Мейн-кун"}'),
+    ('cat', 'siamese', '{"en": "Siamese", "ru": "Сиамская"}'),
+    ('cat', 'persian', '{"en": "Persian", "ru": "Персидская"}'),
+    ('cat', 'sphynx', '{"en": "Sphynx", "ru": "Сфинкс"}'),
+    ('dog', 'mixed', '{"en": "Mixed", "ru": "Метис"}'),
+    ('dog', 'labrador_retriever', '{"en": "Labrador Retriever", "ru": "Лабрадор-ретривер"}'),
+    ('dog', 'german_shepherd', '{"en": "German Shepherd", "ru": "Немецкая овчарка"}'),
+    ('dog', 'yorkshire_terrier', '{"en": "Yorkshire Terrier", "ru": "Йоркширский терьер"}'),
+    ('dog', 'french_bulldog', '{"en": "French Bulldog", "ru": "Французский бульдог"}'),
+    ('dog', 'dachshund', '{"en": "Dachshund", "ru": "Такса"}')
+ON CONFLICT (species_code, code) DO NOTHING;
+
+ALTER TABLE pet ADD COLUMN IF NOT EXISTS breed VARCHAR(64);
+
+-- species code of free text: code, alias or any localized name, case-insensitive
+CREATE OR REPLACE FUNCTION species_code_of(value TEXT) RETURNS VARCHAR AS $$
+    SELECT species.code FROM species
+    WHERE lower(species.code) = lower(trim(value))
+        OR EXISTS (SELECT 1 FROM species_alias WHERE species_alias.species_code = species.code
+            AND species_alias.alias = lower(trim(value)))
+        OR EXISTS (SELECT 1 FROM jsonb_each_text(species.names) n WHERE lower(n.value) = lower(trim(value)))
+    ORDER BY species.code
+    LIMIT 1
+$$ LANGUAGE SQL STABLE;
+
+UPDATE pet SET animal_type = species_code_of(animal_type)
+WHERE species_code_of(animal_type) IS NOT NULL AND animal_type <> species_code_of(animal_type);
+UPDATE vaccine SET species = species_code_of(species)
+WHERE species_code_of(species) IS NOT NULL AND species <> species_code_of(species);
+UPDATE drug_dose_range SET species = species_code_of(species)
+WHERE species <> '' AND species_code_of(species) IS NOT NULL AND species <> species_code_of(species);
+
+-- values left are listed by GET /info/v1/species/unmapped and should be fixed by hand
+DO $$
+DECLARE
+    unmapped RECORD;
+BEGIN
+    FOR unmapped IN
+        SELECT animal_type, count(*) AS pets FROM pet
+        WHERE animal_type NOT IN (SELECT code FROM species) GROUP BY animal_type ORDER BY animal_type
+    LOOP
+        RAISE NOTICE 'unmapped animal_type "%": % pets', unmapped.animal_type, unmapped.pets;
+    END LOOP;
+END $$;
+
+-- not validated so unmapped pets stay readable, new & updated pets are checked
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'pet_species_fk') THEN
+        ALTER TABLE pet ADD CONSTRAINT pet_species_fk
+            FOREIGN KEY (animal_type) REFERENCES species(code) NOT VALID;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'pet_breed_fk') THEN
+        ALTER TABLE pet ADD CONSTRAINT pet_breed_fk
+            FOREIGN KEY (animal_type, breed) REFERENCES breed(species_code, code);
+    END IF;
+END $$;
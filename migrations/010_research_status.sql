@@ -0,0 +1,27 @@
+-- research status changes of pet. Status itself is kept in pet.research_status
+CREATE TABLE IF NOT EXISTS research_status_transition (
+    id SERIAL PRIMARY KEY,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    from_status VARCHAR(128) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    -- veterinarian who made transition, NULL for migrated statuses
+    actor_id INTEGER REFERENCES veterinarian(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS research_status_transition_pet_idx ON research_status_transition (pet_id, created_at);
+
+-- free text statuses that are not stages become candidate, old value is kept in history
+INSERT INTO research_status_transition (pet_id, from_status, to_status, reason)
+SELECT id, research_status, 'candidate', 'migrated from free text status'
+FROM pet
+WHERE lower(trim(research_status)) NOT IN ('candidate', 'consented', 'enrolled', 'active', 'withdrawn', 'completed');
+
+UPDATE pet SET research_status = CASE
+    WHEN lower(trim(research_status)) IN ('candidate', 'consented', 'enrolled', 'active', 'withdrawn', 'completed')
+        THEN lower(trim(research_status))
+    ELSE 'candidate'
+END;
+
+ALTER TABLE pet ALTER COLUMN research_status SET DEFAULT 'candidate';
@@ -0,0 +1,27 @@
+-- research studies pets are enrolled in
+CREATE TABLE IF NOT EXISTS study (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(128) NOT NULL,
+    protocol_id VARCHAR(64) NOT NULL UNIQUE,
+    principal_vet_id INTEGER NOT NULL REFERENCES veterinarian(id),
+    start_date DATE NOT NULL,
+    -- NULL for open ended study
+    end_date DATE,
+    inclusion_criteria TEXT NOT NULL DEFAULT '',
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- pet can be enrolled in study once, withdrawn enrollment is kept for analysis
+CREATE TABLE IF NOT EXISTS study_enrollment (
+    id SERIAL PRIMARY KEY,
+    study_id INTEGER NOT NULL REFERENCES study(id) ON DELETE CASCADE,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    arm VARCHAR(64) NOT NULL,
+    consent_date DATE NOT NULL,
+    enrolled_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    withdrawn_at TIMESTAMP,
+    withdrawal_reason TEXT NOT NULL DEFAULT '',
+    UNIQUE (study_id, pet_id)
+);
+
+CREATE INDEX IF NOT EXISTS study_enrollment_active_pet_idx ON study_enrollment (pet_id) WHERE withdrawn_at IS NULL;
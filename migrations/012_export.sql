@@ -0,0 +1,14 @@
+-- background export jobs. File is written to export dir, criteria are filters of exported data
+CREATE TABLE IF NOT EXISTS export_job (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    format VARCHAR(16) NOT NULL,
+    criteria JSONB NOT NULL DEFAULT '{}',
+    file_path TEXT NOT NULL DEFAULT '',
+    exported_pets INTEGER NOT NULL DEFAULT 0,
+    exported_entries INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,19 @@
+-- visits booked at front desk. Completed appointment is linked to medical entry it created
+CREATE TABLE IF NOT EXISTS appointment (
+    id SERIAL PRIMARY KEY,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    veterinarian_id INTEGER NOT NULL REFERENCES veterinarian(id),
+    clinic_number VARCHAR(64) NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'booked',
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    medical_entry_id INTEGER REFERENCES medical_entry(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS appointment_vet_time_idx ON appointment (veterinarian_id, starts_at);
+CREATE INDEX IF NOT EXISTS appointment_pet_idx ON appointment (pet_id);
@@ -0,0 +1,55 @@
+-- clinic branches. Times of opening hours & vet schedules are local to time_zone of clinic
+CREATE TABLE IF NOT EXISTS clinic (
+    number VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(128) NOT NULL DEFAULT '',
+    -- IANA time zone, e.g. Europe/Moscow
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC'
+);
+
+INSERT INTO clinic (number)
+SELECT DISTINCT clinic_number::text FROM veterinarian WHERE clinic_number IS NOT NULL AND clinic_number::text <> ''
+ON CONFLICT DO NOTHING;
+
+-- weekday is 0 for Sunday. Clinic without hours is not limited by them
+CREATE TABLE IF NOT EXISTS clinic_hours (
+    clinic_number VARCHAR(64) NOT NULL REFERENCES clinic(number) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time > start_time),
+    PRIMARY KEY (clinic_number, weekday, start_time)
+);
+
+-- weekly working hours of vet in clinic of vet
+CREATE TABLE IF NOT EXISTS vet_schedule (
+    veterinarian_id INTEGER NOT NULL REFERENCES veterinarian(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time > start_time),
+    PRIMARY KEY (veterinarian_id, weekday, start_time)
+);
+
+-- days vet does not work, both dates inclusive
+CREATE TABLE IF NOT EXISTS vet_schedule_exception (
+    id SERIAL PRIMARY KEY,
+    veterinarian_id INTEGER NOT NULL REFERENCES veterinarian(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL CHECK (end_date >= start_date),
+    kind VARCHAR(16) NOT NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS vet_schedule_exception_vet_idx ON vet_schedule_exception (veterinarian_id, start_date);
+
+-- time of vet reserved by staff, optionally for pet
+CREATE TABLE IF NOT EXISTS time_block (
+    id SERIAL PRIMARY KEY,
+    veterinarian_id INTEGER NOT NULL REFERENCES veterinarian(id) ON DELETE CASCADE,
+    pet_id INTEGER REFERENCES pet(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS time_block_vet_time_idx ON time_block (veterinarian_id, starts_at);
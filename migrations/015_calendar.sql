@@ -0,0 +1,17 @@
+-- recheck date recommended at medical entry
+ALTER TABLE medical_entry ADD COLUMN IF NOT EXISTS follow_up_at DATE;
+
+CREATE INDEX IF NOT EXISTS medical_entry_follow_up_idx ON medical_entry (follow_up_at) WHERE follow_up_at IS NOT NULL;
+
+-- secret links to iCalendar feeds of follow-ups. Only SHA-256 of token is stored
+CREATE TABLE IF NOT EXISTS calendar_feed (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL,
+    subject_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    CHECK (kind IN ('vet', 'owner'))
+);
+
+CREATE INDEX IF NOT EXISTS calendar_feed_subject_idx ON calendar_feed (kind, subject_id);
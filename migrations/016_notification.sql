@@ -0,0 +1,48 @@
+-- channels owner wants notifications in. Owner without row gets russian emails
+CREATE TABLE IF NOT EXISTS notification_preference (
+    owner_id INTEGER PRIMARY KEY REFERENCES owner(id) ON DELETE CASCADE,
+    language VARCHAR(2) NOT NULL DEFAULT 'ru',
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    sms_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    opted_out BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (language IN ('ru', 'en'))
+);
+
+-- reminder is sent to owner when due. Reminders of med entries are created by scanner once per entry & kind
+CREATE TABLE IF NOT EXISTS reminder (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+    pet_id INTEGER NOT NULL REFERENCES pet(id) ON DELETE CASCADE,
+    medical_entry_id INTEGER REFERENCES medical_entry(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    due_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP,
+    UNIQUE (medical_entry_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS reminder_due_idx ON reminder (due_at) WHERE status = 'pending';
+
+-- delivery of reminder over one channel. Failed sends are retried until attempts run out
+CREATE TABLE IF NOT EXISTS notification (
+    id SERIAL PRIMARY KEY,
+    reminder_id INTEGER NOT NULL REFERENCES reminder(id) ON DELETE CASCADE,
+    owner_id INTEGER NOT NULL REFERENCES owner(id) ON DELETE CASCADE,
+    channel VARCHAR(16) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    language VARCHAR(2) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notification_pending_idx ON notification (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS notification_owner_idx ON notification (owner_id);
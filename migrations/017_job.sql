@@ -0,0 +1,20 @@
+-- periodic jobs. next_run_at is shared by replicas so each run happens once
+CREATE TABLE IF NOT EXISTS scheduled_job (
+    name VARCHAR(64) PRIMARY KEY,
+    schedule VARCHAR(64) NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS job_run (
+    id SERIAL PRIMARY KEY,
+    job_name VARCHAR(64) NOT NULL,
+    trigger VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'running',
+    host VARCHAR(255) NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMPTZ,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS job_run_name_idx ON job_run (job_name, started_at DESC);
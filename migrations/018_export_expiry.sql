@@ -0,0 +1,4 @@
+-- files of completed exports are deleted after expires_at, job is kept with expired status
+ALTER TABLE export_job ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS export_job_expires_at_idx ON export_job (expires_at) WHERE status = 'completed';
@@ -0,0 +1,5 @@
+-- attempt is incremented when job is claimed by worker, checkpoints of previous worker are rejected then
+ALTER TABLE import_job ADD COLUMN IF NOT EXISTS attempt INTEGER NOT NULL DEFAULT 0;
+
+-- owners are found by email case insensitively
+CREATE INDEX IF NOT EXISTS owner_lower_email_idx ON owner (lower(email));
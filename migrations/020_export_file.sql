@@ -0,0 +1,11 @@
+-- files of completed exports are kept in database, so any replica can serve & delete them. Export request asked for
+-- local disk storage, see note in README
+CREATE TABLE IF NOT EXISTS export_file_chunk (
+    job_id INTEGER NOT NULL REFERENCES export_job(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    data BYTEA NOT NULL,
+    PRIMARY KEY (job_id, seq)
+);
+
+ALTER TABLE export_job ADD COLUMN IF NOT EXISTS file_name VARCHAR(128) NOT NULL DEFAULT '';
+ALTER TABLE export_job ADD COLUMN IF NOT EXISTS file_size BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,25 @@
+-- starter reference ranges of common drugs, mg/kg per single dose. Clinic reviews them with
+-- GET/PUT /info/v1/dose-ranges, existing ranges are not overwritten
+INSERT INTO drug_dose_range (drug, species, min_mg_per_kg, max_mg_per_kg) VALUES
+    ('Amoxicillin', '', 10, 20),
+    ('Amoxicillin-clavulanate', '', 12.5, 25),
+    ('Cefalexin', '', 15, 30),
+    ('Doxycycline', '', 5, 10),
+    ('Enrofloxacin', 'dog', 5, 20),
+    ('Enrofloxacin', 'cat', 5, 5),
+    ('Metronidazole', '', 10, 15),
+    ('Meloxicam', 'dog', 0.1, 0.2),
+    ('Meloxicam', 'cat', 0.05, 0.1),
+    ('Carprofen', 'dog', 2, 4.4),
+    ('Robenacoxib', '', 1, 2.4),
+    ('Gabapentin', '', 5, 20),
+    ('Tramadol', 'dog', 2, 5),
+    ('Buprenorphine', '', 0.01, 0.03),
+    ('Maropitant', '', 1, 2),
+    ('Furosemide', '', 1, 4),
+    ('Prednisolone', '', 0.5, 2),
+    ('Famotidine', '', 0.5, 1),
+    ('Omeprazole', '', 0.7, 1),
+    ('Fenbendazole', '', 50, 50),
+    ('Praziquantel', '', 5, 7.5)
+ON CONFLICT (lower(drug), lower(species)) DO NOTHING;
@@ -0,0 +1,47 @@
+-- starter synonym groups of common drug allergens, english & russian names. Clinic reviews them with
+-- GET/PUT /info/v1/allergen-synonyms, existing synonyms are kept
+INSERT INTO allergen_synonym (substance, synonym) VALUES
+    ('penicillin', 'пенициллин'),
+    ('penicillin', 'amoxicillin'),
+    ('penicillin', 'амоксициллин'),
+    ('penicillin', 'amoxicillin-clavulanate'),
+    ('penicillin', 'ampicillin'),
+    ('penicillin', 'ампициллин'),
+    ('penicillin', 'benzylpenicillin'),
+    ('penicillin', 'бензилпенициллин'),
+    ('cephalosporin', 'цефалоспорин'),
+    ('cephalosporin', 'cefalexin'),
+    ('cephalosporin', 'cephalexin'),
+    ('cephalosporin', 'цефалексин'),
+    ('cephalosporin', 'cefovecin'),
+    ('cephalosporin', 'cefazolin'),
+    ('cephalosporin', 'цефазолин'),
+    ('cephalosporin', 'ceftriaxone'),
+    ('cephalosporin', 'цефтриаксон'),
+    ('fluoroquinolone', 'фторхинолон'),
+    ('fluoroquinolone', 'enrofloxacin'),
+    ('fluoroquinolone', 'энрофлоксацин'),
+    ('fluoroquinolone', 'marbofloxacin'),
+    ('fluoroquinolone', 'марбофлоксацин'),
+    ('fluoroquinolone', 'ciprofloxacin'),
+    ('fluoroquinolone', 'ципрофлоксацин'),
+    ('tetracycline', 'тетрациклин'),
+    ('tetracycline', 'doxycycline'),
+    ('tetracycline', 'доксициклин'),
+    ('tetracycline', 'oxytetracycline'),
+    ('tetracycline', 'окситетрациклин'),
+    ('sulfonamide', 'сульфаниламид'),
+    ('sulfonamide', 'sulfadiazine'),
+    ('sulfonamide', 'сульфадиазин'),
+    ('sulfonamide', 'sulfamethoxazole'),
+    ('sulfonamide', 'сульфаметоксазол'),
+    ('nsaid', 'нпвс'),
+    ('nsaid', 'meloxicam'),
+    ('nsaid', 'мелоксикам'),
+    ('nsaid', 'carprofen'),
+    ('nsaid', 'карпрофен'),
+    ('nsaid', 'robenacoxib'),
+    ('nsaid', 'робенакоксиб'),
+    ('nsaid', 'ketoprofen'),
+    ('nsaid', 'кетопрофен')
+ON CONFLICT (substance, synonym) DO NOTHING;
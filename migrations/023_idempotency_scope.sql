@@ -0,0 +1,13 @@
+-- expiry is compared with current time of any zone, so times are stored with time zone.
+-- Existing values are read in time zone of session
+ALTER TABLE idempotency_key
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ;
+
+-- keys are unique per client & route, scope is hash of both. Keys saved before get empty scope and expire
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS scope CHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_key DROP CONSTRAINT IF EXISTS idempotency_key_pkey;
+ALTER TABLE idempotency_key ADD PRIMARY KEY (scope, key);
+
+-- in progress key is leased, key of request that crashed is taken over after lease ends
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;